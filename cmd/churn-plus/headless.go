@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cloudboy-jh/churn-plus/internal/config"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/github"
+)
+
+// headlessOptions configures a run without the TUI
+type headlessOptions struct {
+	// FailOn overrides the project's fail_on_severity
+	FailOn string
+
+	// LogFormat is "text" or "json"
+	LogFormat string
+
+	// StreamFindings writes each finding to stdout as NDJSON as it is found
+	StreamFindings bool
+
+	// GitHubPR, as "owner/repo#N", is a pull request to post findings to
+	GitHubPR string
+
+	// Output is a file the report is also written to, in Format
+	Output string
+	Format string
+}
+
+// runHeadless scans the project, runs the pipeline to completion, and saves
+// the report, logging progress to stderr. With StreamFindings, stdout carries
+// only the NDJSON findings. It returns the process exit code.
+func runHeadless(projectRoot string, opts headlessOptions) int {
+	log, err := newProgressLog(os.Stderr, opts.LogFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitError
+	}
+
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		// An invalid config would silently run something else in CI
+		log.Error("failed to load config", err)
+		return exitError
+	}
+
+	factory := engine.NewFactory(cfg)
+
+	failOn, failOnSet := factory.FailOnSeverity()
+	if opts.FailOn != "" {
+		failOn, failOnSet = engine.ParseSeverity(opts.FailOn)
+		if !failOnSet {
+			log.Error("invalid --fail-on", fmt.Errorf("unknown severity %q", opts.FailOn))
+			return exitError
+		}
+	}
+
+	var format string
+	if opts.Output != "" {
+		if format, err = reportFormat(opts.Output, opts.Format); err != nil {
+			log.Error("invalid --format", err)
+			return exitError
+		}
+	}
+
+	// Check the pull request up front rather than after a long run
+	var pr github.PullRequest
+	token := os.Getenv("GITHUB_TOKEN")
+	if opts.GitHubPR != "" {
+		if pr, err = github.ParsePullRequestRef(opts.GitHubPR); err != nil {
+			log.Error("invalid --github-pr", err)
+			return exitError
+		}
+		if token == "" {
+			log.Error("invalid --github-pr", fmt.Errorf("GITHUB_TOKEN is not set"))
+			return exitError
+		}
+	}
+
+	// Interrupting keeps the checkpoint, so the next run resumes
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("scanning project", map[string]interface{}{"project": projectRoot})
+	run, err := factory.PrepareRun(projectRoot)
+	if err != nil {
+		log.Error("failed to prepare run", err)
+		return exitError
+	}
+	defer run.Close()
+	log.Info("scan complete", map[string]interface{}{"files": len(run.Files), "log": run.LogPath})
+
+	if opts.StreamFindings {
+		// A consumer that exits early (e.g. `| head`) must not kill the
+		// run; the failed write is logged and the report is still saved
+		signal.Ignore(syscall.SIGPIPE)
+		run.Orchestrator.SetFindingStream(os.Stdout)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for event := range run.Orchestrator.Events() {
+			log.Event(event)
+		}
+		close(done)
+	}()
+
+	execute := run.Execute
+	if engine.HasCheckpoint(projectRoot) {
+		log.Info("resuming from checkpoint", nil)
+		execute = run.Resume
+	}
+	err = execute(ctx)
+	<-done
+	if err != nil {
+		log.Error("analysis failed", err)
+		return exitError
+	}
+
+	report, err := run.Finish()
+	if err != nil {
+		log.Error("failed to save report", err)
+		return exitError
+	}
+
+	path, err := engine.LatestReport(projectRoot)
+	if err != nil {
+		log.Error("failed to locate report", err)
+		return exitError
+	}
+	log.Info("report saved", map[string]interface{}{
+		"path":     path,
+		"findings": report.Summary.FindingCount,
+		"critical": report.Summary.BySeverity[engine.SeverityCritical],
+		"high":     report.Summary.BySeverity[engine.SeverityHigh],
+		"medium":   report.Summary.BySeverity[engine.SeverityMedium],
+		"low":      report.Summary.BySeverity[engine.SeverityLow],
+	})
+	for _, pass := range report.Pipeline {
+		log.Info(pass.UsageSummary(), nil)
+	}
+
+	if opts.Output != "" {
+		if err := writeReport(opts.Output, format, report); err != nil {
+			log.Error("failed to write --output", err)
+			return exitError
+		}
+		log.Info("report written", map[string]interface{}{"path": opts.Output, "format": format})
+	}
+
+	if opts.GitHubPR != "" {
+		client := github.NewClient(token)
+		client.SetBaseURL(os.Getenv("GITHUB_API_URL"))
+		result, err := client.PostFindings(ctx, pr, report, github.PostOptions{})
+		if err != nil {
+			log.Error("failed to post findings to GitHub", err)
+			return exitError
+		}
+		log.Info("posted review", map[string]interface{}{
+			"pull_request": opts.GitHubPR,
+			"posted":       result.Posted,
+			"duplicates":   result.Duplicates,
+			"outside_diff": result.OutsideDiff,
+		})
+	}
+
+	if failOnSet {
+		if count := report.CountAtOrAbove(failOn); count > 0 {
+			log.Info("failing: findings at or above threshold", map[string]interface{}{
+				"severity": failOn,
+				"count":    count,
+			})
+			return exitFindings
+		}
+	}
+	return exitOK
+}
+
+// reportWriters maps --format names to the functions that write them
+var reportWriters = map[string]func(*engine.AnalysisReport, io.Writer) error{
+	"json":  writeJSONReport,
+	"sarif": engine.ExportSARIF,
+	"md":    engine.ExportMarkdown,
+	"csv":   engine.ExportCSV,
+	"html":  engine.ExportHTML,
+}
+
+// reportExtensions maps file extensions to the format they imply
+var reportExtensions = map[string]string{
+	".json":     "json",
+	".sarif":    "sarif",
+	".md":       "md",
+	".markdown": "md",
+	".csv":      "csv",
+	".html":     "html",
+	".htm":      "html",
+}
+
+// reportFormatNames lists the --format names for the usage text
+func reportFormatNames() string {
+	names := make([]string, 0, len(reportWriters))
+	for name := range reportWriters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// reportFormat returns the format to write path in: format when set,
+// otherwise the one path's extension implies, defaulting to json
+func reportFormat(path, format string) (string, error) {
+	if format == "" {
+		format = reportExtensions[strings.ToLower(filepath.Ext(path))]
+		if format == "" {
+			format = "json"
+		}
+	}
+	if _, ok := reportWriters[format]; !ok {
+		return "", fmt.Errorf("unknown report format %q (expected %s)", format, reportFormatNames())
+	}
+	return format, nil
+}
+
+// writeReport writes the report to path in format
+func writeReport(path, format string, report *engine.AnalysisReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := reportWriters[format](report, file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// writeJSONReport writes the report as indented JSON, as saved under
+// .churn/reports/
+func writeJSONReport(report *engine.AnalysisReport, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// progressLog writes a headless run's progress as text lines or JSON
+// objects, one per line
+type progressLog struct {
+	w    io.Writer
+	json bool
+}
+
+func newProgressLog(w io.Writer, format string) (*progressLog, error) {
+	switch format {
+	case "", "text":
+		return &progressLog{w: w}, nil
+	case "json":
+		return &progressLog{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected text or json)", format)
+	}
+}
+
+// Info logs a message with optional fields
+func (l *progressLog) Info(message string, fields map[string]interface{}) {
+	l.write("info", message, fields)
+}
+
+// Error logs a failure
+func (l *progressLog) Error(message string, err error) {
+	l.write("error", message, map[string]interface{}{"error": err.Error()})
+}
+
+// Event logs a pipeline event
+func (l *progressLog) Event(event engine.PipelineEvent) {
+	fields := map[string]interface{}{}
+	if event.Pass != nil {
+		fields["pass"] = event.Pass.Name
+	}
+
+	switch event.Type {
+	case engine.EventPassStarted:
+		fields["files"] = event.Total
+		l.write("info", "pass started", fields)
+	case engine.EventPassProgress:
+		fields["current"] = event.Current
+		fields["total"] = event.Total
+		l.write("info", "pass progress", fields)
+	case engine.EventPassCompleted:
+		l.write("info", "pass completed", fields)
+	case engine.EventPassFailed:
+		if event.Error != nil {
+			fields["error"] = event.Error.Error()
+		}
+		l.write("error", "pass failed", fields)
+	case engine.EventFindingAdded:
+		if finding := event.Finding; finding != nil {
+			fields["severity"] = finding.Severity
+			fields["kind"] = finding.Kind
+			fields["location"] = fmt.Sprintf("%s:%d", finding.File, finding.LineStart)
+		}
+		l.write("info", "finding", fields)
+	case engine.EventWarning:
+		message := event.Message
+		if message == "" && event.Error != nil {
+			message = event.Error.Error()
+		}
+		l.write("warn", message, fields)
+	}
+}
+
+func (l *progressLog) write(level, message string, fields map[string]interface{}) {
+	now := time.Now()
+
+	if l.json {
+		entry := map[string]interface{}{
+			"time":    now.Format(time.RFC3339),
+			"level":   level,
+			"message": message,
+		}
+		for key, value := range fields {
+			entry[key] = value
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		fmt.Fprintln(l.w, string(data))
+		return
+	}
+
+	line := fmt.Sprintf("%s %-5s %s", now.Format("15:04:05"), level, message)
+	for _, key := range sortedKeys(fields) {
+		line += fmt.Sprintf(" %s=%v", key, fields[key])
+	}
+	fmt.Fprintln(l.w, line)
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+// Command churn-plus analyzes a project with a multi-pass LLM pipeline,
+// interactively in a TUI or headless with --run.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+	"github.com/cloudboy-jh/churn-plus/internal/ui"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// Exit codes of a headless run
+const (
+	exitOK       = 0
+	exitFindings = 1 // Findings at or above the fail-on severity
+	exitError    = 2
+)
+
+func main() {
+	var (
+		run         = flag.Bool("run", false, "analyze without the TUI, save the report, and exit")
+		profile     = flag.String("profile", "", "use the named profile from the global config")
+		failOn      = flag.String("fail-on", "", "with --run, exit 1 when findings reach this severity (low, medium, high, critical)")
+		logFormat   = flag.String("log-format", "text", "with --run, progress log format on stderr: text or json")
+		stream      = flag.Bool("stream-findings", false, "with --run, write each finding to stdout as a JSON line as soon as it is found")
+		githubPR    = flag.String("github-pr", "", "with --run, post findings as a review on this pull request (owner/repo#N), using GITHUB_TOKEN")
+		output      = flag.String("output", "", "with --run, also write the report to this file")
+		format      = flag.String("format", "", "with --output, the report format: "+reportFormatNames()+" (default: from the file extension, else json)")
+		showVersion = flag.Bool("version", false, "print the version and exit")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: churn-plus [flags] [project-dir]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println("churn-plus", version)
+		return
+	}
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(exitError)
+	}
+	if *stream && !*run {
+		fmt.Fprintln(os.Stderr, "Error: --stream-findings requires --run")
+		os.Exit(exitError)
+	}
+	if *githubPR != "" && !*run {
+		fmt.Fprintln(os.Stderr, "Error: --github-pr requires --run")
+		os.Exit(exitError)
+	}
+	if *output != "" && !*run {
+		fmt.Fprintln(os.Stderr, "Error: --output requires --run")
+		os.Exit(exitError)
+	}
+	if *format != "" && *output == "" {
+		fmt.Fprintln(os.Stderr, "Error: --format requires --output")
+		os.Exit(exitError)
+	}
+
+	projectRoot, err := resolveProjectRoot(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitError)
+	}
+
+	// Profiles are selected through the environment so config.Load applies them
+	if *profile != "" {
+		os.Setenv("CHURN_PROFILE", *profile)
+	}
+
+	// Plain text when NO_COLOR is set or output is piped
+	theme.SetNoColor(theme.DetectNoColor())
+
+	if *run {
+		os.Exit(runHeadless(projectRoot, headlessOptions{
+			FailOn:         *failOn,
+			LogFormat:      *logFormat,
+			StreamFindings: *stream,
+			GitHubPR:       *githubPR,
+			Output:         *output,
+			Format:         *format,
+		}))
+	}
+
+	program := tea.NewProgram(ui.NewAppModel(projectRoot), tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitError)
+	}
+}
+
+// resolveProjectRoot returns the absolute project directory, defaulting to
+// the working directory
+func resolveProjectRoot(dir string) (string, error) {
+	if dir == "" {
+		return os.Getwd()
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open project: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return filepath.Abs(dir)
+}
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,24 +11,126 @@ import (
 type Config struct {
 	Global  *GlobalConfig  `json:"global"`
 	Project *ProjectConfig `json:"project"`
+
+	// keyringCache holds keyring lookups made by GetAPIKey
+	keyringCache map[string]string
 }
 
-// GlobalConfig is stored in ~/.churn/config.json
+// GlobalConfig is stored in ~/.churn/config.json (or config.yaml)
 type GlobalConfig struct {
+	SchemaVersion int `json:"schema_version"`
+
 	APIKeys      APIKeys           `json:"api_keys"`
 	DefaultModel ModelSelection    `json:"default_model"`
 	Concurrency  ConcurrencyLimits `json:"concurrency"`
 	Cache        CacheSettings     `json:"cache"`
 	UI           UISettings        `json:"ui"`
+
+	// UseKeyring reads API keys from the OS keyring, ahead of api_keys
+	UseKeyring bool `json:"use_keyring,omitempty"`
+
+	// Endpoints overrides provider base URLs, e.g. for gateways or self-hosted servers
+	Endpoints ProviderEndpoints `json:"endpoints"`
+
+	// CompatibleProviders defines OpenAI-compatible APIs (Mistral, Groq,
+	// OpenRouter, ...) that can be selected as a provider by their key
+	CompatibleProviders map[string]CompatibleProvider `json:"compatible_providers,omitempty"`
+
+	// RateLimits caps requests and tokens per minute, keyed by provider
+	RateLimits map[string]RateLimit `json:"rate_limits,omitempty"`
+
+	// HTTP configures the proxy and extra headers used for provider requests
+	HTTP HTTPSettings `json:"http"`
+
+	// AzureOpenAI locates the Azure OpenAI deployment used by the "azure" provider
+	AzureOpenAI AzureOpenAISettings `json:"azure_openai"`
+
+	// ContextWindows overrides model context windows in tokens, keyed by model name prefix
+	ContextWindows map[string]int `json:"context_windows,omitempty"`
+
+	// Profiles are named model setups; ActiveProfile (or CHURN_PROFILE)
+	// selects one, overriding the model, concurrency, and cache settings
+	Profiles      map[string]Profile `json:"profiles,omitempty"`
+	ActiveProfile string             `json:"active_profile,omitempty"`
 }
 
-// ProjectConfig is stored in .churn/config.json
+// ProjectConfig is stored in .churn/config.json (or config.yaml)
 type ProjectConfig struct {
+	SchemaVersion int `json:"schema_version"`
+
 	LastRun        time.Time       `json:"last_run,omitempty"`
 	Model          ModelSelection  `json:"model,omitempty"`
 	IgnorePatterns []string        `json:"ignore_patterns,omitempty"`
 	CustomPasses   []string        `json:"custom_passes,omitempty"`
 	Pipeline       *PipelineConfig `json:"pipeline,omitempty"`
+
+	// FileExtensionOverrides maps an extension to a language (e.g. ".ts": "javascript")
+	FileExtensionOverrides map[string]string `json:"file_extension_overrides,omitempty"`
+
+	// DisableGitignore stops the scanner from honoring the project's .gitignore files
+	DisableGitignore bool `json:"disable_gitignore,omitempty"`
+
+	// ScanWorkers is how many files are scanned in parallel (0 uses the CPU count)
+	ScanWorkers int `json:"scan_workers,omitempty"`
+
+	// MaxFileBytes skips files larger than this (0 uses 512KB, negative disables)
+	MaxFileBytes int64 `json:"max_file_bytes,omitempty"`
+
+	// ChangedOnly restricts analysis to files changed since BaseRef plus uncommitted work
+	ChangedOnly bool   `json:"changed_only,omitempty"`
+	BaseRef     string `json:"base_ref,omitempty"` // Default: "main"
+
+	// OnError is "continue" (default) to keep running passes after one fails, or "stop"
+	OnError string `json:"on_error,omitempty"`
+
+	// FileOrder is the order files are analyzed in: "scan" (default), "size"
+	// (most lines first), "churn" (most committed first), or "modified"
+	// (most recently modified first)
+	FileOrder string `json:"file_order,omitempty"`
+
+	// CountCodeLines counts each file's source lines of code, skipping blank
+	// and comment-only lines, alongside its total lines
+	CountCodeLines bool `json:"count_code_lines,omitempty"`
+
+	// Blame attaches the author and date of the last commit to touch each
+	// finding's lines, from git blame
+	Blame bool `json:"blame,omitempty"`
+
+	// ScanArchives unpacks .zip and .tar.gz files found in the project and scans their contents
+	ScanArchives bool `json:"scan_archives,omitempty"`
+
+	// UseBaseline drops findings recorded in .churn/baseline.json from reports
+	UseBaseline bool `json:"use_baseline,omitempty"`
+
+	// DisableLineNumbers sends file content to the model without line-number prefixes
+	DisableLineNumbers bool `json:"disable_line_numbers,omitempty"`
+
+	// DisableStructuredOutput always asks for findings in the prompt instead of
+	// using JSON mode or tool calls
+	DisableStructuredOutput bool `json:"disable_structured_output,omitempty"`
+
+	// PromptWindowLines sends only regions of interest for files longer than this (0 disables)
+	PromptWindowLines int `json:"prompt_window_lines,omitempty"`
+
+	// MinSeverity drops findings below this severity (low, medium, high, or
+	// critical) from reports and the findings list
+	MinSeverity string `json:"min_severity,omitempty"`
+
+	// FailOnSeverity makes a non-interactive run exit with an error when the
+	// report has findings at this severity or above
+	FailOnSeverity string `json:"fail_on_severity,omitempty"`
+
+	// ReportRetention prunes old reports from .churn/reports/ after each run
+	ReportRetention *ReportRetention `json:"report_retention,omitempty"`
+
+	// DedupOverlap is how much of the shorter line range two findings of the
+	// same kind in a file must share to be merged, from 0 to 1 (default 0.5).
+	// 0 merges only exact duplicates.
+	DedupOverlap *float64 `json:"dedup_overlap,omitempty"`
+
+	// LogLevel is the lowest level written to .churn/logs/: debug, info
+	// (default), warn, or error. CHURN_LOG_LEVEL overrides it.
+	LogLevel string `json:"log_level,omitempty"`
 }
 
 // PipelineConfig defines the pipeline configuration
@@ -44,6 +145,25 @@ type PassConfig struct {
 	Enabled     bool   `json:"enabled"`
 	Model       string `json:"model"`
 	Provider    string `json:"provider"`
+
+	// Mode is "files" (file content), "findings" (earlier passes' findings
+	// instead of content), or "both". Empty picks by name: "summary" uses
+	// findings, "local-refinement" both, and other passes files.
+	Mode string `json:"mode,omitempty"`
+
+	// Temperature overrides the default sampling temperature (0.7) when set
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// MaxTokens overrides the default response limit (4000) when positive
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// Timeout bounds each request of the pass as a duration, e.g. "30s" or "10m"
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// GetTimeout returns the parsed Timeout, 0 when unset or invalid
+func (p PassConfig) GetTimeout() time.Duration {
+	return parseTimeout(p.Timeout)
 }
 
 // APIKeys holds credentials for various LLM providers
@@ -51,12 +171,13 @@ type APIKeys struct {
 	Anthropic string `json:"anthropic,omitempty"`
 	OpenAI    string `json:"openai,omitempty"`
 	Google    string `json:"google,omitempty"`
+	Azure     string `json:"azure,omitempty"`
 	// Ollama doesn't need API keys (local)
 }
 
 // ModelSelection specifies which model to use for each provider
 type ModelSelection struct {
-	Provider string `json:"provider"` // "anthropic", "openai", "google", "ollama"
+	Provider string `json:"provider"` // "anthropic", "openai", "google", "azure", "ollama"
 	Model    string `json:"model"`    // e.g., "claude-3.5-sonnet", "gpt-4-turbo"
 }
 
@@ -68,6 +189,61 @@ type ConcurrencyLimits struct {
 	Google    int `json:"google"`    // Default: 8
 }
 
+// RateLimit holds a provider's per-minute limits; zero disables a limit
+type RateLimit struct {
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	TokensPerMinute   int `json:"tokens_per_minute,omitempty"`
+}
+
+// ProviderEndpoints holds optional base URLs; empty uses the provider's default
+type ProviderEndpoints struct {
+	Anthropic string `json:"anthropic,omitempty"` // Default: "https://api.anthropic.com"
+	OpenAI    string `json:"openai,omitempty"`    // Default: "https://api.openai.com/v1"
+	Google    string `json:"google,omitempty"`    // Default: "https://generativelanguage.googleapis.com"
+	Ollama    string `json:"ollama,omitempty"`    // Default: "http://localhost:11434"
+}
+
+// CompatibleProvider configures an OpenAI-compatible API
+type CompatibleProvider struct {
+	BaseURL    string   `json:"base_url"`              // e.g. "https://api.mistral.ai/v1"
+	APIKey     string   `json:"api_key,omitempty"`     // Takes precedence over APIKeyEnv
+	APIKeyEnv  string   `json:"api_key_env,omitempty"` // Environment variable holding the key
+	AuthStyle  string   `json:"auth_style,omitempty"`  // "bearer" (default), "header", or "none"
+	AuthHeader string   `json:"auth_header,omitempty"` // Header name for "header" style, default "api-key"
+	Models     []string `json:"models,omitempty"`      // Used when the /models endpoint is unavailable
+}
+
+// ResolveAPIKey returns the configured key, falling back to APIKeyEnv
+func (p CompatibleProvider) ResolveAPIKey() string {
+	if p.APIKey != "" {
+		return p.APIKey
+	}
+	if p.APIKeyEnv != "" {
+		return os.Getenv(p.APIKeyEnv)
+	}
+	return ""
+}
+
+// HTTPSettings configures how provider requests are sent
+type HTTPSettings struct {
+	Timeout string            `json:"timeout,omitempty"` // Default request timeout, e.g. "2m"; empty is 5 minutes
+	Proxy   string            `json:"proxy,omitempty"`   // e.g. "http://proxy.corp:3128"; empty uses HTTPS_PROXY/HTTP_PROXY
+	Headers map[string]string `json:"headers,omitempty"` // Added to requests to every provider
+
+	// ProviderHeaders adds headers for a single provider, overriding Headers
+	ProviderHeaders map[string]map[string]string `json:"provider_headers,omitempty"`
+}
+
+// AzureOpenAISettings configures an Azure OpenAI deployment
+type AzureOpenAISettings struct {
+	Endpoint   string `json:"endpoint,omitempty"`    // e.g. "https://my-resource.openai.azure.com"
+	Deployment string `json:"deployment,omitempty"`  // Deployment name, used in place of a model
+	APIVersion string `json:"api_version,omitempty"` // Default: "2024-06-01"
+}
+
+// DefaultAzureAPIVersion is the Azure OpenAI REST API version used when none is configured
+const DefaultAzureAPIVersion = "2024-06-01"
+
 // CacheSettings controls caching behavior
 type CacheSettings struct {
 	Enabled bool `json:"enabled"`  // Default: true
@@ -75,6 +251,13 @@ type CacheSettings struct {
 	MaxSize int  `json:"max_size"` // Max cache size in MB, default: 100
 }
 
+// ReportRetention limits the reports kept per project; zero disables a limit.
+// The newest report is always kept.
+type ReportRetention struct {
+	MaxReports int `json:"max_reports,omitempty"`  // Keep at most this many reports
+	MaxAgeDays int `json:"max_age_days,omitempty"` // Delete reports older than this
+}
+
 // UISettings controls UI behavior
 type UISettings struct {
 	ShowLineNumbers bool   `json:"show_line_numbers"` // Default: true
@@ -85,7 +268,8 @@ type UISettings struct {
 // Default configurations
 func DefaultGlobalConfig() *GlobalConfig {
 	return &GlobalConfig{
-		APIKeys: APIKeys{},
+		SchemaVersion: CurrentSchemaVersion,
+		APIKeys:       APIKeys{},
 		DefaultModel: ModelSelection{
 			Provider: "anthropic",
 			Model:    "claude-3.5-sonnet",
@@ -106,11 +290,15 @@ func DefaultGlobalConfig() *GlobalConfig {
 			SyntaxHighlight: true,
 			Theme:           "default",
 		},
+		AzureOpenAI: AzureOpenAISettings{
+			APIVersion: DefaultAzureAPIVersion,
+		},
 	}
 }
 
 func DefaultProjectConfig() *ProjectConfig {
 	return &ProjectConfig{
+		SchemaVersion: CurrentSchemaVersion,
 		IgnorePatterns: []string{
 			"node_modules",
 			".git",
@@ -120,21 +308,24 @@ func DefaultProjectConfig() *ProjectConfig {
 			"*.map",
 		},
 		CustomPasses: []string{},
+		MaxFileBytes: 512 * 1024,
 	}
 }
 
-// GetGlobalConfigPath returns ~/.churn/config.json
+// GetGlobalConfigPath returns ~/.churn/config.yaml, config.yml, or
+// config.json, whichever exists, defaulting to config.json
 func GetGlobalConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
-	return filepath.Join(home, ".churn", "config.json"), nil
+	return resolveConfigPath(filepath.Join(home, ".churn")), nil
 }
 
-// GetProjectConfigPath returns .churn/config.json in the given project root
+// GetProjectConfigPath returns the project's .churn/config.yaml, config.yml,
+// or config.json, whichever exists, defaulting to config.json
 func GetProjectConfigPath(projectRoot string) string {
-	return filepath.Join(projectRoot, ".churn", "config.json")
+	return resolveConfigPath(filepath.Join(projectRoot, ".churn"))
 }
 
 // GetReportsDir returns .churn/reports/ directory path
@@ -142,7 +333,7 @@ func GetReportsDir(projectRoot string) string {
 	return filepath.Join(projectRoot, ".churn", "reports")
 }
 
-// LoadGlobalConfig loads configuration from ~/.churn/config.json
+// LoadGlobalConfig loads configuration from ~/.churn/config.json or config.yaml
 func LoadGlobalConfig() (*GlobalConfig, error) {
 	path, err := GetGlobalConfigPath()
 	if err != nil {
@@ -164,15 +355,22 @@ func LoadGlobalConfig() (*GlobalConfig, error) {
 	}
 
 	var cfg GlobalConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	migrated, err := decodeConfig(path, data, &cfg, globalMigrations)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse global config: %w", err)
 	}
 
 	// Merge with defaults for any missing fields
-	return mergeGlobalWithDefaults(&cfg), nil
+	merged := mergeGlobalWithDefaults(&cfg)
+	if migrated {
+		if err := SaveGlobalConfig(merged); err != nil {
+			return nil, fmt.Errorf("failed to rewrite migrated global config: %w", err)
+		}
+	}
+	return merged, nil
 }
 
-// LoadProjectConfig loads configuration from .churn/config.json
+// LoadProjectConfig loads configuration from .churn/config.json or config.yaml
 func LoadProjectConfig(projectRoot string) (*ProjectConfig, error) {
 	path := GetProjectConfigPath(projectRoot)
 
@@ -191,39 +389,56 @@ func LoadProjectConfig(projectRoot string) (*ProjectConfig, error) {
 	}
 
 	var cfg ProjectConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	migrated, err := decodeConfig(path, data, &cfg, projectMigrations)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse project config: %w", err)
 	}
 
-	return mergeProjectWithDefaults(&cfg), nil
+	merged := mergeProjectWithDefaults(&cfg)
+	if migrated {
+		if err := SaveProjectConfig(projectRoot, merged); err != nil {
+			return nil, fmt.Errorf("failed to rewrite migrated project config: %w", err)
+		}
+	}
+	return merged, nil
 }
 
-// SaveGlobalConfig writes global configuration to ~/.churn/config.json
+// SaveGlobalConfig writes global configuration to ~/.churn/, keeping the
+// format of the existing file. The file can hold API keys and auth headers,
+// so it and its directory are readable by the owner only.
 func SaveGlobalConfig(cfg *GlobalConfig) error {
 	path, err := GetGlobalConfigPath()
 	if err != nil {
 		return err
 	}
 
-	// Ensure directory exists
+	// Ensure directory exists, tightening one created by older versions
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(dir, 0700); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return fmt.Errorf("failed to restrict config directory: %w", err)
+	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := marshalConfig(path, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// WriteFile keeps the mode of an existing file
+	if err := os.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict config: %w", err)
+	}
 
 	return nil
 }
 
-// SaveProjectConfig writes project configuration to .churn/config.json
+// SaveProjectConfig writes project configuration to .churn/, keeping the
+// format of the existing file
 func SaveProjectConfig(projectRoot string, cfg *ProjectConfig) error {
 	path := GetProjectConfigPath(projectRoot)
 
@@ -233,7 +448,7 @@ func SaveProjectConfig(projectRoot string, cfg *ProjectConfig) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := marshalConfig(path, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -245,7 +460,9 @@ func SaveProjectConfig(projectRoot string, cfg *ProjectConfig) error {
 	return nil
 }
 
-// Load loads and merges global and project configurations
+// Load loads and merges global and project configurations. If the files
+// parse but fail validation, the config is returned together with a
+// ValidationErrors error.
 func Load(projectRoot string) (*Config, error) {
 	global, err := LoadGlobalConfig()
 	if err != nil {
@@ -258,55 +475,203 @@ func Load(projectRoot string) (*Config, error) {
 	}
 
 	// Override API keys from environment variables if present
-	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
-		global.APIKeys.Anthropic = key
+	for provider, name := range apiKeyEnvVars {
+		if key := os.Getenv(name); key != "" {
+			global.APIKeys.set(provider, key)
+		}
 	}
-	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
-		global.APIKeys.OpenAI = key
+	if endpoint := os.Getenv("AZURE_OPENAI_ENDPOINT"); endpoint != "" {
+		global.AzureOpenAI.Endpoint = endpoint
 	}
-	if key := os.Getenv("GOOGLE_API_KEY"); key != "" {
-		global.APIKeys.Google = key
+	if baseURL := os.Getenv("OPENAI_BASE_URL"); baseURL != "" {
+		global.Endpoints.OpenAI = baseURL
 	}
+	if host := os.Getenv("OLLAMA_HOST"); host != "" {
+		global.Endpoints.Ollama = host
+	}
+	applyProfileEnv(global)
 
-	return &Config{
+	cfg := &Config{
 		Global:  global,
 		Project: project,
-	}, nil
+	}
+
+	// Invalid settings are reported alongside the config so callers can
+	// show them and carry on
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
 }
 
-// GetAPIKey returns the API key for a given provider
+// GetAPIKey returns the API key for a given provider. Environment variables
+// win, then the OS keyring when UseKeyring is set, then the config file.
 func (c *Config) GetAPIKey(provider string) string {
+	if name, ok := apiKeyEnvVars[provider]; ok {
+		if key := os.Getenv(name); key != "" {
+			return key
+		}
+	}
+	if key := c.keyringAPIKey(provider); key != "" {
+		return key
+	}
+
+	switch provider {
+	case "anthropic", "openai", "google", "azure":
+		return c.Global.APIKeys.get(provider)
+	default:
+		if compatible, ok := c.GetCompatibleProvider(provider); ok {
+			return compatible.ResolveAPIKey()
+		}
+		return ""
+	}
+}
+
+// GetCompatibleProvider returns the OpenAI-compatible provider configured
+// under name, if any
+func (c *Config) GetCompatibleProvider(name string) (CompatibleProvider, bool) {
+	compatible, ok := c.Global.CompatibleProviders[name]
+	return compatible, ok
+}
+
+// GetEndpoint returns the configured base URL for a provider, or "" for the default
+func (c *Config) GetEndpoint(provider string) string {
 	switch provider {
 	case "anthropic":
-		return c.Global.APIKeys.Anthropic
+		return c.Global.Endpoints.Anthropic
 	case "openai":
-		return c.Global.APIKeys.OpenAI
+		return c.Global.Endpoints.OpenAI
 	case "google":
-		return c.Global.APIKeys.Google
+		return c.Global.Endpoints.Google
+	case "ollama":
+		return c.Global.Endpoints.Ollama
 	default:
 		return ""
 	}
 }
 
-// GetConcurrencyLimit returns the concurrency limit for a provider
+// GetHTTPHeaders returns the extra headers to send with a provider's requests
+func (c *Config) GetHTTPHeaders(provider string) map[string]string {
+	headers := make(map[string]string)
+	for name, value := range c.Global.HTTP.Headers {
+		headers[name] = value
+	}
+	for name, value := range c.Global.HTTP.ProviderHeaders[provider] {
+		headers[name] = value
+	}
+	return headers
+}
+
+// GetRateLimit returns the rate limit configured for a provider, if any
+func (c *Config) GetRateLimit(provider string) (RateLimit, bool) {
+	limit, ok := c.Global.RateLimits[provider]
+	if !ok || (limit.RequestsPerMinute <= 0 && limit.TokensPerMinute <= 0) {
+		return RateLimit{}, false
+	}
+	return limit, true
+}
+
+// GetRequestTimeout returns the configured default request timeout, 0 to
+// use the provider default
+func (c *Config) GetRequestTimeout() time.Duration {
+	return parseTimeout(c.Global.HTTP.Timeout)
+}
+
+// parseTimeout parses a duration string, returning 0 when it is empty or
+// invalid (Validate reports invalid ones)
+func parseTimeout(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// GetConcurrencyLimit returns the concurrency limit for a provider,
+// resolved through the active profile
 func (c *Config) GetConcurrencyLimit(provider string) int {
+	if _, profile, ok := c.ActiveProfile(); ok {
+		if limit := profile.Concurrency.get(provider); limit > 0 {
+			return limit
+		}
+	}
+	if limit := c.Global.Concurrency.get(provider); limit > 0 {
+		return limit
+	}
+	return 5
+}
+
+// SetConcurrencyLimits saves the global concurrency limits to the global
+// config file. Every limit must be at least 1.
+func (c *Config) SetConcurrencyLimits(limits ConcurrencyLimits) error {
+	for _, provider := range []string{"ollama", "openai", "anthropic", "google"} {
+		if limits.get(provider) < 1 {
+			return fmt.Errorf("concurrency limit for %s must be at least 1", provider)
+		}
+	}
+
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	global.Concurrency = limits
+	if err := SaveGlobalConfig(global); err != nil {
+		return err
+	}
+
+	c.Global.Concurrency = limits
+	return nil
+}
+
+// SetCacheSettings saves the global cache settings to the global config
+// file. TTL and MaxSize must be positive.
+func (c *Config) SetCacheSettings(settings CacheSettings) error {
+	if settings.TTL <= 0 {
+		return fmt.Errorf("cache TTL must be a positive number of hours, got %d", settings.TTL)
+	}
+	if settings.MaxSize <= 0 {
+		return fmt.Errorf("cache max size must be a positive number of MB, got %d", settings.MaxSize)
+	}
+
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	global.Cache = settings
+	if err := SaveGlobalConfig(global); err != nil {
+		return err
+	}
+
+	c.Global.Cache = settings
+	return nil
+}
+
+// get returns the limit for a provider, 0 when unset or unknown
+func (l ConcurrencyLimits) get(provider string) int {
 	switch provider {
 	case "ollama":
-		return c.Global.Concurrency.Ollama
+		return l.Ollama
 	case "openai":
-		return c.Global.Concurrency.OpenAI
+		return l.OpenAI
 	case "anthropic":
-		return c.Global.Concurrency.Anthropic
+		return l.Anthropic
 	case "google":
-		return c.Global.Concurrency.Google
+		return l.Google
 	default:
-		return 5
+		return 0
 	}
 }
 
 // GetModelSelection returns the active model selection
-// Project config overrides global config
+// The active profile overrides project config, which overrides global config
 func (c *Config) GetModelSelection() ModelSelection {
+	if _, profile, ok := c.ActiveProfile(); ok && profile.Model.Provider != "" {
+		return profile.Model
+	}
 	if c.Project.Model.Provider != "" && c.Project.Model.Model != "" {
 		return c.Project.Model
 	}
@@ -341,6 +706,10 @@ func mergeGlobalWithDefaults(cfg *GlobalConfig) *GlobalConfig {
 		cfg.UI.Theme = defaults.UI.Theme
 	}
 
+	if cfg.AzureOpenAI.APIVersion == "" {
+		cfg.AzureOpenAI.APIVersion = DefaultAzureAPIVersion
+	}
+
 	return cfg
 }
 
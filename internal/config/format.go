@@ -0,0 +1,94 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are the accepted config file names in lookup order. YAML
+// wins over JSON so a hand-written config.yaml isn't shadowed by the
+// default config.json created on first run.
+var configFileNames = []string{"config.yaml", "config.yml", "config.json"}
+
+// resolveConfigPath returns the config file in dir, or config.json when
+// none exists yet
+func resolveConfigPath(dir string) string {
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// isYAMLPath reports whether path names a YAML file
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// unmarshalConfig parses data in the format given by path's extension. YAML
+// is converted to JSON first so the json struct tags apply to both formats.
+func unmarshalConfig(path string, data []byte, v interface{}) error {
+	if !isYAMLPath(path) {
+		return json.Unmarshal(data, v)
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc == nil {
+		return nil
+	}
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to convert YAML: %w", err)
+	}
+	return json.Unmarshal(jsonData, v)
+}
+
+// marshalConfig encodes v in the format given by path's extension. YAML
+// output keeps the JSON field names and order; comments in a hand-written
+// file are not preserved.
+func marshalConfig(path string, v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil || !isYAMLPath(path) {
+		return data, err
+	}
+
+	// JSON is valid YAML, so parse it into a node tree and re-emit it in
+	// block style
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearFlowStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clearFlowStyle switches a node tree from JSON's flow style to block style
+func clearFlowStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range node.Content {
+		clearFlowStyle(child)
+	}
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name API keys are stored under in the OS
+// keyring (Keychain, Secret Service, or Credential Manager)
+const keyringService = "churn-plus"
+
+// keyringProviders are the providers whose keys can live in the keyring
+var keyringProviders = []string{"anthropic", "openai", "google", "azure"}
+
+// apiKeyEnvVars maps providers to the environment variables that override
+// their stored keys
+var apiKeyEnvVars = map[string]string{
+	"anthropic": "ANTHROPIC_API_KEY",
+	"openai":    "OPENAI_API_KEY",
+	"google":    "GOOGLE_API_KEY",
+	"azure":     "AZURE_OPENAI_API_KEY",
+}
+
+// Where an API key was found, as reported by APIKeySource
+const (
+	KeySourceEnv     = "env"
+	KeySourceKeyring = "keyring"
+	KeySourceConfig  = "config"
+)
+
+// GetKeyringAPIKey reads a provider's key from the OS keyring, returning ""
+// when none is stored
+func GetKeyringAPIKey(provider string) (string, error) {
+	key, err := keyring.Get(keyringService, provider)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s key from keyring: %w", provider, err)
+	}
+	return key, nil
+}
+
+// SetKeyringAPIKey stores a provider's key in the OS keyring. An empty key
+// removes it.
+func SetKeyringAPIKey(provider, apiKey string) error {
+	if apiKey == "" {
+		err := keyring.Delete(keyringService, provider)
+		if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("failed to delete %s key from keyring: %w", provider, err)
+		}
+		return nil
+	}
+
+	if err := keyring.Set(keyringService, provider, apiKey); err != nil {
+		return fmt.Errorf("failed to write %s key to keyring: %w", provider, err)
+	}
+	return nil
+}
+
+// MigrateAPIKeysToKeyring moves the plaintext keys in the global config file
+// into the OS keyring, blanks them in the file, and turns on UseKeyring. It
+// returns how many keys were moved. Keys from environment variables are
+// never written anywhere.
+func MigrateAPIKeysToKeyring() (int, error) {
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, provider := range keyringProviders {
+		key := global.APIKeys.get(provider)
+		if key == "" {
+			continue
+		}
+		if err := SetKeyringAPIKey(provider, key); err != nil {
+			return moved, err
+		}
+		global.APIKeys.set(provider, "")
+		moved++
+	}
+
+	global.UseKeyring = true
+	if err := SaveGlobalConfig(global); err != nil {
+		return moved, fmt.Errorf("failed to blank migrated keys: %w", err)
+	}
+
+	return moved, nil
+}
+
+// EnableKeyring runs MigrateAPIKeysToKeyring and brings the loaded config in
+// line with the rewritten file. Environment variables still take precedence
+// because GetAPIKey checks them first.
+func (c *Config) EnableKeyring() (int, error) {
+	moved, err := MigrateAPIKeysToKeyring()
+	if err != nil {
+		return moved, err
+	}
+
+	c.Global.UseKeyring = true
+	for _, provider := range keyringProviders {
+		c.Global.APIKeys.set(provider, "")
+	}
+	c.keyringCache = nil
+	return moved, nil
+}
+
+// StoreAPIKey saves a provider's key where GetAPIKey will find it: the OS
+// keyring when UseKeyring is set, otherwise the global config file. An empty
+// key clears it. The file is edited as loaded from disk so keys merged in
+// from environment variables are never written out.
+func (c *Config) StoreAPIKey(provider, apiKey string) error {
+	if c.Global.UseKeyring {
+		if err := SetKeyringAPIKey(provider, apiKey); err != nil {
+			return err
+		}
+		if c.keyringCache == nil {
+			c.keyringCache = make(map[string]string)
+		}
+		c.keyringCache[provider] = apiKey
+		return nil
+	}
+
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	global.APIKeys.set(provider, apiKey)
+	if err := SaveGlobalConfig(global); err != nil {
+		return err
+	}
+
+	c.Global.APIKeys.set(provider, apiKey)
+	return nil
+}
+
+// APIKeySource reports where GetAPIKey finds a provider's key: KeySourceEnv,
+// KeySourceKeyring, KeySourceConfig, or "" when it has none
+func (c *Config) APIKeySource(provider string) string {
+	if name, ok := apiKeyEnvVars[provider]; ok && os.Getenv(name) != "" {
+		return KeySourceEnv
+	}
+	if c.keyringAPIKey(provider) != "" {
+		return KeySourceKeyring
+	}
+	if c.Global.APIKeys.get(provider) != "" {
+		return KeySourceConfig
+	}
+	return ""
+}
+
+// keyringAPIKey returns the provider's keyring entry when the keyring is
+// enabled, caching lookups since each one can cost a D-Bus round trip
+func (c *Config) keyringAPIKey(provider string) string {
+	if !c.Global.UseKeyring {
+		return ""
+	}
+
+	if key, ok := c.keyringCache[provider]; ok {
+		return key
+	}
+
+	// An unavailable keyring falls back to the config and env keys
+	key, _ := GetKeyringAPIKey(provider)
+	if c.keyringCache == nil {
+		c.keyringCache = make(map[string]string)
+	}
+	c.keyringCache[provider] = key
+	return key
+}
+
+// get returns the stored key for a built-in provider
+func (k *APIKeys) get(provider string) string {
+	switch provider {
+	case "anthropic":
+		return k.Anthropic
+	case "openai":
+		return k.OpenAI
+	case "google":
+		return k.Google
+	case "azure":
+		return k.Azure
+	default:
+		return ""
+	}
+}
+
+// set replaces the stored key for a built-in provider
+func (k *APIKeys) set(provider, apiKey string) {
+	switch provider {
+	case "anthropic":
+		k.Anthropic = apiKey
+	case "openai":
+		k.OpenAI = apiKey
+	case "google":
+		k.Google = apiKey
+	case "azure":
+		k.Azure = apiKey
+	}
+}
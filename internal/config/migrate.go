@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CurrentSchemaVersion is the config file shape this build reads and writes.
+// Bump it together with a new entry in globalMigrations and
+// projectMigrations whenever fields are renamed or reinterpreted.
+const CurrentSchemaVersion = 1
+
+// migration upgrades a decoded config document by one schema version
+type migration func(doc map[string]interface{})
+
+// globalMigrations[i] upgrades a global config from version i to i+1
+var globalMigrations = []migration{
+	migrateGlobalV0,
+}
+
+// projectMigrations[i] upgrades a project config from version i to i+1
+var projectMigrations = []migration{
+	migrateProjectV0,
+}
+
+// decodeConfig parses data into v, first upgrading documents written with
+// an older schema. It reports whether a migration ran so the caller can
+// rewrite the file.
+func decodeConfig(path string, data []byte, v interface{}, migrations []migration) (bool, error) {
+	var doc map[string]interface{}
+	if err := unmarshalConfig(path, data, &doc); err != nil {
+		return false, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	version := schemaVersion(doc)
+	if version > CurrentSchemaVersion {
+		return false, fmt.Errorf("schema version %d is newer than this churn-plus supports (%d)", version, CurrentSchemaVersion)
+	}
+
+	for from := version; from < CurrentSchemaVersion; from++ {
+		migrations[from](doc)
+	}
+	doc["schema_version"] = CurrentSchemaVersion
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(jsonData, v); err != nil {
+		return false, err
+	}
+
+	return version < CurrentSchemaVersion, nil
+}
+
+// schemaVersion reads schema_version from a document, 0 when absent
+func schemaVersion(doc map[string]interface{}) int {
+	// JSON numbers decode as float64
+	if version, ok := doc["schema_version"].(float64); ok {
+		return int(version)
+	}
+	return 0
+}
+
+// migrateGlobalV0 upgrades unversioned global configs. Provider names were
+// matched case-sensitively but never validated, so "Anthropic" loaded and
+// then failed at run time; they are lowercased here.
+func migrateGlobalV0(doc map[string]interface{}) {
+	if model, ok := doc["default_model"].(map[string]interface{}); ok {
+		lowercaseField(model, "provider")
+	}
+}
+
+// migrateProjectV0 upgrades unversioned project configs, lowercasing
+// provider names as migrateGlobalV0 does
+func migrateProjectV0(doc map[string]interface{}) {
+	if model, ok := doc["model"].(map[string]interface{}); ok {
+		lowercaseField(model, "provider")
+	}
+
+	pipeline, ok := doc["pipeline"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	passes, _ := pipeline["passes"].([]interface{})
+	for _, pass := range passes {
+		if pass, ok := pass.(map[string]interface{}); ok {
+			lowercaseField(pass, "provider")
+		}
+	}
+}
+
+// lowercaseField lowercases a string field in place
+func lowercaseField(doc map[string]interface{}, field string) {
+	if value, ok := doc[field].(string); ok {
+		doc[field] = strings.ToLower(value)
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectConfigMigratesV0(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{
+			name: "json",
+			file: "config.json",
+			contents: `{
+  "model": {"provider": "Anthropic", "model": "claude-3-5-sonnet-20241022"},
+  "pipeline": {"passes": [{"name": "lint", "enabled": true, "model": "llama3", "provider": "Ollama"}]}
+}`,
+		},
+		{
+			name: "yaml",
+			file: "config.yaml",
+			contents: `model:
+  provider: Anthropic
+  model: claude-3-5-sonnet-20241022
+pipeline:
+  passes:
+    - name: lint
+      enabled: true
+      model: llama3
+      provider: Ollama
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, ".churn", tt.file)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadProjectConfig(root)
+			if err != nil {
+				t.Fatalf("LoadProjectConfig: %v", err)
+			}
+			if cfg.SchemaVersion != CurrentSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", cfg.SchemaVersion, CurrentSchemaVersion)
+			}
+			if cfg.Model.Provider != "anthropic" {
+				t.Errorf("Model.Provider = %q, want %q", cfg.Model.Provider, "anthropic")
+			}
+			if cfg.Pipeline == nil || len(cfg.Pipeline.Passes) != 1 {
+				t.Fatalf("Pipeline = %+v, want one pass", cfg.Pipeline)
+			}
+			if got := cfg.Pipeline.Passes[0].Provider; got != "ollama" {
+				t.Errorf("pass provider = %q, want %q", got, "ollama")
+			}
+
+			// The file is rewritten in place, in its own format
+			if got := GetProjectConfigPath(root); got != path {
+				t.Errorf("config path = %s, want %s", got, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rewritten := string(data)
+			if strings.Contains(rewritten, "Anthropic") || strings.Contains(rewritten, "Ollama") {
+				t.Errorf("rewritten config still has mixed-case providers:\n%s", rewritten)
+			}
+			if !strings.Contains(rewritten, "schema_version") {
+				t.Errorf("rewritten config has no schema_version:\n%s", rewritten)
+			}
+
+			var reloaded ProjectConfig
+			migrated, err := decodeConfig(path, data, &reloaded, projectMigrations)
+			if err != nil {
+				t.Fatalf("decoding rewritten config: %v", err)
+			}
+			if migrated {
+				t.Error("rewritten config was migrated again")
+			}
+			if reloaded.Model.Provider != "anthropic" {
+				t.Errorf("rewritten Model.Provider = %q, want %q", reloaded.Model.Provider, "anthropic")
+			}
+		})
+	}
+}
+
+func TestLoadGlobalConfigMigratesV0(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path := filepath.Join(home, ".churn", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	contents := `{"default_model": {"provider": "OpenAI", "model": "gpt-4o"}}`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatalf("LoadGlobalConfig: %v", err)
+	}
+	if cfg.DefaultModel.Provider != "openai" {
+		t.Errorf("DefaultModel.Provider = %q, want %q", cfg.DefaultModel.Provider, "openai")
+	}
+	if cfg.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", cfg.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	// The rewritten file can hold keys, so only its owner may read it
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("config mode = %o, want 600", mode)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reloaded GlobalConfig
+	migrated, err := decodeConfig(path, data, &reloaded, globalMigrations)
+	if err != nil {
+		t.Fatalf("decoding rewritten config: %v", err)
+	}
+	if migrated {
+		t.Error("rewritten config was migrated again")
+	}
+	if reloaded.DefaultModel.Provider != "openai" {
+		t.Errorf("rewritten DefaultModel.Provider = %q, want %q", reloaded.DefaultModel.Provider, "openai")
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// Profile is a named model setup, e.g. a cheap local one and a premium
+// hosted one, that can be switched without editing the model selection
+type Profile struct {
+	Model ModelSelection `json:"model"`
+
+	// Concurrency overrides the global limits; zero fields keep the global value
+	Concurrency ConcurrencyLimits `json:"concurrency"`
+
+	// Cache replaces the global cache settings when set
+	Cache *CacheSettings `json:"cache,omitempty"`
+}
+
+// ActiveProfile returns the active profile and its name, or ok=false when
+// no profile is active
+func (c *Config) ActiveProfile() (name string, profile Profile, ok bool) {
+	name = c.Global.ActiveProfile
+	if name == "" {
+		return "", Profile{}, false
+	}
+	profile, ok = c.Global.Profiles[name]
+	return name, profile, ok
+}
+
+// ProfileNames returns the configured profile names in sorted order
+func (c *Config) ProfileNames() []string {
+	return sortedKeys(c.Global.Profiles)
+}
+
+// SetActiveProfile switches to the named profile, or back to the plain
+// configuration when name is empty, and saves the choice to the global
+// config file
+func (c *Config) SetActiveProfile(name string) error {
+	if name != "" {
+		if _, ok := c.Global.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+	}
+
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	global.ActiveProfile = name
+	if err := SaveGlobalConfig(global); err != nil {
+		return err
+	}
+
+	c.Global.ActiveProfile = name
+	return nil
+}
+
+// SetProfileModel changes the active profile's model and saves it to the
+// global config file
+func (c *Config) SetProfileModel(selection ModelSelection) error {
+	name, profile, ok := c.ActiveProfile()
+	if !ok {
+		return fmt.Errorf("no active profile")
+	}
+
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	saved, ok := global.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q is not in the global config file", name)
+	}
+	saved.Model = selection
+	global.Profiles[name] = saved
+	if err := SaveGlobalConfig(global); err != nil {
+		return err
+	}
+
+	profile.Model = selection
+	c.Global.Profiles[name] = profile
+	return nil
+}
+
+// GetCacheSettings returns the cache settings, resolved through the active
+// profile
+func (c *Config) GetCacheSettings() CacheSettings {
+	settings := c.Global.Cache
+	if _, profile, ok := c.ActiveProfile(); ok && profile.Cache != nil {
+		settings = *profile.Cache
+		if settings.TTL == 0 {
+			settings.TTL = c.Global.Cache.TTL
+		}
+		if settings.MaxSize == 0 {
+			settings.MaxSize = c.Global.Cache.MaxSize
+		}
+	}
+	return settings
+}
+
+// applyProfileEnv selects the profile named by CHURN_PROFILE, if set
+func applyProfileEnv(global *GlobalConfig) {
+	if name := os.Getenv("CHURN_PROFILE"); name != "" {
+		global.ActiveProfile = name
+	}
+}
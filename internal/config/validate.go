@@ -0,0 +1,311 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KnownProviders are the built-in provider names. Entries in
+// GlobalConfig.CompatibleProviders are accepted as well.
+var KnownProviders = []string{"anthropic", "openai", "google", "azure", "ollama"}
+
+// ValidationError describes a problem with a single config field
+type ValidationError struct {
+	Field   string // JSON path, e.g. "project.pipeline.passes[1].provider"
+	Message string
+}
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors collects every problem found in a config
+type ValidationErrors []ValidationError
+
+// Error implements the error interface, listing one problem per line
+func (e ValidationErrors) Error() string {
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, "invalid config:")
+	for _, err := range e {
+		lines = append(lines, "  "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// add records a problem with field
+func (e *ValidationErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the merged configuration, returning nil when it is valid
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	if c.Global != nil {
+		errs = append(errs, c.Global.validate()...)
+	}
+	if c.Project != nil {
+		providers := KnownProviders
+		if c.Global != nil {
+			providers = c.Global.ProviderNames()
+		}
+		errs = append(errs, c.Project.validate(providers)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Validate checks the global configuration, returning nil when it is valid
+func (g *GlobalConfig) Validate() error {
+	if errs := g.validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks the project configuration against the built-in providers,
+// returning nil when it is valid. Use Config.Validate to also accept
+// compatible providers from the global config.
+func (p *ProjectConfig) Validate() error {
+	if errs := p.validate(KnownProviders); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ProviderNames returns the built-in providers plus the configured
+// compatible providers
+func (g *GlobalConfig) ProviderNames() []string {
+	names := append([]string{}, KnownProviders...)
+	return append(names, sortedKeys(g.CompatibleProviders)...)
+}
+
+func (g *GlobalConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+	providers := g.ProviderNames()
+
+	validateProvider(&errs, "global.default_model.provider", g.DefaultModel.Provider, providers)
+
+	limits := []struct {
+		field string
+		value int
+	}{
+		{"global.concurrency.ollama", g.Concurrency.Ollama},
+		{"global.concurrency.openai", g.Concurrency.OpenAI},
+		{"global.concurrency.anthropic", g.Concurrency.Anthropic},
+		{"global.concurrency.google", g.Concurrency.Google},
+	}
+	for _, limit := range limits {
+		if limit.value <= 0 {
+			errs.add(limit.field, "must be greater than 0, got %d", limit.value)
+		}
+	}
+
+	if g.Cache.TTL <= 0 {
+		errs.add("global.cache.ttl", "must be a positive number of hours, got %d", g.Cache.TTL)
+	}
+	if g.Cache.MaxSize <= 0 {
+		errs.add("global.cache.max_size", "must be a positive number of MB, got %d", g.Cache.MaxSize)
+	}
+
+	for prefix, window := range g.ContextWindows {
+		if window <= 0 {
+			errs.add(fmt.Sprintf("global.context_windows[%q]", prefix), "must be greater than 0, got %d", window)
+		}
+	}
+
+	for _, name := range sortedKeys(g.CompatibleProviders) {
+		compatible := g.CompatibleProviders[name]
+		field := fmt.Sprintf("global.compatible_providers[%q]", name)
+		if contains(KnownProviders, name) {
+			errs.add(field, "name clashes with the built-in %s provider", name)
+		}
+		if compatible.BaseURL == "" {
+			errs.add(field+".base_url", "is required")
+		}
+		switch compatible.AuthStyle {
+		case "", "bearer", "header", "none":
+		default:
+			errs.add(field+".auth_style", "unknown style %q (expected bearer, header, or none)", compatible.AuthStyle)
+		}
+	}
+
+	if g.HTTP.Proxy != "" {
+		if proxyURL, err := url.Parse(g.HTTP.Proxy); err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			errs.add("global.http.proxy", "invalid proxy URL %q", g.HTTP.Proxy)
+		}
+	}
+
+	validateTimeout(&errs, "global.http.timeout", g.HTTP.Timeout)
+
+	for _, name := range sortedKeys(g.RateLimits) {
+		limit := g.RateLimits[name]
+		field := fmt.Sprintf("global.rate_limits[%q]", name)
+		validateProvider(&errs, field, name, providers)
+		if limit.RequestsPerMinute < 0 {
+			errs.add(field+".requests_per_minute", "must not be negative, got %d", limit.RequestsPerMinute)
+		}
+		if limit.TokensPerMinute < 0 {
+			errs.add(field+".tokens_per_minute", "must not be negative, got %d", limit.TokensPerMinute)
+		}
+	}
+
+	for _, name := range sortedKeys(g.Profiles) {
+		profile := g.Profiles[name]
+		field := fmt.Sprintf("global.profiles[%q]", name)
+		validateProvider(&errs, field+".model.provider", profile.Model.Provider, providers)
+		if profile.Model.Model == "" {
+			errs.add(field+".model.model", "is required")
+		}
+		limits := []struct {
+			field string
+			value int
+		}{
+			{field + ".concurrency.ollama", profile.Concurrency.Ollama},
+			{field + ".concurrency.openai", profile.Concurrency.OpenAI},
+			{field + ".concurrency.anthropic", profile.Concurrency.Anthropic},
+			{field + ".concurrency.google", profile.Concurrency.Google},
+		}
+		for _, limit := range limits {
+			if limit.value < 0 {
+				errs.add(limit.field, "must not be negative, got %d", limit.value)
+			}
+		}
+		if profile.Cache != nil && (profile.Cache.TTL < 0 || profile.Cache.MaxSize < 0) {
+			errs.add(field+".cache", "ttl and max_size must not be negative")
+		}
+	}
+
+	if g.ActiveProfile != "" {
+		if _, ok := g.Profiles[g.ActiveProfile]; !ok {
+			errs.add("global.active_profile", "unknown profile %q", g.ActiveProfile)
+		}
+	}
+
+	return errs
+}
+
+func (p *ProjectConfig) validate(providers []string) ValidationErrors {
+	var errs ValidationErrors
+
+	if p.Model.Provider != "" {
+		validateProvider(&errs, "project.model.provider", p.Model.Provider, providers)
+	}
+
+	switch p.OnError {
+	case "", "continue", "stop":
+	default:
+		errs.add("project.on_error", "unknown policy %q (expected continue or stop)", p.OnError)
+	}
+
+	switch p.FileOrder {
+	case "", "scan", "size", "churn", "modified":
+	default:
+		errs.add("project.file_order", "unknown order %q (expected scan, size, churn, or modified)", p.FileOrder)
+	}
+
+	if p.ScanWorkers < 0 {
+		errs.add("project.scan_workers", "must not be negative, got %d", p.ScanWorkers)
+	}
+	if p.PromptWindowLines < 0 {
+		errs.add("project.prompt_window_lines", "must not be negative, got %d", p.PromptWindowLines)
+	}
+	if retention := p.ReportRetention; retention != nil {
+		if retention.MaxReports < 0 {
+			errs.add("project.report_retention.max_reports", "must not be negative, got %d", retention.MaxReports)
+		}
+		if retention.MaxAgeDays < 0 {
+			errs.add("project.report_retention.max_age_days", "must not be negative, got %d", retention.MaxAgeDays)
+		}
+	}
+	validateSeverity(&errs, "project.min_severity", p.MinSeverity)
+	validateSeverity(&errs, "project.fail_on_severity", p.FailOnSeverity)
+
+	switch strings.ToLower(strings.TrimSpace(p.LogLevel)) {
+	case "", "debug", "info", "warn", "error":
+	default:
+		errs.add("project.log_level", "unknown log level %q (expected debug, info, warn, or error)", p.LogLevel)
+	}
+	if p.DedupOverlap != nil && (*p.DedupOverlap < 0 || *p.DedupOverlap > 1) {
+		errs.add("project.dedup_overlap", "must be between 0 and 1, got %g", *p.DedupOverlap)
+	}
+
+	if p.Pipeline != nil {
+		for i, pass := range p.Pipeline.Passes {
+			field := fmt.Sprintf("project.pipeline.passes[%d]", i)
+			if pass.Name == "" {
+				errs.add(field+".name", "is required")
+			}
+			validateProvider(&errs, field+".provider", pass.Provider, providers)
+			switch pass.Mode {
+			case "", "files", "findings", "both":
+			default:
+				errs.add(field+".mode", "unknown mode %q (expected files, findings, or both)", pass.Mode)
+			}
+			if pass.Temperature != nil && (*pass.Temperature < 0 || *pass.Temperature > 2) {
+				errs.add(field+".temperature", "must be between 0 and 2, got %g", *pass.Temperature)
+			}
+			if pass.MaxTokens < 0 {
+				errs.add(field+".max_tokens", "must not be negative, got %d", pass.MaxTokens)
+			}
+			validateTimeout(&errs, field+".timeout", pass.Timeout)
+		}
+	}
+
+	return errs
+}
+
+// validateSeverity records an error unless severity is empty or a known level
+func validateSeverity(errs *ValidationErrors, field, severity string) {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "", "low", "medium", "high", "critical":
+	default:
+		errs.add(field, "unknown severity %q (expected low, medium, high, or critical)", severity)
+	}
+}
+
+// validateProvider records an error unless provider is one of providers
+func validateProvider(errs *ValidationErrors, field, provider string, providers []string) {
+	if provider == "" {
+		errs.add(field, "is required")
+		return
+	}
+	if !contains(providers, provider) {
+		errs.add(field, "unknown provider %q (expected one of %s)", provider, strings.Join(providers, ", "))
+	}
+}
+
+// validateTimeout records an error unless value is empty or a positive
+// duration such as "30s"
+func validateTimeout(errs *ValidationErrors, field, value string) {
+	if value == "" {
+		return
+	}
+	if timeout, err := time.ParseDuration(value); err != nil || timeout <= 0 {
+		errs.add(field, "must be a positive duration such as \"30s\" or \"10m\", got %q", value)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
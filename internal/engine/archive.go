@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveSeparator separates an archive path from the path of a file inside it
+const ArchiveSeparator = "::"
+
+// Limits on what is extracted from one archive. Larger entries are skipped
+// and counted as too large; an archive over the entry count or total size
+// (e.g. a zip bomb) is not scanned at all.
+const (
+	maxArchiveEntryBytes = 10 * 1024 * 1024
+	maxArchiveEntries    = 10000
+	maxArchiveTotalBytes = 200 * 1024 * 1024
+)
+
+// errArchiveTooLarge marks archives over the entry count or total size limit
+var errArchiveTooLarge = errors.New("archive exceeds extraction limits")
+
+// isArchive reports whether a path is an archive the scanner can unpack
+func isArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".zip") ||
+		strings.HasSuffix(lower, ".tar.gz") ||
+		strings.HasSuffix(lower, ".tgz")
+}
+
+// ScanArchive unpacks a .zip or .tar.gz archive into a temporary directory,
+// scans it, and returns files with archive-relative paths such as
+// "vendor.zip::src/main.go". The temporary directory is removed afterwards.
+func (s *Scanner) ScanArchive(archivePath string) ([]*FileInfo, error) {
+	tmpDir, err := os.MkdirTemp("", "churn-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	skipped, err := extractArchive(archivePath, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	inner := NewScanner(tmpDir, s.ignorePatterns)
+	inner.extensionOverrides = s.extensionOverrides
+	inner.maxFileBytes = s.maxFileBytes
+
+	files, err := inner.Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	s.statsMu.Lock()
+	innerStats := inner.Stats()
+	s.stats.SkippedTooLarge += innerStats.SkippedTooLarge + skipped
+	s.stats.SkippedBinary += innerStats.SkippedBinary
+	s.statsMu.Unlock()
+
+	for _, file := range files {
+		relPath, err := filepath.Rel(tmpDir, file.Path)
+		if err != nil {
+			continue
+		}
+		file.Path = archivePath + ArchiveSeparator + filepath.ToSlash(relPath)
+	}
+
+	return files, nil
+}
+
+// ReadFileContent reads a scanned file, transparently reading entries of
+// archives scanned with ScanArchive
+func ReadFileContent(path string) ([]byte, error) {
+	archivePath, entryPath, ok := strings.Cut(path, ArchiveSeparator)
+	if !ok {
+		return os.ReadFile(path)
+	}
+	return readArchiveEntry(archivePath, entryPath)
+}
+
+// extractArchive unpacks an archive into dest and returns how many entries
+// were skipped for exceeding maxArchiveEntryBytes
+func extractArchive(archivePath, dest string) (int, error) {
+	x := &archiveExtractor{dest: dest}
+
+	var err error
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		err = x.extractZip(archivePath)
+	} else {
+		err = x.extractTarGz(archivePath)
+	}
+	return x.skipped, err
+}
+
+// archiveExtractor writes archive entries below dest while enforcing the
+// extraction limits
+type archiveExtractor struct {
+	dest    string
+	entries int
+	written int64
+	skipped int
+}
+
+// extractZip unpacks a zip archive
+func (x *archiveExtractor) extractZip(archivePath string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		if entry.UncompressedSize64 > maxArchiveEntryBytes {
+			x.skipped++
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			continue
+		}
+		err = x.write(entry.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractTarGz unpacks a gzipped tar archive
+func (x *archiveExtractor) extractTarGz(archivePath string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxArchiveEntryBytes {
+			x.skipped++
+			continue
+		}
+
+		if err := x.write(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// write extracts one archive entry below dest, refusing entries that would
+// escape the destination directory. The declared entry size isn't trusted:
+// an entry that turns out larger than the limit is removed and skipped.
+func (x *archiveExtractor) write(name string, r io.Reader) error {
+	target := filepath.Join(x.dest, filepath.FromSlash(name))
+	if !strings.HasPrefix(target, filepath.Clean(x.dest)+string(os.PathSeparator)) {
+		return nil // Skip path traversal entries
+	}
+
+	x.entries++
+	if x.entries > maxArchiveEntries {
+		return fmt.Errorf("%w (more than %d entries)", errArchiveTooLarge, maxArchiveEntries)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	// Read one byte past the limits to tell a full entry from a cut-off one
+	limit := min(maxArchiveEntryBytes, maxArchiveTotalBytes-x.written) + 1
+	n, err := io.Copy(out, io.LimitReader(r, limit))
+	out.Close()
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	if n > maxArchiveEntryBytes {
+		x.skipped++
+		return os.Remove(target)
+	}
+	x.written += n
+	if x.written > maxArchiveTotalBytes {
+		return fmt.Errorf("%w (more than %d MB extracted)", errArchiveTooLarge, maxArchiveTotalBytes/(1024*1024))
+	}
+
+	return nil
+}
+
+// readArchiveEntry reads a single file out of an archive
+func readArchiveEntry(archivePath, entryPath string) ([]byte, error) {
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		reader, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip archive: %w", err)
+		}
+		defer reader.Close()
+
+		for _, entry := range reader.File {
+			if filepath.ToSlash(filepath.Clean(entry.Name)) != entryPath {
+				continue
+			}
+			rc, err := entry.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return readArchiveData(rc)
+		}
+		return nil, fmt.Errorf("%s not found in %s", entryPath, archivePath)
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in %s", entryPath, archivePath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if filepath.ToSlash(filepath.Clean(header.Name)) == entryPath {
+			return readArchiveData(tr)
+		}
+	}
+}
+
+// readArchiveData reads an archive entry, refusing entries over
+// maxArchiveEntryBytes rather than returning them cut off
+func readArchiveData(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveEntryBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveEntryBytes {
+		return nil, errFileTooLarge
+	}
+	return data, nil
+}
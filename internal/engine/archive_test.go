@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a zip archive holding the named entries
+func writeZip(t *testing.T, path string, entries map[string][]byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanArchiveSkipsOversizedEntries(t *testing.T) {
+	root := t.TempDir()
+	writeZip(t, filepath.Join(root, "vendor.zip"), map[string][]byte{
+		"src/main.go": []byte("package main\n"),
+		"src/big.go":  bytes.Repeat([]byte("/"), maxArchiveEntryBytes+1),
+	})
+
+	scanner := NewScanner(root, nil)
+	scanner.SetScanArchives(true)
+	scanner.SetMaxFileBytes(-1)
+	files, err := scanner.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if len(files) != 1 || !strings.HasSuffix(files[0].Path, ArchiveSeparator+"src/main.go") {
+		t.Errorf("Scan() = %d files, want only src/main.go", len(files))
+	}
+	if got := scanner.Stats().SkippedTooLarge; got != 1 {
+		t.Errorf("SkippedTooLarge = %d, want 1", got)
+	}
+
+	if _, err := ReadFileContent(filepath.Join(root, "vendor.zip") + ArchiveSeparator + "src/big.go"); err == nil {
+		t.Error("ReadFileContent() returned an oversized entry")
+	}
+}
+
+func TestScanArchiveLimitsEntryCount(t *testing.T) {
+	root := t.TempDir()
+	entries := make(map[string][]byte, maxArchiveEntries+1)
+	for i := 0; i <= maxArchiveEntries; i++ {
+		entries[fmt.Sprintf("f%d.go", i)] = []byte("package f\n")
+	}
+	writeZip(t, filepath.Join(root, "bomb.zip"), entries)
+
+	scanner := NewScanner(root, nil)
+	scanner.SetScanArchives(true)
+	files, err := scanner.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if len(files) != 0 {
+		t.Errorf("Scan() = %d files, want none", len(files))
+	}
+	warnings := scanner.Stats().Warnings
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "bomb.zip") {
+		t.Errorf("Warnings = %q, want one about bomb.zip", warnings)
+	}
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Baseline is a set of known findings, identified by HashFinding, that are
+// left out of reports so only newly introduced findings show up
+type Baseline struct {
+	CreatedAt time.Time `json:"created_at"`
+	Hashes    []string  `json:"hashes"`
+
+	index map[string]bool
+}
+
+// BaselinePath returns the location of the project's baseline file
+func BaselinePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".churn", "baseline.json")
+}
+
+// SaveBaseline replaces the project's baseline with the given findings
+func SaveBaseline(projectRoot string, findings []*Finding) error {
+	baseline := &Baseline{}
+	baseline.Add(findings)
+	return baseline.Save(projectRoot)
+}
+
+// LoadBaseline reads the project's baseline. A missing file yields an empty baseline.
+func LoadBaseline(projectRoot string) (*Baseline, error) {
+	data, err := os.ReadFile(BaselinePath(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Baseline{}, nil
+		}
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+
+	return &baseline, nil
+}
+
+// Add records findings in the baseline
+func (b *Baseline) Add(findings []*Finding) {
+	b.buildIndex()
+	for _, finding := range findings {
+		hash := HashFinding(finding)
+		if !b.index[hash] {
+			b.index[hash] = true
+			b.Hashes = append(b.Hashes, hash)
+		}
+	}
+}
+
+// Contains reports whether a finding is in the baseline
+func (b *Baseline) Contains(finding *Finding) bool {
+	b.buildIndex()
+	return b.index[HashFinding(finding)]
+}
+
+// Len returns the number of findings in the baseline
+func (b *Baseline) Len() int {
+	return len(b.Hashes)
+}
+
+// Filter returns the findings not in the baseline and how many were dropped
+func (b *Baseline) Filter(findings []*Finding) ([]*Finding, int) {
+	kept := make([]*Finding, 0, len(findings))
+	for _, finding := range findings {
+		if !b.Contains(finding) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept, len(findings) - len(kept)
+}
+
+// Save writes the baseline to .churn/baseline.json
+func (b *Baseline) Save(projectRoot string) error {
+	path := BaselinePath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create baseline directory: %w", err)
+	}
+
+	// Sorted so the file diffs cleanly when committed
+	sort.Strings(b.Hashes)
+	b.CreatedAt = time.Now()
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	return nil
+}
+
+// buildIndex builds the lookup set from Hashes on first use
+func (b *Baseline) buildIndex() {
+	if b.index != nil {
+		return
+	}
+	b.index = make(map[string]bool, len(b.Hashes))
+	for _, hash := range b.Hashes {
+		b.index[hash] = true
+	}
+}
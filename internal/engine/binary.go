@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// binarySniffBytes is how much of a file is inspected for binary content
+const binarySniffBytes = 8 * 1024
+
+// maxInvalidUTF8Ratio is the share of invalid UTF-8 bytes above which
+// content is treated as binary
+const maxInvalidUTF8Ratio = 0.3
+
+// IsBinaryContent reports whether data looks like binary rather than text:
+// it contains a NUL byte or mostly bytes that are not valid UTF-8
+func IsBinaryContent(data []byte) bool {
+	if len(data) > binarySniffBytes {
+		data = data[:binarySniffBytes]
+	}
+	if len(data) == 0 {
+		return false
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+
+	invalid := 0
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			// A rune cut off by the sniff window is not evidence of binary
+			if len(data)-i < utf8.UTFMax && !utf8.FullRune(data[i:]) {
+				break
+			}
+			invalid++
+		}
+		i += size
+	}
+
+	return float64(invalid)/float64(len(data)) > maxInvalidUTF8Ratio
+}
+
+// IsBinaryFile reads the start of a file and reports whether it is binary
+func IsBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, binarySniffBytes)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+
+	return IsBinaryContent(buf[:n]), nil
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uncommittedSHA is the commit git blame reports for lines not yet committed
+const uncommittedSHA = "0000000000000000000000000000000000000000"
+
+// AnnotateBlame attaches the last commit to touch each finding's lines, as
+// reported by git blame. When several commits touched the range, the most
+// recent one is used. Findings in untracked files, on uncommitted lines, or
+// outside a git repository are left without blame.
+func AnnotateBlame(projectRoot string, findings []*Finding) {
+	if _, err := runGit(projectRoot, "rev-parse", "--git-dir"); err != nil {
+		return
+	}
+
+	for _, finding := range findings {
+		path := finding.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+
+		blame, err := gitBlame(projectRoot, path, finding.LineStart, finding.LineEnd)
+		if err != nil {
+			continue
+		}
+		finding.Blame = blame
+	}
+}
+
+// gitBlame returns the most recent commit to touch lines start-end of path.
+// It fails when the file is untracked or none of the lines are committed.
+func gitBlame(projectRoot, path string, start, end int) (*BlameInfo, error) {
+	start = max(start, 1)
+	end = max(end, start)
+
+	out, err := runGit(projectRoot, "blame", "--line-porcelain",
+		"-L", fmt.Sprintf("%d,%d", start, end), "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *BlameInfo
+	var current BlameInfo
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The line's content ends its entry
+			if current.Commit != uncommittedSHA && (latest == nil || current.CommitDate.After(latest.CommitDate)) {
+				entry := current
+				latest = &entry
+			}
+			current = BlameInfo{}
+
+		case current.Commit == "":
+			if sha, _, ok := strings.Cut(line, " "); ok {
+				current.Commit = sha
+			}
+
+		case strings.HasPrefix(line, "author "):
+			current.Author = strings.TrimPrefix(line, "author ")
+
+		case strings.HasPrefix(line, "committer-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "committer-time "), 10, 64); err == nil {
+				current.CommitDate = time.Unix(seconds, 0).UTC()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git blame: %w", err)
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("no committed lines")
+	}
+	if len(latest.Commit) > 7 {
+		latest.Commit = latest.Commit[:7]
+	}
+	return latest, nil
+}
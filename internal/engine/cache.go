@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache stores raw LLM responses on disk so unchanged files are not
+// re-sent to the provider
+type ResponseCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+
+	mu sync.Mutex
+}
+
+// cacheEntry is the on-disk representation of a cached response
+type cacheEntry struct {
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewResponseCache creates a cache in dir. A zero ttl never expires entries
+// and a zero maxBytes disables eviction.
+func NewResponseCache(dir string, ttl time.Duration, maxBytes int64) *ResponseCache {
+	return &ResponseCache{
+		dir:      dir,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+	}
+}
+
+// CacheDir returns the directory a project's response cache is stored in
+func CacheDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".churn", "cache")
+}
+
+// CacheSize returns the bytes used by a project's response cache on disk
+func CacheSize(projectRoot string) (int64, error) {
+	entries, err := os.ReadDir(CacheDir(projectRoot))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			total += info.Size()
+		}
+	}
+	return total, nil
+}
+
+// ClearCache deletes a project's response cache
+func ClearCache(projectRoot string) error {
+	if err := os.RemoveAll(CacheDir(projectRoot)); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// CacheKey hashes everything that influences an LLM response
+func CacheKey(provider string, opts RequestOptions, content, pass string) string {
+	sampling := fmt.Sprintf("%g/%d", opts.Temperature, opts.MaxTokens)
+
+	h := sha256.New()
+	for _, part := range []string{provider, opts.Model, opts.SystemPrompt, sampling, content, pass} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// Get returns a cached response that has not expired
+func (c *ResponseCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.entryPath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		os.Remove(path)
+		return "", false
+	}
+
+	if c.ttl > 0 && time.Since(entry.CreatedAt) > c.ttl {
+		os.Remove(path)
+		return "", false
+	}
+
+	// Bump the modification time so eviction is least recently used
+	now := time.Now()
+	os.Chtimes(path, now, now)
+
+	return entry.Response, true
+}
+
+// Put stores a response and evicts old entries if the cache is too large
+func (c *ResponseCache) Put(key, response string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cacheEntry{Response: response, CreatedAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	// Write atomically so concurrent readers never see a partial entry
+	path := c.entryPath(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return c.evict()
+}
+
+// evict removes least recently used entries until the cache fits maxBytes
+func (c *ResponseCache) evict() error {
+	if c.maxBytes <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	type cachedFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var files []cachedFile
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{
+			path:    filepath.Join(c.dir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, file := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(file.path); err == nil {
+			total -= file.size
+		}
+	}
+
+	return nil
+}
+
+// entryPath returns the file that stores key
+func (c *ResponseCache) entryPath(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
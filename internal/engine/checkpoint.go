@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrCheckpointUnavailable marks the warning sent when the checkpoint can't
+// be written and the run carries on without one
+var ErrCheckpointUnavailable = errors.New("checkpoint unavailable")
+
+// Checkpoint records each file a pass has finished, with its findings, so an
+// interrupted run can resume without paying for those files again. Entries
+// are keyed by pass, model, and path, and only reused while the file's
+// content hash matches.
+type Checkpoint struct {
+	path string
+
+	mu       sync.Mutex
+	entries  map[string]checkpointEntry
+	disabled bool
+}
+
+// checkpointEntry is one line of the checkpoint file
+type checkpointEntry struct {
+	Pass        string     `json:"pass"`
+	Model       string     `json:"model"`
+	Path        string     `json:"path"`
+	ContentHash string     `json:"content_hash"`
+	Findings    []*Finding `json:"findings"`
+}
+
+// CheckpointPath returns the location of the project's run checkpoint
+func CheckpointPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".churn", "state", "checkpoint.jsonl")
+}
+
+// HasCheckpoint reports whether an unfinished run left a checkpoint
+func HasCheckpoint(projectRoot string) bool {
+	info, err := os.Stat(CheckpointPath(projectRoot))
+	return err == nil && info.Size() > 0
+}
+
+// LoadCheckpoint reads the project's checkpoint. A missing file yields an
+// empty checkpoint, and a line cut short by a crash is ignored.
+func LoadCheckpoint(projectRoot string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{
+		path:    CheckpointPath(projectRoot),
+		entries: make(map[string]checkpointEntry),
+	}
+
+	data, err := os.ReadFile(checkpoint.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return checkpoint, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	// Cut off a partial last line so new entries start on a line of their own
+	if end := bytes.LastIndexByte(data, '\n') + 1; end < len(data) {
+		if err := os.Truncate(checkpoint.path, int64(end)); err != nil {
+			return nil, fmt.Errorf("failed to repair checkpoint: %w", err)
+		}
+		data = data[:end]
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var entry checkpointEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		checkpoint.entries[checkpointKey(entry.Pass, entry.Model, entry.Path)] = entry
+	}
+
+	return checkpoint, nil
+}
+
+// NewCheckpoint starts an empty checkpoint, discarding any left by an
+// earlier run
+func NewCheckpoint(projectRoot string) (*Checkpoint, error) {
+	if err := RemoveCheckpoint(projectRoot); err != nil {
+		return nil, err
+	}
+	return &Checkpoint{
+		path:    CheckpointPath(projectRoot),
+		entries: make(map[string]checkpointEntry),
+	}, nil
+}
+
+// RemoveCheckpoint deletes the project's checkpoint, e.g. once a run has
+// finished and its report is saved
+func RemoveCheckpoint(projectRoot string) error {
+	if err := os.Remove(CheckpointPath(projectRoot)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the findings recorded for a file, provided its content is
+// unchanged. The findings are copies, so the caller may modify them.
+func (c *Checkpoint) Lookup(pass *Pass, path, contentHash string) ([]*Finding, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[checkpointKey(pass.Name, pass.Model, path)]
+	c.mu.Unlock()
+	if !ok || entry.ContentHash != contentHash {
+		return nil, false
+	}
+
+	findings := make([]*Finding, len(entry.Findings))
+	for i, finding := range entry.Findings {
+		copied := *finding
+		findings[i] = &copied
+	}
+	return findings, true
+}
+
+// Record appends a finished file to the checkpoint file
+func (c *Checkpoint) Record(pass *Pass, path, contentHash string, findings []*Finding) error {
+	entry := checkpointEntry{
+		Pass:        pass.Name,
+		Model:       pass.Model,
+		Path:        path,
+		ContentHash: contentHash,
+		Findings:    findings,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint entry: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.disabled {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	file, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	c.entries[checkpointKey(pass.Name, pass.Model, path)] = entry
+	return nil
+}
+
+// disable stops further writes after a failure, reporting whether this call
+// disabled it so the failure is only reported once
+func (c *Checkpoint) disable() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled {
+		return false
+	}
+	c.disabled = true
+	return true
+}
+
+// contentHash returns the sha256 of a file's content, or "" when it can't
+// be read
+func contentHash(path string) string {
+	content, err := ReadFileContent(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content))
+}
+
+func checkpointKey(pass, model, path string) string {
+	return pass + "\x00" + model + "\x00" + path
+}
@@ -0,0 +1,55 @@
+package engine
+
+// ReportDiff describes how findings changed between two reports
+type ReportDiff struct {
+	Added     []*Finding // In the new report only: regressions
+	Removed   []*Finding // In the old report only: fixed
+	Unchanged []*Finding // In both reports
+}
+
+// CompareReports matches findings between two reports by HashFinding. A nil
+// old report treats every finding in the new one as added.
+func CompareReports(oldReport, newReport *AnalysisReport) *ReportDiff {
+	diff := &ReportDiff{
+		Added:     make([]*Finding, 0),
+		Removed:   make([]*Finding, 0),
+		Unchanged: make([]*Finding, 0),
+	}
+
+	oldHashes := make(map[string]bool)
+	if oldReport != nil {
+		for _, finding := range oldReport.Findings {
+			oldHashes[HashFinding(finding)] = true
+		}
+	}
+
+	newHashes := make(map[string]bool)
+	if newReport != nil {
+		for _, finding := range newReport.Findings {
+			hash := HashFinding(finding)
+			if newHashes[hash] {
+				continue
+			}
+			newHashes[hash] = true
+
+			if oldHashes[hash] {
+				diff.Unchanged = append(diff.Unchanged, finding)
+			} else {
+				diff.Added = append(diff.Added, finding)
+			}
+		}
+	}
+
+	if oldReport != nil {
+		for _, finding := range oldReport.Findings {
+			hash := HashFinding(finding)
+			if !newHashes[hash] {
+				// Mark so duplicates in the old report are listed once
+				newHashes[hash] = true
+				diff.Removed = append(diff.Removed, finding)
+			}
+		}
+	}
+
+	return diff
+}
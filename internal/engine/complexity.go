@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// HighComplexityThreshold is the cyclomatic complexity from which a function
+// is called out in analysis prompts
+const HighComplexityThreshold = 10
+
+// functionSpan is a function's location and cyclomatic complexity
+type functionSpan struct {
+	Name       string
+	StartLine  int
+	EndLine    int
+	Complexity int
+}
+
+// ComputeCyclomaticComplexity returns the cyclomatic complexity of each
+// function in content, keyed by function name. Go is parsed properly; other
+// languages are approximated with regular expressions.
+func ComputeCyclomaticComplexity(content, language string) map[string]int {
+	result := make(map[string]int)
+	for _, fn := range analyzeFunctions(content, language) {
+		result[fn.Name] = fn.Complexity
+	}
+	return result
+}
+
+// enclosingComplexity returns the complexity of the function containing line, or 0
+func enclosingComplexity(spans []functionSpan, line int) int {
+	for _, fn := range spans {
+		if line >= fn.StartLine && line <= fn.EndLine {
+			return fn.Complexity
+		}
+	}
+	return 0
+}
+
+// analyzeFunctions locates functions and computes their complexity
+func analyzeFunctions(content, language string) []functionSpan {
+	if language == "go" {
+		if spans, err := analyzeGoFunctions(content); err == nil {
+			return spans
+		}
+	}
+	return analyzeFunctionsByPattern(content, language)
+}
+
+// analyzeGoFunctions walks the Go AST counting decision points per function
+func analyzeGoFunctions(content string) ([]functionSpan, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Go source: %w", err)
+	}
+
+	var spans []functionSpan
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		complexity := 1
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+				complexity++
+			case *ast.CaseClause:
+				if node.List != nil { // default does not add a path
+					complexity++
+				}
+			case *ast.CommClause:
+				if node.Comm != nil {
+					complexity++
+				}
+			case *ast.BinaryExpr:
+				if node.Op == token.LAND || node.Op == token.LOR {
+					complexity++
+				}
+			}
+			return true
+		})
+
+		spans = append(spans, functionSpan{
+			Name:       goFuncName(fn),
+			StartLine:  fset.Position(fn.Pos()).Line,
+			EndLine:    fset.Position(fn.End()).Line,
+			Complexity: complexity,
+		})
+	}
+
+	return spans, nil
+}
+
+// goFuncName returns a function name, qualified by receiver type for methods
+func goFuncName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if index, ok := recv.(*ast.IndexExpr); ok {
+		recv = index.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+var (
+	// funcDeclPatterns match a function declaration, capturing its name
+	funcDeclPatterns = map[string]*regexp.Regexp{
+		"python":     regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)`),
+		"ruby":       regexp.MustCompile(`^\s*def\s+(?:self\.)?(\w+[?!]?)`),
+		"rust":       regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+(\w+)`),
+		"kotlin":     regexp.MustCompile(`^\s*(?:\w+\s+)*fun\s+(?:<[^>]*>\s*)?(?:\w+\.)?(\w+)`),
+		"swift":      regexp.MustCompile(`^\s*(?:\w+\s+)*func\s+(\w+)`),
+		"php":        regexp.MustCompile(`^\s*(?:\w+\s+)*function\s+(\w+)`),
+		"javascript": regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*(\w+)|^\s*(?:export\s+)?(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s+)?(?:function|\([^)]*\)\s*=>|\w+\s*=>)`),
+	}
+
+	// cLikeFuncDecl approximates a function definition in C-family languages
+	cLikeFuncDecl = regexp.MustCompile(`^\s*(?:[\w<>\[\]*&:,]+\s+)+\**(\w+)\s*\([^;]*\)\s*(?:const\s*)?(?:throws\s+[\w.,\s]+)?\{?\s*$`)
+
+	// decisionPatterns match the constructs that add an execution path
+	defaultDecisions = regexp.MustCompile(`\b(?:if|for|foreach|while|case|catch)\b|&&|\|\|`)
+	pythonDecisions  = regexp.MustCompile(`\b(?:if|elif|for|while|except|and|or)\b`)
+	rubyDecisions    = regexp.MustCompile(`\b(?:if|elsif|unless|for|while|until|when|rescue)\b|&&|\|\|`)
+
+	// controlKeywords are names a C-like pattern may mistake for functions
+	controlKeywords = map[string]bool{
+		"if": true, "for": true, "while": true, "switch": true, "catch": true, "return": true, "else": true,
+	}
+)
+
+// analyzeFunctionsByPattern approximates function boundaries with regular
+// expressions: each function runs until the next declaration starts
+func analyzeFunctionsByPattern(content, language string) []functionSpan {
+	declPattern, ok := funcDeclPatterns[language]
+	if language == "typescript" {
+		declPattern, ok = funcDeclPatterns["javascript"], true
+	}
+	if !ok {
+		declPattern = cLikeFuncDecl
+	}
+
+	decisions := defaultDecisions
+	switch language {
+	case "python":
+		decisions = pythonDecisions
+	case "ruby":
+		decisions = rubyDecisions
+	}
+
+	lines := strings.Split(content, "\n")
+	var spans []functionSpan
+	for i, line := range lines {
+		match := declPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		name := ""
+		for _, group := range match[1:] {
+			if group != "" {
+				name = group
+				break
+			}
+		}
+		if name == "" || controlKeywords[name] {
+			continue
+		}
+
+		if len(spans) > 0 {
+			spans[len(spans)-1].EndLine = i
+		}
+		spans = append(spans, functionSpan{Name: name, StartLine: i + 1, EndLine: len(lines)})
+	}
+
+	for i := range spans {
+		complexity := 1
+		// The declaration line itself is skipped so `function if()` style
+		// false positives do not count
+		for _, line := range lines[spans[i].StartLine:spans[i].EndLine] {
+			complexity += len(decisions.FindAllString(stripLineComment(line, language), -1))
+		}
+		spans[i].Complexity = complexity
+	}
+
+	return spans
+}
+
+// stripLineComment drops a trailing line comment so commented-out code is not counted
+func stripLineComment(line, language string) string {
+	marker := "//"
+	switch language {
+	case "python", "ruby", "bash", "zsh":
+		marker = "#"
+	}
+	if idx := strings.Index(line, marker); idx >= 0 {
+		return line[:idx]
+	}
+	return line
+}
+
+// complexityNotes describes the functions in content that exceed the threshold
+func complexityNotes(content, language string) string {
+	spans := analyzeFunctions(content, language)
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Complexity > spans[j].Complexity
+	})
+
+	var notes strings.Builder
+	for _, fn := range spans {
+		if fn.Complexity < HighComplexityThreshold {
+			break
+		}
+		notes.WriteString(fmt.Sprintf("- %s (lines %d-%d) has cyclomatic complexity %d — analyze it for refactoring opportunities.\n",
+			fn.Name, fn.StartLine, fn.EndLine, fn.Complexity))
+	}
+	return notes.String()
+}
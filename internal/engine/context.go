@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -128,9 +129,111 @@ func (cb *ContextBuilder) detectFrameworks() []string {
 		}
 	}
 
+	// Check pyproject.toml for Python frameworks (PEP 621, Poetry, PDM)
+	if deps := cb.readManifest("pyproject.toml", parsePyprojectDependencies); deps != nil {
+		frameworks = appendFrameworks(frameworks, deps, map[string]string{
+			"django":  "Django",
+			"flask":   "Flask",
+			"fastapi": "FastAPI",
+		})
+	}
+
+	// Check Cargo.toml for Rust frameworks
+	if deps := cb.readManifest("Cargo.toml", parseCargoDependencies); deps != nil {
+		frameworks = appendFrameworks(frameworks, deps, map[string]string{
+			"actix-web": "Actix Web",
+			"axum":      "Axum",
+			"rocket":    "Rocket",
+		})
+	}
+
+	// Check pom.xml and Gradle builds for Spring
+	for _, manifest := range []string{"pom.xml", "build.gradle", "build.gradle.kts"} {
+		parse := parseGradleDependencies
+		if manifest == "pom.xml" {
+			parse = parseMavenDependencies
+		}
+		if framework := springFramework(cb.readManifest(manifest, parse)); framework != "" {
+			frameworks = append(frameworks, framework)
+		}
+	}
+
+	// Check Gemfile for Ruby frameworks
+	if deps := cb.readManifest("Gemfile", parseGemfileDependencies); deps != nil {
+		frameworks = appendFrameworks(frameworks, deps, map[string]string{
+			"rails":   "Rails",
+			"sinatra": "Sinatra",
+		})
+	}
+
+	// Check composer.json for PHP frameworks
+	if deps := cb.readManifest("composer.json", parseComposerDependencies); deps != nil {
+		frameworks = appendFrameworks(frameworks, deps, map[string]string{
+			"laravel/framework":        "Laravel",
+			"symfony/framework-bundle": "Symfony",
+			"symfony/symfony":          "Symfony",
+		})
+	}
+
+	return uniqueFrameworks(frameworks)
+}
+
+// readManifest parses a manifest in the project root, returning nil when it
+// does not exist or cannot be read
+func (cb *ContextBuilder) readManifest(name string, parse func([]byte) map[string]string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(cb.rootPath, name))
+	if err != nil {
+		return nil
+	}
+	return parse(data)
+}
+
+// appendFrameworks appends the frameworks whose package is among deps, in a
+// stable order
+func appendFrameworks(frameworks []string, deps map[string]string, known map[string]string) []string {
+	packages := make([]string, 0, len(known))
+	for pkg := range known {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	for _, pkg := range packages {
+		if _, ok := deps[pkg]; ok {
+			frameworks = append(frameworks, known[pkg])
+		}
+	}
 	return frameworks
 }
 
+// springFramework reports Spring Boot or plain Spring from Maven/Gradle
+// coordinates and plugin ids
+func springFramework(deps map[string]string) string {
+	framework := ""
+	for name := range deps {
+		if strings.HasPrefix(name, "org.springframework.boot") {
+			return "Spring Boot"
+		}
+		if strings.HasPrefix(name, "org.springframework") {
+			framework = "Spring"
+		}
+	}
+	return framework
+}
+
+// uniqueFrameworks drops repeated frameworks, such as Django listed in both
+// requirements.txt and pyproject.toml, keeping the first occurrence
+func uniqueFrameworks(frameworks []string) []string {
+	seen := make(map[string]bool, len(frameworks))
+	unique := frameworks[:0]
+	for _, framework := range frameworks {
+		if !seen[framework] {
+			seen[framework] = true
+			unique = append(unique, framework)
+		}
+	}
+	return unique
+}
+
 // detectTools identifies development tools used
 func (cb *ContextBuilder) detectTools() []string {
 	tools := make([]string, 0)
@@ -180,5 +283,23 @@ func (cb *ContextBuilder) extractDependencies() map[string]string {
 		}
 	}
 
+	// Extract from other ecosystems; earlier manifests win on name clashes
+	manifests := []struct {
+		name  string
+		parse func([]byte) map[string]string
+	}{
+		{"go.mod", parseGoModDependencies},
+		{"requirements.txt", parseRequirementsDependencies},
+		{"pyproject.toml", parsePyprojectDependencies},
+		{"Cargo.toml", parseCargoDependencies},
+	}
+	for _, manifest := range manifests {
+		for name, version := range cb.readManifest(manifest.name, manifest.parse) {
+			if _, ok := deps[name]; !ok {
+				deps[name] = version
+			}
+		}
+	}
+
 	return deps
 }
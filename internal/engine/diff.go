@@ -1,17 +1,33 @@
 package engine
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+// DefaultDiffContextLines is the number of unchanged lines shown around
+// each change, matching `diff -U3`
+const DefaultDiffContextLines = 3
+
 // DiffEngine generates diffs between original and suggested code
-type DiffEngine struct{}
+type DiffEngine struct {
+	contextLines int
+}
 
 // NewDiffEngine creates a new diff engine
 func NewDiffEngine() *DiffEngine {
-	return &DiffEngine{}
+	return &DiffEngine{contextLines: DefaultDiffContextLines}
+}
+
+// SetContextLines sets how many unchanged lines surround each change
+func (de *DiffEngine) SetContextLines(n int) {
+	if n < 0 {
+		n = 0
+	}
+	de.contextLines = n
 }
 
 // Diff represents a unified diff
@@ -45,12 +61,12 @@ const (
 	DiffLineContext DiffLineType = "context"
 )
 
-// Generate creates a unified diff between original and modified content
+// Generate creates a unified diff between original and modified content.
+// Line endings are not part of the diff; Apply keeps the file's own.
 func (de *DiffEngine) Generate(filePath, original, modified string) (*Diff, error) {
-	originalLines := splitLines(original)
-	modifiedLines := splitLines(modified)
+	originalLines := trimCRs(splitLines(original))
+	modifiedLines := trimCRs(splitLines(modified))
 
-	// Simple line-by-line diff (can be enhanced with proper diff algorithm like Myers)
 	hunks := de.generateHunks(originalLines, modifiedLines)
 
 	return &Diff{
@@ -59,70 +75,165 @@ func (de *DiffEngine) Generate(filePath, original, modified string) (*Diff, erro
 	}, nil
 }
 
-// generateHunks creates diff hunks from original and modified lines
+// generateHunks creates diff hunks from original and modified lines. Changes
+// are grouped with surrounding context; a run of unchanged lines longer than
+// twice the context splits the diff into separate hunks.
 func (de *DiffEngine) generateHunks(original, modified []string) []*DiffHunk {
-	// This is a simplified diff implementation
-	// For production, consider using a proper diff library like go-diff
-
 	if len(original) == 0 && len(modified) == 0 {
 		return []*DiffHunk{}
 	}
 
-	// Create a single hunk for simplicity
-	hunk := &DiffHunk{
-		OriginalStart: 1,
-		OriginalLines: len(original),
-		ModifiedStart: 1,
-		ModifiedLines: len(modified),
-		Lines:         make([]*DiffLine, 0),
+	lines := myersDiff(original, modified)
+
+	// Find the changed lines, then the spans of lines each hunk covers
+	var spans [][2]int
+	for i, line := range lines {
+		if line.Type == DiffLineContext {
+			continue
+		}
+		from := max(i-de.contextLines, 0)
+		to := min(i+de.contextLines+1, len(lines))
+		if len(spans) > 0 && from <= spans[len(spans)-1][1] {
+			spans[len(spans)-1][1] = to
+		} else {
+			spans = append(spans, [2]int{from, to})
+		}
+	}
+
+	hunks := make([]*DiffHunk, 0, len(spans))
+	for _, span := range spans {
+		hunks = append(hunks, newHunk(lines, span[0], span[1]))
+	}
+
+	return hunks
+}
+
+// newHunk builds a hunk from lines[from:to], computing its @@ ranges
+func newHunk(lines []*DiffLine, from, to int) *DiffHunk {
+	hunk := &DiffHunk{Lines: lines[from:to]}
+
+	// Count the lines on each side that precede the hunk
+	origBefore, modBefore := 0, 0
+	for _, line := range lines[:from] {
+		if line.Type != DiffLineAdded {
+			origBefore++
+		}
+		if line.Type != DiffLineRemoved {
+			modBefore++
+		}
+	}
+
+	for _, line := range hunk.Lines {
+		if line.Type != DiffLineAdded {
+			hunk.OriginalLines++
+		}
+		if line.Type != DiffLineRemoved {
+			hunk.ModifiedLines++
+		}
 	}
 
-	// Simple implementation: mark all original lines as removed, all new as added
-	maxLen := len(original)
-	if len(modified) > maxLen {
-		maxLen = len(modified)
+	// Unified diffs number an empty side by the line it follows
+	hunk.OriginalStart = origBefore + 1
+	if hunk.OriginalLines == 0 {
+		hunk.OriginalStart = origBefore
+	}
+	hunk.ModifiedStart = modBefore + 1
+	if hunk.ModifiedLines == 0 {
+		hunk.ModifiedStart = modBefore
 	}
 
-	for i := 0; i < maxLen; i++ {
-		if i < len(original) && i < len(modified) {
-			if original[i] == modified[i] {
-				// Context line
-				hunk.Lines = append(hunk.Lines, &DiffLine{
-					Type:    DiffLineContext,
-					Content: original[i],
-					LineNum: i + 1,
-				})
+	return hunk
+}
+
+// myersDiff computes a minimal edit script between a and b using Myers'
+// O(ND) algorithm. Removed and context lines carry their original line
+// number; added lines carry the original line they are inserted before.
+func myersDiff(a, b []string) []*DiffLine {
+	n, m := len(a), len(b)
+	maxEdits := n + m
+	offset := maxEdits + 1
+
+	// v[k+offset] is the furthest x reached on diagonal k; trace keeps a copy
+	// per edit distance so the path can be walked back
+	v := make([]int, 2*maxEdits+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= maxEdits; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset] // Step down: insertion
 			} else {
-				// Line changed
-				hunk.Lines = append(hunk.Lines, &DiffLine{
-					Type:    DiffLineRemoved,
-					Content: original[i],
-					LineNum: i + 1,
-				})
-				hunk.Lines = append(hunk.Lines, &DiffLine{
-					Type:    DiffLineAdded,
-					Content: modified[i],
-					LineNum: i + 1,
-				})
+				x = v[k-1+offset] + 1 // Step right: deletion
 			}
-		} else if i < len(original) {
-			// Line removed
-			hunk.Lines = append(hunk.Lines, &DiffLine{
-				Type:    DiffLineRemoved,
-				Content: original[i],
-				LineNum: i + 1,
-			})
-		} else {
-			// Line added
-			hunk.Lines = append(hunk.Lines, &DiffLine{
-				Type:    DiffLineAdded,
-				Content: modified[i],
-				LineNum: len(original) + (i - len(original)) + 1,
-			})
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+offset] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v...))
+				break search
+			}
+		}
+		trace = append(trace, append([]int(nil), v...))
+	}
+
+	// Walk back from (n, m), collecting operations in reverse
+	var reversed []*DiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+
+		var prevK int
+		if d > 0 {
+			prev := trace[d-1]
+			if k == -d || (k != d && prev[k-1+offset] < prev[k+1+offset]) {
+				prevK = k + 1
+			} else {
+				prevK = k - 1
+			}
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = trace[d-1][prevK+offset]
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, &DiffLine{Type: DiffLineContext, Content: a[x], LineNum: x + 1})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				y--
+				reversed = append(reversed, &DiffLine{Type: DiffLineAdded, Content: b[y], LineNum: x + 1})
+			} else {
+				x--
+				reversed = append(reversed, &DiffLine{Type: DiffLineRemoved, Content: a[x], LineNum: x + 1})
+			}
+		}
+	}
+
+	lines := make([]*DiffLine, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+
+	// Within a change block, list removals before additions as unified diffs do
+	for i := 1; i < len(lines); i++ {
+		for j := i; j > 0 && lines[j].Type == DiffLineRemoved && lines[j-1].Type == DiffLineAdded; j-- {
+			lines[j], lines[j-1] = lines[j-1], lines[j]
 		}
 	}
 
-	return []*DiffHunk{hunk}
+	return lines
 }
 
 // FormatUnified formats a diff in unified diff format
@@ -182,22 +293,274 @@ func (d *Diff) GetChangeCount() (additions int, deletions int) {
 	return
 }
 
-// splitLines splits content into lines
+// Apply applies the diff to original content, verifying that context and
+// removed lines still match. Unchanged lines are kept byte for byte, and
+// added lines use the file's line ending.
+func (d *Diff) Apply(original string) (string, error) {
+	lines := splitLines(original)
+	if joinLines(lines, original) != original {
+		// Never write back content that would not survive the round trip
+		return "", fmt.Errorf("cannot split the file into lines without losing content")
+	}
+	cr := ""
+	if len(lines) > 0 && strings.HasSuffix(lines[0], "\r") {
+		cr = "\r"
+	}
+
+	result := make([]string, 0, len(lines))
+	pos := 0
+
+	for _, hunk := range d.Hunks {
+		start := locateHunk(lines, hunk, pos)
+		if start < pos || start > len(lines) {
+			return "", fmt.Errorf("hunk at line %d is out of range", hunk.OriginalStart)
+		}
+		result = append(result, lines[pos:start]...)
+		pos = start
+
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case DiffLineAdded:
+				result = append(result, strings.TrimSuffix(line.Content, "\r")+cr)
+			case DiffLineRemoved, DiffLineContext:
+				if pos >= len(lines) || !sameLine(lines[pos], line.Content) {
+					return "", fmt.Errorf("patch does not match file at line %d", pos+1)
+				}
+				if line.Type == DiffLineContext {
+					result = append(result, lines[pos])
+				}
+				pos++
+			}
+		}
+	}
+
+	result = append(result, lines[pos:]...)
+
+	return joinLines(result, original), nil
+}
+
+var (
+	// diffFencePattern matches fenced ```diff or ```patch blocks
+	diffFencePattern = regexp.MustCompile("(?s)```(?:diff|patch|udiff)[^\n]*\n(.*?)```")
+
+	// hunkHeaderPattern matches "@@ -a,b +c,d @@", with optional counts
+	hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+)
+
+// ParseUnifiedDiff extracts a unified diff from an LLM response. Fenced
+// ```diff blocks are preferred; otherwise bare hunks are read from the whole
+// response. Surrounding prose is skipped, malformed hunk headers are treated
+// as unknown positions, and line counts are recomputed from the hunk body
+// since models often get them wrong.
+func ParseUnifiedDiff(response string) (*Diff, error) {
+	text := response
+	if blocks := diffFencePattern.FindAllStringSubmatch(response, -1); len(blocks) > 0 {
+		parts := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			parts = append(parts, block[1])
+		}
+		text = strings.Join(parts, "\n")
+	}
+
+	diff := &Diff{}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var hunk *DiffHunk
+	var origLine int
+	for i, line := range lines {
+		// File headers come in ---/+++ pairs; a lone "---" inside a hunk is a removal
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			hunk = nil
+			continue
+		}
+		if strings.HasPrefix(line, "+++ ") && hunk == nil {
+			if path := diffHeaderPath(line[4:]); path != "" && diff.FilePath == "" {
+				diff.FilePath = path
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "@@") {
+			hunk = parseHunkHeader(line)
+			origLine = hunk.OriginalStart
+			diff.Hunks = append(diff.Hunks, hunk)
+			continue
+		}
+
+		if hunk == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+"):
+			hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineAdded, Content: line[1:], LineNum: origLine})
+			hunk.ModifiedLines++
+		case strings.HasPrefix(line, "-"):
+			hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineRemoved, Content: line[1:], LineNum: origLine})
+			hunk.OriginalLines++
+			origLine++
+		case strings.HasPrefix(line, " ") || line == "":
+			// Models often strip the leading space from blank context lines
+			content := line
+			if content != "" {
+				content = content[1:]
+			}
+			hunk.Lines = append(hunk.Lines, &DiffLine{Type: DiffLineContext, Content: content, LineNum: origLine})
+			hunk.OriginalLines++
+			hunk.ModifiedLines++
+			origLine++
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			// Prose ends the hunk
+			hunk = nil
+		}
+	}
+
+	// Drop hunks that were only a header, and trailing blank context picked
+	// up from the gap between the diff and following prose
+	hunks := diff.Hunks[:0]
+	for _, h := range diff.Hunks {
+		trimTrailingBlankContext(h)
+		if len(h.Lines) > 0 {
+			hunks = append(hunks, h)
+		}
+	}
+	diff.Hunks = hunks
+
+	if len(diff.Hunks) == 0 {
+		return nil, fmt.Errorf("no unified diff hunks found in response")
+	}
+
+	return diff, nil
+}
+
+// parseHunkHeader reads a hunk's start positions; counts are recomputed
+// from the body, and a malformed header leaves the positions at zero
+func parseHunkHeader(line string) *DiffHunk {
+	hunk := &DiffHunk{Lines: make([]*DiffLine, 0)}
+
+	match := hunkHeaderPattern.FindStringSubmatch(line)
+	if match == nil {
+		return hunk
+	}
+	hunk.OriginalStart, _ = strconv.Atoi(match[1])
+	hunk.ModifiedStart, _ = strconv.Atoi(match[3])
+	return hunk
+}
+
+// diffHeaderPath extracts the path from a "+++ b/path" header
+func diffHeaderPath(header string) string {
+	// Headers may carry a tab-separated timestamp
+	path, _, _ := strings.Cut(strings.TrimSpace(header), "\t")
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+	return path
+}
+
+// trimTrailingBlankContext removes empty context lines at the end of a hunk
+func trimTrailingBlankContext(hunk *DiffHunk) {
+	for len(hunk.Lines) > 0 {
+		last := hunk.Lines[len(hunk.Lines)-1]
+		if last.Type != DiffLineContext || last.Content != "" {
+			return
+		}
+		hunk.Lines = hunk.Lines[:len(hunk.Lines)-1]
+		hunk.OriginalLines--
+		hunk.ModifiedLines--
+	}
+}
+
+// locateHunk returns the 0-based line where a hunk applies. The header
+// position is used when the hunk matches there; otherwise the nearest match
+// at or after pos is used, since LLM-written headers are often off.
+func locateHunk(lines []string, hunk *DiffHunk, pos int) int {
+	start := hunk.OriginalStart - 1
+	if hunk.OriginalLines == 0 {
+		// A pure insertion is numbered by the line it follows
+		start = hunk.OriginalStart
+	}
+	if start >= pos && hunkMatchesAt(lines, hunk, start) {
+		return start
+	}
+
+	best := -1
+	for i := pos; i <= len(lines); i++ {
+		if !hunkMatchesAt(lines, hunk, i) {
+			continue
+		}
+		if best < 0 || abs(i-start) < abs(best-start) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return start
+	}
+	return best
+}
+
+// hunkMatchesAt reports whether a hunk's context and removed lines match at start
+func hunkMatchesAt(lines []string, hunk *DiffHunk, start int) bool {
+	if start < 0 {
+		return false
+	}
+	pos := start
+	for _, line := range hunk.Lines {
+		if line.Type == DiffLineAdded {
+			continue
+		}
+		if pos >= len(lines) || !sameLine(lines[pos], line.Content) {
+			return false
+		}
+		pos++
+	}
+	return true
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// splitLines splits content into lines on "\n". Lines keep a trailing "\r",
+// so joinLines restores CRLF content exactly, and no line is too long.
 func splitLines(content string) []string {
 	if content == "" {
 		return []string{}
 	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
 
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	lines := make([]string, 0)
-
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+// joinLines joins lines split from original, ending with a newline when
+// original did
+func joinLines(lines []string, original string) string {
+	joined := strings.Join(lines, "\n")
+	if strings.HasSuffix(original, "\n") && len(lines) > 0 {
+		joined += "\n"
 	}
+	return joined
+}
 
+// trimCRs strips the "\r" of CRLF line endings from lines, in place
+func trimCRs(lines []string) []string {
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
 	return lines
 }
 
+// sameLine compares lines ignoring a CRLF line ending
+func sameLine(a, b string) bool {
+	return strings.TrimSuffix(a, "\r") == strings.TrimSuffix(b, "\r")
+}
+
 // ApplyFindingSuggestion applies a finding's suggestion to generate a diff
 func ApplyFindingSuggestion(finding *Finding, originalContent string) (*Diff, error) {
 	if finding.Code == "" {
@@ -208,15 +571,85 @@ func ApplyFindingSuggestion(finding *Finding, originalContent string) (*Diff, er
 	// For now, create a simple diff showing the suggestion
 	// In a real implementation, this would intelligently apply the change
 	lines := splitLines(originalContent)
+	lineStart, lineEnd, err := locateFinding(finding, lines)
+	if err != nil {
+		return nil, err
+	}
 
 	// Replace lines in the specified range
 	modifiedLines := make([]string, 0, len(lines))
-	modifiedLines = append(modifiedLines, lines[:finding.LineStart-1]...)
+	modifiedLines = append(modifiedLines, lines[:lineStart-1]...)
 	modifiedLines = append(modifiedLines, finding.Code)
-	if finding.LineEnd < len(lines) {
-		modifiedLines = append(modifiedLines, lines[finding.LineEnd:]...)
-	}
+	modifiedLines = append(modifiedLines, lines[lineEnd:]...)
 
 	engine := NewDiffEngine()
 	return engine.Generate(finding.File, originalContent, strings.Join(modifiedLines, "\n"))
 }
+
+// ErrFindingChanged is returned when the lines a finding was reported on are
+// no longer in the file
+var ErrFindingChanged = errors.New("lines changed since the analysis")
+
+// locateFinding returns the 1-based line range a finding's suggestion
+// replaces. With Source recorded, the lines must still read the same: at
+// the reported lines, or at the one place they moved to when an earlier
+// patch shifted them. Without it the reported range is trusted as is.
+func locateFinding(finding *Finding, lines []string) (start, end int, err error) {
+	if finding.LineStart < 1 || finding.LineStart > len(lines) {
+		return 0, 0, fmt.Errorf("finding line %d is outside the file", finding.LineStart)
+	}
+
+	// An end before the start or past the file replaces just what exists
+	end = min(max(finding.LineEnd, finding.LineStart), len(lines))
+	if finding.Source == "" {
+		return finding.LineStart, end, nil
+	}
+
+	source := splitLines(finding.Source)
+	matchesAt := func(i int) bool {
+		if i < 0 || i+len(source) > len(lines) {
+			return false
+		}
+		for j, line := range source {
+			if !sameLine(lines[i+j], line) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if matchesAt(finding.LineStart - 1) {
+		return finding.LineStart, finding.LineStart + len(source) - 1, nil
+	}
+
+	found := -1
+	for i := range lines {
+		if !matchesAt(i) {
+			continue
+		}
+		if found >= 0 {
+			// Ambiguous; patching either could be wrong
+			found = -1
+			break
+		}
+		found = i
+	}
+	if found < 0 {
+		return 0, 0, fmt.Errorf("%s:%d: %w; re-run the analysis", finding.File, finding.LineStart, ErrFindingChanged)
+	}
+	return found + 1, found + len(source), nil
+}
+
+// maxSourceLines caps the lines recorded in Finding.Source
+const maxSourceLines = 200
+
+// findingSource returns the text of lines start-end (1-based) of content
+// for Finding.Source, or "" when the range is outside the file or too long
+func findingSource(content string, start, end int) string {
+	lines := splitLines(content)
+	end = min(max(end, start), len(lines))
+	if start < 1 || start > len(lines) || end-start+1 > maxSourceLines {
+		return ""
+	}
+	return strings.Join(trimCRs(append([]string(nil), lines[start-1:end]...)), "\n")
+}
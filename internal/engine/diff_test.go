@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const diffTestOriginal = "l1\nl2\nl3\nl4\nl5\nl6\nl7\nl8\nl9\nl10\n"
+
+func TestGenerateSingleLineInsertion(t *testing.T) {
+	modified := "l1\nl2\nl3\nl4\nl5\nnew\nl6\nl7\nl8\nl9\nl10\n"
+
+	diff, err := NewDiffEngine().Generate("a.go", diffTestOriginal, modified)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	assertHunks(t, diff, []*DiffHunk{{
+		OriginalStart: 3, OriginalLines: 6,
+		ModifiedStart: 3, ModifiedLines: 7,
+		Lines: []*DiffLine{
+			{Type: DiffLineContext, Content: "l3", LineNum: 3},
+			{Type: DiffLineContext, Content: "l4", LineNum: 4},
+			{Type: DiffLineContext, Content: "l5", LineNum: 5},
+			{Type: DiffLineAdded, Content: "new", LineNum: 6},
+			{Type: DiffLineContext, Content: "l6", LineNum: 6},
+			{Type: DiffLineContext, Content: "l7", LineNum: 7},
+			{Type: DiffLineContext, Content: "l8", LineNum: 8},
+		},
+	}})
+
+	want := strings.Join([]string{
+		"--- a/a.go",
+		"+++ b/a.go",
+		"@@ -3,6 +3,7 @@",
+		" l3",
+		" l4",
+		" l5",
+		"+new",
+		" l6",
+		" l7",
+		" l8",
+		"",
+	}, "\n")
+	if got := diff.FormatUnified(); got != want {
+		t.Errorf("FormatUnified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateMidFileModification(t *testing.T) {
+	modified := "l1\nl2\nl3\nl4\nl5\nL6\nl7\nl8\nl9\nl10\n"
+
+	diff, err := NewDiffEngine().Generate("a.go", diffTestOriginal, modified)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	assertHunks(t, diff, []*DiffHunk{{
+		OriginalStart: 3, OriginalLines: 7,
+		ModifiedStart: 3, ModifiedLines: 7,
+		Lines: []*DiffLine{
+			{Type: DiffLineContext, Content: "l3", LineNum: 3},
+			{Type: DiffLineContext, Content: "l4", LineNum: 4},
+			{Type: DiffLineContext, Content: "l5", LineNum: 5},
+			{Type: DiffLineRemoved, Content: "l6", LineNum: 6},
+			{Type: DiffLineAdded, Content: "L6", LineNum: 7},
+			{Type: DiffLineContext, Content: "l7", LineNum: 7},
+			{Type: DiffLineContext, Content: "l8", LineNum: 8},
+			{Type: DiffLineContext, Content: "l9", LineNum: 9},
+		},
+	}})
+
+	if additions, deletions := diff.GetChangeCount(); additions != 1 || deletions != 1 {
+		t.Errorf("GetChangeCount() = +%d -%d, want +1 -1", additions, deletions)
+	}
+
+	// The generated diff reproduces the modified content
+	applied, err := diff.Apply(diffTestOriginal)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if applied != modified {
+		t.Errorf("Apply() = %q, want %q", applied, modified)
+	}
+}
+
+func assertHunks(t *testing.T, diff *Diff, want []*DiffHunk) {
+	t.Helper()
+
+	if len(diff.Hunks) != len(want) {
+		t.Fatalf("got %d hunks, want %d", len(diff.Hunks), len(want))
+	}
+	for i, hunk := range diff.Hunks {
+		w := want[i]
+		if hunk.OriginalStart != w.OriginalStart || hunk.OriginalLines != w.OriginalLines ||
+			hunk.ModifiedStart != w.ModifiedStart || hunk.ModifiedLines != w.ModifiedLines {
+			t.Errorf("hunk %d = @@ -%d,%d +%d,%d @@, want @@ -%d,%d +%d,%d @@", i,
+				hunk.OriginalStart, hunk.OriginalLines, hunk.ModifiedStart, hunk.ModifiedLines,
+				w.OriginalStart, w.OriginalLines, w.ModifiedStart, w.ModifiedLines)
+		}
+		if len(hunk.Lines) != len(w.Lines) {
+			t.Errorf("hunk %d has %d lines, want %d", i, len(hunk.Lines), len(w.Lines))
+			continue
+		}
+		for j, line := range hunk.Lines {
+			if *line != *w.Lines[j] {
+				t.Errorf("hunk %d line %d = %+v, want %+v", i, j, *line, *w.Lines[j])
+			}
+		}
+	}
+}
+
+func TestApplyKeepsLongLines(t *testing.T) {
+	long := strings.Repeat("x", 70000)
+	original := "a\n" + long + "\nb\n"
+	modified := "X\n" + long + "\nb\n"
+
+	diff, err := NewDiffEngine().Generate("a.go", original, modified)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	applied, err := diff.Apply(original)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if applied != modified {
+		t.Errorf("Apply() returned %d bytes, want %d", len(applied), len(modified))
+	}
+}
+
+func TestApplyKeepsCRLF(t *testing.T) {
+	original := "l1\r\nl2\r\nl3\r\n"
+
+	// Diffs from a model carry no line endings
+	diff := &Diff{FilePath: "a.go", Hunks: []*DiffHunk{{
+		OriginalStart: 2, OriginalLines: 1, ModifiedStart: 2, ModifiedLines: 2,
+		Lines: []*DiffLine{
+			{Type: DiffLineRemoved, Content: "l2"},
+			{Type: DiffLineAdded, Content: "L2"},
+			{Type: DiffLineAdded, Content: "new"},
+		},
+	}}}
+
+	applied, err := diff.Apply(original)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if want := "l1\r\nL2\r\nnew\r\nl3\r\n"; applied != want {
+		t.Errorf("Apply() = %q, want %q", applied, want)
+	}
+}
+
+func TestApplyFindingSuggestionChecksSource(t *testing.T) {
+	finding := &Finding{
+		File:      "a.go",
+		LineStart: 2,
+		LineEnd:   2,
+		Code:      "fixed",
+		Source:    "l2",
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string // "" when the patch must be refused
+	}{
+		{"unchanged", "l1\nl2\nl3\n", "l1\nfixed\nl3\n"},
+		{"shifted", "l0\nl1\nl2\nl3\n", "l0\nl1\nfixed\nl3\n"},
+		{"changed", "l1\nL2\nl3\n", ""},
+		{"ambiguous", "l0\nl1\nl2\nl2\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := ApplyFindingSuggestion(finding, tt.content)
+			if tt.want == "" {
+				if !errors.Is(err, ErrFindingChanged) {
+					t.Fatalf("ApplyFindingSuggestion() error = %v, want ErrFindingChanged", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyFindingSuggestion: %v", err)
+			}
+			applied, err := diff.Apply(tt.content)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if applied != tt.want {
+				t.Errorf("Apply() = %q, want %q", applied, tt.want)
+			}
+		})
+	}
+}
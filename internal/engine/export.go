@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+)
+
+// ExportMarkdown writes the report as Markdown: a summary header followed by
+// one table of findings per file, suitable for pasting into a PR description
+func ExportMarkdown(report *AnalysisReport, w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Churn Analysis Report\n\n")
+	sb.WriteString(fmt.Sprintf("_Generated %s", report.Timestamp.Format("2006-01-02 15:04:05")))
+	if report.Version != "" {
+		sb.WriteString(fmt.Sprintf(" by churn-plus %s", report.Version))
+	}
+	if revision := report.Revision(); revision != "" {
+		sb.WriteString(fmt.Sprintf(" at `%s`", revision))
+	}
+	sb.WriteString("_\n\n")
+
+	summary := report.Summary
+	sb.WriteString(fmt.Sprintf("- **Files analyzed:** %d\n", summary.FilesAnalyzed))
+	sb.WriteString(fmt.Sprintf("- **Findings:** %d", len(report.Findings)))
+	var counts []string
+	for _, severity := range []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow} {
+		if n := summary.BySeverity[severity]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%s %d %s", theme.SeverityIcon(string(severity)), n, severity))
+		}
+	}
+	if len(counts) > 0 {
+		sb.WriteString(" (" + strings.Join(counts, ", ") + ")")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("- **Duration:** %.1fs\n", summary.Duration))
+	if len(summary.FailedPasses) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Failed passes:** %s\n", strings.Join(summary.FailedPasses, ", ")))
+	}
+	if summary.Usage.PromptTokens+summary.Usage.CompletionTokens > 0 {
+		sb.WriteString("- **Usage:**\n")
+		for _, pass := range report.Pipeline {
+			sb.WriteString(fmt.Sprintf("  - %s\n", pass.UsageSummary()))
+		}
+	}
+
+	root := ""
+	if report.Context != nil {
+		root = report.Context.RootPath
+	}
+
+	byFile := make(map[string][]*Finding)
+	var files []string
+	for _, finding := range report.Findings {
+		path := reportPath(root, finding.File)
+		if _, ok := byFile[path]; !ok {
+			files = append(files, path)
+		}
+		byFile[path] = append(byFile[path], finding)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		findings := byFile[file]
+		sort.SliceStable(findings, func(i, j int) bool {
+			return findings[i].LineStart < findings[j].LineStart
+		})
+
+		sb.WriteString(fmt.Sprintf("\n## `%s`\n\n", file))
+		sb.WriteString("| Severity | Lines | Kind | Pass | Message |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, finding := range findings {
+			sb.WriteString(fmt.Sprintf("| %s %s | %s | %s | %s | %s |\n",
+				theme.SeverityIcon(string(finding.Severity)), finding.Severity,
+				lineRange(finding), markdownCell(finding.Kind),
+				markdownCell(finding.Pass), markdownCell(finding.Message)))
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+
+	return nil
+}
+
+// ExportCSV writes one row per finding with a header row
+func ExportCSV(report *AnalysisReport, w io.Writer) error {
+	root := ""
+	if report.Context != nil {
+		root = report.Context.RootPath
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"file", "line_start", "line_end", "severity", "kind", "pass", "message"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, finding := range report.Findings {
+		record := []string{
+			reportPath(root, finding.File),
+			strconv.Itoa(finding.LineStart),
+			strconv.Itoa(finding.LineEnd),
+			string(finding.Severity),
+			finding.Kind,
+			finding.Pass,
+			finding.Message,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+// reportPath returns path relative to the project root with forward slashes
+func reportPath(root, path string) string {
+	if root != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// lineRange formats a finding's lines as "12" or "12-18"
+func lineRange(finding *Finding) string {
+	if finding.LineEnd > finding.LineStart {
+		return fmt.Sprintf("%d-%d", finding.LineStart, finding.LineEnd)
+	}
+	return strconv.Itoa(finding.LineStart)
+}
+
+// markdownCell escapes text for a single Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
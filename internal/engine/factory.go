@@ -3,6 +3,9 @@ package engine
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/cloudboy-jh/churn-plus/internal/config"
 	"github.com/cloudboy-jh/churn-plus/internal/engine/providers"
@@ -10,63 +13,240 @@ import (
 
 // Factory creates and configures engine components
 type Factory struct {
-	cfg *config.Config
+	cfg    *config.Config
+	logger *slog.Logger
+
+	lastScanStats ScanStats
 }
 
 // NewFactory creates a new engine factory
 func NewFactory(cfg *config.Config) *Factory {
-	return &Factory{cfg: cfg}
+	return &Factory{cfg: cfg, logger: discardLogger}
+}
+
+// SetLogger sets the logger given to the providers and pipelines the
+// factory creates
+func (f *Factory) SetLogger(logger *slog.Logger) {
+	f.logger = logger
 }
 
-// CreateProvider creates a model provider based on configuration
+// LogLevel returns the level analysis logs are written at: CHURN_LOG_LEVEL,
+// then the project's log_level, defaulting to info
+func (f *Factory) LogLevel() slog.Level {
+	if level, ok := ParseLogLevel(os.Getenv(LogLevelEnv)); ok {
+		return level
+	}
+	level, _ := ParseLogLevel(f.cfg.Project.LogLevel)
+	return level
+}
+
+// CreateProvider creates a model provider based on configuration. Transient
+// errors are retried with backoff, and requests are spaced to stay under the
+// provider's configured rate limit.
 func (f *Factory) CreateProvider() (ModelProvider, error) {
 	modelSelection := f.cfg.GetModelSelection()
+	return f.createProvider(modelSelection.Provider, modelSelection.Model)
+}
 
-	switch modelSelection.Provider {
+// createProvider creates the named provider; model is the Azure deployment
+// used when none is configured
+func (f *Factory) createProvider(name, model string) (ModelProvider, error) {
+	var provider ModelProvider
+	switch name {
 	case "anthropic":
 		apiKey := f.cfg.GetAPIKey("anthropic")
 		if apiKey == "" {
 			return nil, fmt.Errorf("anthropic API key not configured")
 		}
-		return providers.NewAnthropicProvider(apiKey), nil
+		provider = providers.NewAnthropicProvider(apiKey, f.cfg.GetEndpoint("anthropic"))
 
 	case "openai":
+		// Self-hosted compatible servers at a custom endpoint may not need a key
 		apiKey := f.cfg.GetAPIKey("openai")
-		if apiKey == "" {
+		baseURL := f.cfg.GetEndpoint("openai")
+		if apiKey == "" && baseURL == "" {
 			return nil, fmt.Errorf("openai API key not configured")
 		}
-		return providers.NewOpenAIProvider(apiKey), nil
+		provider = providers.NewOpenAIProvider(apiKey, baseURL)
 
 	case "google":
 		apiKey := f.cfg.GetAPIKey("google")
 		if apiKey == "" {
 			return nil, fmt.Errorf("google API key not configured")
 		}
-		return providers.NewGoogleProvider(apiKey), nil
+		provider = providers.NewGoogleProvider(apiKey, f.cfg.GetEndpoint("google"))
+
+	case "azure":
+		apiKey := f.cfg.GetAPIKey("azure")
+		if apiKey == "" {
+			return nil, fmt.Errorf("azure API key not configured")
+		}
+		azure := f.cfg.Global.AzureOpenAI
+		if azure.Endpoint == "" {
+			return nil, fmt.Errorf("azure endpoint not configured")
+		}
+		deployment := azure.Deployment
+		if deployment == "" {
+			deployment = model
+		}
+		provider = providers.NewAzureOpenAIProvider(apiKey, azure.Endpoint, deployment, azure.APIVersion)
 
 	case "ollama":
-		return providers.NewOllamaProvider(""), nil
+		provider = providers.NewOllamaProvider(f.cfg.GetEndpoint("ollama"))
 
 	default:
-		return nil, fmt.Errorf("unknown provider: %s", modelSelection.Provider)
+		compatible, ok := f.cfg.GetCompatibleProvider(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown provider: %s", name)
+		}
+		if compatible.BaseURL == "" {
+			return nil, fmt.Errorf("%s base URL not configured", name)
+		}
+		provider = NewCompatibleProvider(name, compatible)
+	}
+
+	if err := ConfigureHTTP(f.cfg, name, provider); err != nil {
+		return nil, err
+	}
+
+	// Limit inside the retries so that each attempt waits its turn
+	if limit, ok := f.cfg.GetRateLimit(name); ok {
+		limiter := providers.NewRateLimiter(limit.RequestsPerMinute, limit.TokensPerMinute)
+		provider = providers.NewRateLimitedProvider(provider, limiter)
+	}
+
+	retry := providers.NewRetryProvider(provider)
+	retry.SetLogger(f.logger)
+	return retry, nil
+}
+
+// ConfigureHTTP gives a provider an HTTP client that uses the configured
+// proxy and sends the configured extra headers
+func ConfigureHTTP(cfg *config.Config, name string, provider ModelProvider) error {
+	setter, ok := provider.(providers.HTTPClientSetter)
+	if !ok {
+		return nil
+	}
+
+	client, err := providers.NewHTTPClient(providers.HTTPSettings{
+		ProxyURL: cfg.Global.HTTP.Proxy,
+		Headers:  cfg.GetHTTPHeaders(name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure HTTP client: %w", err)
 	}
+
+	setter.SetHTTPClient(client)
+	return nil
+}
+
+// NewCompatibleProvider creates a provider for an OpenAI-compatible API
+// configured under name
+func NewCompatibleProvider(name string, compatible config.CompatibleProvider) *providers.GenericOpenAIProvider {
+	return providers.NewGenericOpenAIProvider(providers.GenericOpenAIConfig{
+		Name:           name,
+		BaseURL:        compatible.BaseURL,
+		APIKey:         compatible.ResolveAPIKey(),
+		AuthStyle:      providers.AuthStyle(compatible.AuthStyle),
+		AuthHeader:     compatible.AuthHeader,
+		FallbackModels: compatible.Models,
+	})
+}
+
+// providerCheckTimeout bounds the health check made before a run
+const providerCheckTimeout = 15 * time.Second
+
+// ProviderCheckError reports that the provider failed its pre-run check
+type ProviderCheckError struct {
+	Provider string
+	Err      error
+}
+
+// Error implements the error interface
+func (e *ProviderCheckError) Error() string {
+	return fmt.Sprintf("%s provider check failed: %v", e.Provider, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ProviderCheckError) Unwrap() error {
+	return e.Err
+}
+
+// CheckProvider pings the provider of every pass and, for passes run on
+// Ollama, verifies that their models have been pulled, so a bad key or a
+// stopped server is reported before analysis starts
+func (f *Factory) CheckProvider(ctx context.Context, orchestrator *PipelineOrchestrator) error {
+	ctx, cancel := context.WithTimeout(ctx, providerCheckTimeout)
+	defer cancel()
+
+	passes := orchestrator.GetPipeline().Passes
+	pinged := make(map[ModelProvider]bool)
+	for _, pass := range passes {
+		provider := orchestrator.providerFor(pass)
+		if pinged[provider] {
+			continue
+		}
+		pinged[provider] = true
+		if err := provider.Ping(ctx); err != nil {
+			return &ProviderCheckError{Provider: provider.Name(), Err: err}
+		}
+	}
+
+	ollama := providers.NewOllamaProvider(f.cfg.GetEndpoint("ollama"))
+	checked := make(map[string]bool)
+	for _, pass := range passes {
+		if pass.Provider != "ollama" || checked[pass.Model] {
+			continue
+		}
+		checked[pass.Model] = true
+		if err := ollama.CheckModel(ctx, pass.Model); err != nil {
+			return &ProviderCheckError{Provider: "ollama", Err: fmt.Errorf("pass %s: %w", pass.Name, err)}
+		}
+	}
+
+	return nil
 }
 
 // CreateDefaultPipeline creates a pipeline with default or configured passes
 func (f *Factory) CreateDefaultPipeline(provider ModelProvider) (*PipelineOrchestrator, error) {
 	orchestrator := NewPipelineOrchestrator(provider)
+	orchestrator.SetLogger(f.logger)
+	orchestrator.SetConcurrencyLimit(f.cfg.GetConcurrencyLimit)
+	orchestrator.SetErrorPolicy(ErrorPolicy(f.cfg.Project.OnError))
+
+	promptOpts := DefaultPromptOptions()
+	promptOpts.NumberLines = !f.cfg.Project.DisableLineNumbers
+	promptOpts.WindowThreshold = f.cfg.Project.PromptWindowLines
+	orchestrator.SetPromptOptions(promptOpts)
+	orchestrator.SetContextWindows(f.cfg.Global.ContextWindows)
+	orchestrator.SetRequestTimeout(f.cfg.GetRequestTimeout())
+	orchestrator.SetStructuredOutput(!f.cfg.Project.DisableStructuredOutput)
 
 	// Check if pipeline is configured in project config
 	if f.cfg.Project.Pipeline != nil && len(f.cfg.Project.Pipeline.Passes) > 0 {
-		// Use configured pipeline
+		// Use configured pipeline; passes on another provider than the
+		// selected one get a provider of their own
+		selected := f.cfg.GetModelSelection().Provider
 		for _, passConfig := range f.cfg.Project.Pipeline.Passes {
 			if passConfig.Enabled {
+				name := passConfig.Provider
+				if name != "" && name != selected && !orchestrator.hasPassProvider(name) {
+					passProvider, err := f.createProvider(name, passConfig.Model)
+					if err != nil {
+						return nil, fmt.Errorf("pass %s: %w", passConfig.Name, err)
+					}
+					orchestrator.SetPassProvider(name, passProvider)
+				}
 				orchestrator.AddPass(&Pass{
 					Name:        passConfig.Name,
 					Description: passConfig.Description,
 					Status:      PassPending,
 					Model:       passConfig.Model,
 					Provider:    passConfig.Provider,
+					Mode:        PassMode(passConfig.Mode),
+					Temperature: passConfig.Temperature,
+					MaxTokens:   passConfig.MaxTokens,
+					Timeout:     passConfig.GetTimeout(),
 				})
 			}
 		}
@@ -126,7 +306,9 @@ func (f *Factory) CreateDefaultPipeline(provider ModelProvider) (*PipelineOrches
 
 // getFirstOllamaModel gets the first available Ollama model
 func (f *Factory) getFirstOllamaModel(provider ModelProvider) string {
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), providerCheckTimeout)
+	defer cancel()
+
 	models, err := provider.ListModels(ctx)
 	if err != nil || len(models) == 0 {
 		return "llama2" // Fallback
@@ -137,16 +319,87 @@ func (f *Factory) getFirstOllamaModel(provider ModelProvider) string {
 // ScanProject scans a project directory
 func (f *Factory) ScanProject(projectRoot string) ([]*FileInfo, *FileNode, error) {
 	scanner := NewScanner(projectRoot, f.cfg.Project.IgnorePatterns)
+	scanner.SetExtensionOverrides(f.cfg.Project.FileExtensionOverrides)
+	scanner.SetScanArchives(f.cfg.Project.ScanArchives)
+	scanner.SetRespectGitignore(!f.cfg.Project.DisableGitignore)
+	scanner.SetWorkers(f.cfg.Project.ScanWorkers)
+	scanner.SetMaxFileBytes(f.cfg.Project.MaxFileBytes)
+	scanner.SetChangedOnly(f.cfg.Project.ChangedOnly, f.cfg.Project.BaseRef)
+	scanner.SetCountCodeLines(f.cfg.Project.CountCodeLines)
 	files, err := scanner.Scan()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to scan project: %w", err)
 	}
+	f.lastScanStats = scanner.Stats()
+
+	// Analyze the most important files first; scan order is kept if the
+	// data to rank them is unavailable
+	if err := OrderFiles(files, FileOrder(f.cfg.Project.FileOrder), projectRoot); err != nil {
+		f.lastScanStats.Warnings = append(f.lastScanStats.Warnings, fmt.Sprintf("Analyzing in scan order: %v", err))
+	}
 
 	tree := BuildFileTree(files, projectRoot)
 
 	return files, tree, nil
 }
 
+// CreateResponseCache creates the project's LLM response cache from the
+// cache settings, returning nil when caching is disabled
+func (f *Factory) CreateResponseCache(projectRoot string) *ResponseCache {
+	settings := f.cfg.GetCacheSettings()
+	if !settings.Enabled {
+		return nil
+	}
+
+	return NewResponseCache(
+		CacheDir(projectRoot),
+		time.Duration(settings.TTL)*time.Hour,
+		int64(settings.MaxSize)*1024*1024,
+	)
+}
+
+// DedupOverlap returns the configured threshold for merging overlapping
+// findings, or DefaultOverlapThreshold
+func (f *Factory) DedupOverlap() float64 {
+	if f.cfg.Project.DedupOverlap != nil {
+		return *f.cfg.Project.DedupOverlap
+	}
+	return DefaultOverlapThreshold
+}
+
+// MinSeverity returns the configured lowest severity to report, empty to
+// report everything
+func (f *Factory) MinSeverity() Severity {
+	severity, ok := ParseSeverity(f.cfg.Project.MinSeverity)
+	if !ok {
+		return ""
+	}
+	return severity
+}
+
+// FailOnSeverity returns the configured severity that fails a
+// non-interactive run, and false when none is set
+func (f *Factory) FailOnSeverity() (Severity, bool) {
+	return ParseSeverity(f.cfg.Project.FailOnSeverity)
+}
+
+// ReportRetention returns the configured limits on kept reports
+func (f *Factory) ReportRetention() ReportRetention {
+	retention := f.cfg.Project.ReportRetention
+	if retention == nil {
+		return ReportRetention{}
+	}
+	return ReportRetention{
+		MaxCount: retention.MaxReports,
+		MaxAge:   time.Duration(retention.MaxAgeDays) * 24 * time.Hour,
+	}
+}
+
+// LastScanStats returns what the most recent ScanProject skipped
+func (f *Factory) LastScanStats() ScanStats {
+	return f.lastScanStats
+}
+
 // BuildContext builds project context from scanned files
 func (f *Factory) BuildContext(projectRoot string, files []*FileInfo) *ProjectContext {
 	builder := NewContextBuilder(projectRoot)
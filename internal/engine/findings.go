@@ -6,14 +6,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 )
 
+// DefaultOverlapThreshold is the share of the shorter line range two
+// findings must have in common to be merged
+const DefaultOverlapThreshold = 0.5
+
 // FindingsAggregator collects and manages findings from multiple passes
 type FindingsAggregator struct {
 	findings []*Finding
 	seen     map[string]bool // For deduplication
+
+	// overlap is the threshold for merging near-duplicates; 0 merges only
+	// exact duplicates
+	overlap float64
+	byKind  map[string][]*Finding // Findings by file and kind, for merging
 }
 
 // NewFindingsAggregator creates a new findings aggregator
@@ -21,10 +32,22 @@ func NewFindingsAggregator() *FindingsAggregator {
 	return &FindingsAggregator{
 		findings: make([]*Finding, 0),
 		seen:     make(map[string]bool),
+		overlap:  DefaultOverlapThreshold,
+		byKind:   make(map[string][]*Finding),
 	}
 }
 
-// Add adds a finding, deduplicating if necessary
+// SetOverlapThreshold sets how much of the shorter line range two findings
+// of the same kind in a file must share to be merged, from 0 to 1. 0 merges
+// only exact duplicates.
+func (fa *FindingsAggregator) SetOverlapThreshold(threshold float64) {
+	fa.overlap = threshold
+}
+
+// Add adds a finding, deduplicating if necessary. A finding that overlaps
+// one already added, with the same file and kind, is merged into it: the
+// higher severity wins and both passes are noted. Added findings are copied,
+// so merging never changes the caller's findings.
 func (fa *FindingsAggregator) Add(finding *Finding) {
 	// Create a hash of the finding for deduplication
 	hash := fa.hashFinding(finding)
@@ -33,9 +56,113 @@ func (fa *FindingsAggregator) Add(finding *Finding) {
 		// Already seen this finding, skip
 		return
 	}
-
 	fa.seen[hash] = true
-	fa.findings = append(fa.findings, finding)
+
+	key := finding.File + "\x00" + finding.Kind
+	if fa.overlap > 0 {
+		for _, existing := range fa.byKind[key] {
+			if lineOverlap(existing, finding) >= fa.overlap {
+				mergeFinding(existing, finding)
+				return
+			}
+		}
+	}
+
+	added := *finding
+	added.Passes = append([]string(nil), finding.Passes...)
+	if len(added.Passes) == 0 && added.Pass != "" {
+		added.Passes = []string{added.Pass}
+	}
+
+	fa.findings = append(fa.findings, &added)
+	fa.byKind[key] = append(fa.byKind[key], &added)
+}
+
+// lineOverlap returns the share of the shorter line range that two findings
+// have in common. Each range is widened by a line on both sides first, so
+// ranges that are one line apart still count as overlapping.
+func lineOverlap(a, b *Finding) float64 {
+	startA, endA := a.LineStart-1, max(a.LineEnd, a.LineStart)+1
+	startB, endB := b.LineStart-1, max(b.LineEnd, b.LineStart)+1
+
+	shared := min(endA, endB) - max(startA, startB) + 1
+	if shared <= 0 {
+		return 0
+	}
+	shorter := min(endA-startA, endB-startB) + 1
+	return float64(shared) / float64(shorter)
+}
+
+// mergeFinding folds a near-duplicate into kept, taking the duplicate's
+// details when it is more severe and recording the passes that reported it
+func mergeFinding(kept, duplicate *Finding) {
+	passes := kept.Passes
+	if severityRank(duplicate.Severity) > severityRank(kept.Severity) {
+		*kept = *duplicate
+	}
+
+	for _, pass := range append([]string{duplicate.Pass}, duplicate.Passes...) {
+		if pass != "" && !slices.Contains(passes, pass) {
+			passes = append(passes, pass)
+		}
+	}
+	kept.Passes = passes
+}
+
+// ParseSeverity converts a configured severity name, reporting whether it is
+// one of low, medium, high, or critical
+func ParseSeverity(name string) (Severity, bool) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(name)))
+	return severity, severityRank(severity) > 0
+}
+
+// AtLeast reports whether s is as severe as min or more
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRank(s) >= severityRank(min)
+}
+
+// FilterBySeverity keeps the findings at min severity or above, returning
+// them and how many were dropped. An empty min keeps everything.
+func FilterBySeverity(findings []*Finding, min Severity) ([]*Finding, int) {
+	if min == "" {
+		return findings, 0
+	}
+
+	kept := make([]*Finding, 0, len(findings))
+	for _, finding := range findings {
+		if finding.Severity.AtLeast(min) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept, len(findings) - len(kept)
+}
+
+// CountAtOrAbove returns how many of the report's findings are at min
+// severity or above, e.g. to fail a CI run
+func (r *AnalysisReport) CountAtOrAbove(min Severity) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity.AtLeast(min) {
+			count++
+		}
+	}
+	return count
+}
+
+// severityRank orders severities from low (1) to critical (4)
+func severityRank(severity Severity) int {
+	switch severity {
+	case SeverityCritical:
+		return 4
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	default:
+		return 0
+	}
 }
 
 // AddMultiple adds multiple findings
@@ -142,21 +269,32 @@ func (fa *FindingsAggregator) CountByKind() map[string]int {
 
 // hashFinding creates a unique hash for deduplication
 func (fa *FindingsAggregator) hashFinding(f *Finding) string {
+	return HashFinding(f)
+}
+
+// HashFinding returns a stable identifier for a finding
+func HashFinding(f *Finding) string {
 	// Hash based on file, line, kind, and message
 	data := fmt.Sprintf("%s:%d:%d:%s:%s", f.File, f.LineStart, f.LineEnd, f.Kind, f.Message)
 	hash := sha256.Sum256([]byte(data))
 	return fmt.Sprintf("%x", hash)
 }
 
+// MaxFileAnalysisRecords caps the per-file audit records kept in a report
+const MaxFileAnalysisRecords = 10000
+
 // GenerateReport creates an analysis report from findings
 func GenerateReport(
 	ctx *ProjectContext,
+	files []*FileInfo,
 	findings []*Finding,
 	passes []*Pass,
 	startTime time.Time,
 	endTime time.Time,
+	overlapThreshold float64,
 ) *AnalysisReport {
 	aggregator := NewFindingsAggregator()
+	aggregator.SetOverlapThreshold(overlapThreshold)
 	aggregator.AddMultiple(findings)
 	aggregator.Sort()
 
@@ -170,14 +308,73 @@ func GenerateReport(
 		Duration:      duration,
 	}
 
+	for _, pass := range passes {
+		if pass.Status == PassFailed {
+			summary.FailedPasses = append(summary.FailedPasses, pass.Name)
+		}
+		summary.Usage = summary.Usage.Add(pass.Usage)
+		summary.EstimatedCost += pass.EstimatedCost
+	}
+
 	return &AnalysisReport{
-		Version:   "0.1.0",
-		Timestamp: time.Now(),
-		Context:   ctx,
-		Findings:  aggregator.GetAll(),
-		Summary:   summary,
-		Pipeline:  passes,
+		SchemaVersion: ReportSchemaVersion,
+		Version:       "0.1.0",
+		Timestamp:     time.Now(),
+		Context:       ctx,
+		Findings:      aggregator.GetAll(),
+		Summary:       summary,
+		Pipeline:      passes,
+
+		FilesAnalyzed: buildFileRecords(files, aggregator.GetAll(), passes, endTime),
+	}
+}
+
+// buildFileRecords creates audit records for the analyzed files
+func buildFileRecords(files []*FileInfo, findings []*Finding, passes []*Pass, analyzedAt time.Time) []FileAnalysisRecord {
+	if len(files) > MaxFileAnalysisRecords {
+		files = files[:MaxFileAnalysisRecords]
+	}
+
+	findingCounts := make(map[string]int)
+	for _, f := range findings {
+		findingCounts[f.File]++
+	}
+
+	passesRun := make([]string, 0, len(passes))
+	for _, pass := range passes {
+		if pass.Status == PassCompleted || pass.Status == PassFailed {
+			passesRun = append(passesRun, pass.Name)
+		}
+	}
+
+	records := make([]FileAnalysisRecord, 0, len(files))
+	for _, file := range files {
+		record := FileAnalysisRecord{
+			Path:         file.Path,
+			Language:     file.Language,
+			Lines:        file.Lines,
+			AnalyzedAt:   analyzedAt,
+			PassesRun:    passesRun,
+			FindingCount: findingCounts[file.Path],
+		}
+		if content, err := ReadFileContent(file.Path); err == nil {
+			record.ContentHash = fmt.Sprintf("%x", sha256.Sum256(content))
+		}
+		records = append(records, record)
 	}
+
+	return records
+}
+
+// FileHashes maps each analyzed file path to its content hash
+func (r *AnalysisReport) FileHashes() map[string]string {
+	hashes := make(map[string]string, len(r.FilesAnalyzed))
+	for _, record := range r.FilesAnalyzed {
+		if record.ContentHash != "" {
+			hashes[record.Path] = record.ContentHash
+		}
+	}
+	return hashes
 }
 
 // SaveReport saves a report to .churn/reports/
@@ -190,7 +387,7 @@ func SaveReport(projectRoot string, report *AnalysisReport) error {
 	}
 
 	// Generate filename with timestamp
-	filename := fmt.Sprintf("churn-report-%s.json", report.Timestamp.Format("2006-01-02T15-04-05"))
+	filename := reportFilePrefix + report.Timestamp.Format(reportTimeLayout) + ".json"
 	path := filepath.Join(reportsDir, filename)
 
 	// Marshal report to JSON
@@ -207,39 +404,192 @@ func SaveReport(projectRoot string, report *AnalysisReport) error {
 	return nil
 }
 
-// LoadReport loads a report from a file
+// reportFilePrefix and reportTimeLayout make up report file names
+const (
+	reportFilePrefix = "churn-report-"
+	reportTimeLayout = "2006-01-02T15-04-05"
+)
+
+// ReportRetention limits the reports PruneReports keeps; zero disables a limit
+type ReportRetention struct {
+	MaxCount int
+	MaxAge   time.Duration
+}
+
+// PruneReports deletes reports beyond the retention limits, oldest first,
+// and returns how many were deleted. The newest report is always kept.
+func PruneReports(projectRoot string, retention ReportRetention) (int, error) {
+	if retention.MaxCount <= 0 && retention.MaxAge <= 0 {
+		return 0, nil
+	}
+
+	reports, times, err := listReportTimes(projectRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-retention.MaxAge)
+	deleted := 0
+	for i := len(reports) - 2; i >= 0; i-- {
+		path := reports[i]
+		newer := len(reports) - 1 - i
+		tooMany := retention.MaxCount > 0 && newer >= retention.MaxCount
+		tooOld := retention.MaxAge > 0 && times[i].Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return deleted, fmt.Errorf("failed to delete report: %w", err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// reportTime returns when a report was saved: its recorded timestamp, or
+// else the time in its file name, or else the file's modification time
+func reportTime(path string) time.Time {
+	if t, ok := readReportTimestamp(path); ok {
+		return t
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), ".json")
+	if stamp, ok := strings.CutPrefix(name, reportFilePrefix); ok {
+		if t, err := time.ParseInLocation(reportTimeLayout, stamp, time.Local); err == nil {
+			return t
+		}
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+// readReportTimestamp reads a report's top-level timestamp without decoding
+// the rest of it. Reports write it before the findings, so this stays cheap
+// for large reports.
+func readReportTimestamp(path string) (time.Time, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return time.Time{}, false
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return time.Time{}, false
+		}
+		if key != "timestamp" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return time.Time{}, false
+			}
+			continue
+		}
+
+		var timestamp time.Time
+		if err := decoder.Decode(&timestamp); err != nil || timestamp.IsZero() {
+			return time.Time{}, false
+		}
+		return timestamp, true
+	}
+	return time.Time{}, false
+}
+
+// Revision describes the checkout the report was made from, e.g.
+// "main@1a2b3c4", or "" when it was not made in a git repository
+func (r *AnalysisReport) Revision() string {
+	switch {
+	case r.GitCommit == "":
+		return ""
+	case r.GitBranch == "":
+		return r.GitCommit
+	default:
+		return r.GitBranch + "@" + r.GitCommit
+	}
+}
+
+// LoadReport loads a report from a file, upgrading it to the current schema
 func LoadReport(path string) (*AnalysisReport, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read report: %w", err)
 	}
 
-	var report AnalysisReport
-	if err := json.Unmarshal(data, &report); err != nil {
+	report, migrated, err := decodeReport(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse report: %w", err)
 	}
 
-	return &report, nil
+	// Upgrade the file so older reports are only migrated once
+	if migrated {
+		if err := rewriteReport(path, report); err != nil {
+			return nil, fmt.Errorf("failed to rewrite migrated report: %w", err)
+		}
+	}
+
+	return report, nil
 }
 
-// ListReports returns all reports in the .churn/reports/ directory
+// ListReports returns all reports in the .churn/reports/ directory, oldest
+// first. Reports are ordered by their recorded timestamp rather than their
+// file name, so copied or renamed reports fall into place.
 func ListReports(projectRoot string) ([]string, error) {
+	reports, _, err := listReportTimes(projectRoot)
+	return reports, err
+}
+
+// LatestReport returns the path of the newest report, or "" when there are
+// none
+func LatestReport(projectRoot string) (string, error) {
+	reports, err := ListReports(projectRoot)
+	if err != nil || len(reports) == 0 {
+		return "", err
+	}
+	return reports[len(reports)-1], nil
+}
+
+// listReportTimes returns the reports oldest first, with the time of each
+func listReportTimes(projectRoot string) ([]string, []time.Time, error) {
 	reportsDir := filepath.Join(projectRoot, ".churn", "reports")
 
 	entries, err := os.ReadDir(reportsDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []string{}, nil
+			return []string{}, nil, nil
 		}
-		return nil, fmt.Errorf("failed to read reports directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read reports directory: %w", err)
 	}
 
-	reports := make([]string, 0)
+	type timedReport struct {
+		path string
+		time time.Time
+	}
+	timed := make([]timedReport, 0, len(entries))
 	for _, entry := range entries {
 		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			reports = append(reports, filepath.Join(reportsDir, entry.Name()))
+			path := filepath.Join(reportsDir, entry.Name())
+			timed = append(timed, timedReport{path: path, time: reportTime(path)})
 		}
 	}
 
-	return reports, nil
+	// Ties keep the file name order
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i].time.Before(timed[j].time)
+	})
+
+	reports := make([]string, len(timed))
+	times := make([]time.Time, len(timed))
+	for i, report := range timed {
+		reports[i] = report.path
+		times[i] = report.time
+	}
+
+	return reports, times, nil
 }
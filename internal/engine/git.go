@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultBaseRef is the ref changed-only scans compare against
+const DefaultBaseRef = "main"
+
+// gitChangedFiles returns the files changed relative to baseRef, including
+// uncommitted and untracked work, as paths relative to rootPath
+func gitChangedFiles(rootPath, baseRef string) (map[string]bool, error) {
+	toplevel, err := runGit(rootPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git repository", rootPath)
+	}
+	toplevel = strings.TrimSpace(toplevel)
+
+	var paths []string
+
+	// -z keeps paths verbatim; otherwise git quotes and escapes unusual ones
+	diff, err := runGit(rootPath, "diff", "--name-only", "-z", baseRef+"...HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", baseRef, err)
+	}
+	paths = append(paths, strings.Split(diff, "\x00")...)
+
+	status, err := runGit(rootPath, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git status: %w", err)
+	}
+	paths = append(paths, parsePorcelainPaths(status)...)
+
+	// Git reports paths from the repository root, which may be above rootPath
+	realRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(realRoot); err == nil {
+		realRoot = resolved
+	}
+
+	changed := make(map[string]bool)
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		rel, err := filepath.Rel(realRoot, filepath.Join(toplevel, filepath.FromSlash(p)))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		changed[rel] = true
+	}
+
+	return changed, nil
+}
+
+// gitRevision returns the checked-out branch and short commit SHA of the
+// repository containing rootPath. Both are empty outside a git repository
+// (or without git installed), and the branch is empty on a detached HEAD.
+func gitRevision(rootPath string) (branch, commit string) {
+	out, err := runGit(rootPath, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", ""
+	}
+	commit = strings.TrimSpace(out)
+
+	if out, err := runGit(rootPath, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		if name := strings.TrimSpace(out); name != "HEAD" {
+			branch = name
+		}
+	}
+	return branch, commit
+}
+
+// parsePorcelainPaths extracts paths from `git status --porcelain -z`
+// output: NUL-terminated "XY path" entries, where renames and copies are
+// followed by an extra entry holding the original path
+func parsePorcelainPaths(output string) []string {
+	var paths []string
+
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		paths = append(paths, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++ // Skip the original path
+		}
+	}
+
+	return paths
+}
+
+// runGit runs a git command in dir and returns its stdout
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
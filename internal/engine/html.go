@@ -0,0 +1,349 @@
+package engine
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// htmlSnippetContext is how many lines around a finding its snippet shows
+const htmlSnippetContext = 3
+
+// htmlReport is the data the HTML report template renders
+type htmlReport struct {
+	Report     *AnalysisReport
+	Severities []htmlCount
+	Kinds      []htmlCount
+	Findings   []htmlFinding
+	Files      []htmlFile
+}
+
+type htmlCount struct {
+	Name  string
+	Count int
+}
+
+type htmlFinding struct {
+	*Finding
+	Path      string
+	Lines     string
+	Rank      int
+	Snippet   []htmlSnippetLine
+	Anchor    string
+	PassNames string
+}
+
+type htmlSnippetLine struct {
+	Number    int
+	Text      string
+	Highlight bool
+}
+
+type htmlFile struct {
+	Path     string
+	Findings []htmlFinding
+	Worst    Severity
+}
+
+// ExportHTML writes the report as a single self-contained HTML page: a
+// severity summary, a sortable and filterable findings table, and findings
+// grouped by file with the code they point at. CSS and JavaScript are
+// inline, so the file can be attached to a ticket as is.
+func ExportHTML(report *AnalysisReport, w io.Writer) error {
+	root := ""
+	if report.Context != nil {
+		root = report.Context.RootPath
+	}
+
+	data := htmlReport{Report: report}
+
+	for _, severity := range []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow} {
+		data.Severities = append(data.Severities, htmlCount{Name: string(severity), Count: report.Summary.BySeverity[severity]})
+	}
+	for kind, count := range report.Summary.ByKind {
+		data.Kinds = append(data.Kinds, htmlCount{Name: kind, Count: count})
+	}
+	sort.Slice(data.Kinds, func(i, j int) bool {
+		if data.Kinds[i].Count != data.Kinds[j].Count {
+			return data.Kinds[i].Count > data.Kinds[j].Count
+		}
+		return data.Kinds[i].Name < data.Kinds[j].Name
+	})
+
+	// Read each file once for its findings' snippets
+	contents := make(map[string][]string)
+	byFile := make(map[string][]htmlFinding)
+	var paths []string
+	for i, finding := range report.Findings {
+		lines, ok := contents[finding.File]
+		if !ok {
+			if content, err := ReadFileContent(finding.File); err == nil {
+				lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			}
+			contents[finding.File] = lines
+		}
+
+		passes := finding.Pass
+		if len(finding.Passes) > 1 {
+			passes = strings.Join(finding.Passes, ", ")
+		}
+
+		entry := htmlFinding{
+			Finding: finding,
+			Path:    reportPath(root, finding.File),
+			Lines:   lineRange(finding),
+			Rank:    severityRank(finding.Severity),
+			Snippet: htmlSnippet(lines, finding),
+			Anchor:  fmt.Sprintf("finding-%d", i+1),
+
+			PassNames: passes,
+		}
+		data.Findings = append(data.Findings, entry)
+
+		if _, ok := byFile[entry.Path]; !ok {
+			paths = append(paths, entry.Path)
+		}
+		byFile[entry.Path] = append(byFile[entry.Path], entry)
+	}
+
+	sort.Strings(paths)
+	for _, path := range paths {
+		findings := byFile[path]
+		sort.SliceStable(findings, func(i, j int) bool {
+			return findings[i].LineStart < findings[j].LineStart
+		})
+
+		worst := findings[0]
+		for _, finding := range findings[1:] {
+			if finding.Rank > worst.Rank {
+				worst = finding
+			}
+		}
+		data.Files = append(data.Files, htmlFile{Path: path, Findings: findings, Worst: worst.Severity})
+	}
+
+	if err := htmlReportTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to write HTML: %w", err)
+	}
+
+	return nil
+}
+
+// htmlSnippet returns the finding's lines with some context, or nil when the
+// file could not be read or the lines are out of range
+func htmlSnippet(lines []string, finding *Finding) []htmlSnippetLine {
+	if finding.LineStart < 1 || finding.LineStart > len(lines) {
+		return nil
+	}
+	end := max(finding.LineEnd, finding.LineStart)
+
+	first := max(finding.LineStart-htmlSnippetContext, 1)
+	last := min(end+htmlSnippetContext, len(lines))
+
+	snippet := make([]htmlSnippetLine, 0, last-first+1)
+	for n := first; n <= last; n++ {
+		snippet = append(snippet, htmlSnippetLine{
+			Number:    n,
+			Text:      lines[n-1],
+			Highlight: n >= finding.LineStart && n <= end,
+		})
+	}
+	return snippet
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Churn Analysis Report</title>
+<style>
+  :root {
+    --critical: #d32f2f; --high: #f57c00; --medium: #fbc02d; --low: #1976d2;
+    --border: #e0e0e0; --muted: #757575; --bg: #fafafa;
+  }
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 0; padding: 2rem; color: #212121; background: #fff; }
+  h1 { margin: 0 0 .25rem; }
+  h2 { margin: 2rem 0 .75rem; }
+  .meta { color: var(--muted); margin-bottom: 1.5rem; }
+  .cards { display: flex; flex-wrap: wrap; gap: 1rem; }
+  .card { border: 1px solid var(--border); border-radius: 8px; padding: 1rem 1.25rem; min-width: 7rem; background: var(--bg); }
+  .card .count { font-size: 2rem; font-weight: 600; }
+  .card .label { color: var(--muted); text-transform: capitalize; }
+  .card.critical .count { color: var(--critical); }
+  .card.high .count { color: var(--high); }
+  .card.medium .count { color: var(--medium); }
+  .card.low .count { color: var(--low); }
+  .kinds { display: flex; flex-wrap: wrap; gap: .5rem; margin-top: 1rem; }
+  .kind { border: 1px solid var(--border); border-radius: 999px; padding: .2rem .75rem; font-size: .9rem; }
+  .controls { display: flex; flex-wrap: wrap; gap: 1rem; align-items: center; margin-bottom: .75rem; }
+  .controls input[type=search] { padding: .4rem .6rem; min-width: 16rem; border: 1px solid var(--border); border-radius: 4px; }
+  .controls label { text-transform: capitalize; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: .45rem .6rem; border-bottom: 1px solid var(--border); vertical-align: top; }
+  th { cursor: pointer; user-select: none; background: var(--bg); white-space: nowrap; }
+  th.sorted-asc::after { content: " \25B2"; }
+  th.sorted-desc::after { content: " \25BC"; }
+  td.path { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .9rem; }
+  .badge { display: inline-block; border-radius: 4px; padding: .1rem .45rem; color: #fff; font-size: .8rem; text-transform: uppercase; }
+  .badge.critical { background: var(--critical); }
+  .badge.high { background: var(--high); }
+  .badge.medium { background: var(--medium); color: #212121; }
+  .badge.low { background: var(--low); }
+  details.file { border: 1px solid var(--border); border-radius: 8px; margin-bottom: .75rem; }
+  details.file > summary { padding: .6rem .9rem; cursor: pointer; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+  details.file > summary .count { color: var(--muted); font-family: sans-serif; margin-left: .5rem; }
+  .finding { border-top: 1px solid var(--border); padding: .75rem .9rem; }
+  .finding .title { margin-bottom: .4rem; }
+  .finding .where { color: var(--muted); font-size: .9rem; }
+  pre { background: var(--bg); border: 1px solid var(--border); border-radius: 4px; padding: .5rem 0; overflow-x: auto; margin: .5rem 0 0; font-size: .85rem; }
+  pre .line { display: block; padding: 0 .75rem; }
+  pre .line.hl { background: #fff3cd; }
+  pre .num { display: inline-block; width: 3.5em; color: var(--muted); user-select: none; }
+  .fix-label { margin-top: .5rem; color: var(--muted); font-size: .9rem; }
+  .hidden { display: none; }
+  .empty { color: var(--muted); }
+</style>
+</head>
+<body>
+<h1>Churn Analysis Report</h1>
+<div class="meta">
+  Generated {{.Report.Timestamp.Format "2006-01-02 15:04:05"}}{{with .Report.Version}} by churn-plus {{.}}{{end}}
+  {{- with .Report.Context}} &middot; {{.RootPath}}{{end}}
+  {{- with .Report.Revision}} &middot; {{.}}{{end}}
+  &middot; {{.Report.Summary.FilesAnalyzed}} files analyzed in {{printf "%.1f" .Report.Summary.Duration}}s
+  {{- with .Report.Summary.FailedPasses}} &middot; failed passes: {{range $i, $p := .}}{{if $i}}, {{end}}{{$p}}{{end}}{{end}}
+</div>
+
+<h2>Summary</h2>
+<div class="cards">
+  <div class="card"><div class="count">{{len .Findings}}</div><div class="label">findings</div></div>
+  {{- range .Severities}}
+  <div class="card {{.Name}}"><div class="count">{{.Count}}</div><div class="label">{{.Name}}</div></div>
+  {{- end}}
+</div>
+{{- if .Kinds}}
+<div class="kinds">
+  {{- range .Kinds}}
+  <span class="kind">{{.Name}}: {{.Count}}</span>
+  {{- end}}
+</div>
+{{- end}}
+
+<h2>Findings</h2>
+{{- if .Findings}}
+<div class="controls">
+  <input type="search" id="search" placeholder="Filter by file, kind, or message">
+  {{- range .Severities}}
+  <label><input type="checkbox" class="severity-filter" value="{{.Name}}" checked> {{.Name}}</label>
+  {{- end}}
+  <span id="shown" class="empty"></span>
+</div>
+<table id="findings">
+  <thead>
+    <tr>
+      <th data-type="number">Severity</th>
+      <th>File</th>
+      <th data-type="number">Lines</th>
+      <th>Kind</th>
+      <th>Pass</th>
+      <th>Message</th>
+    </tr>
+  </thead>
+  <tbody>
+    {{- range .Findings}}
+    <tr data-severity="{{.Severity}}">
+      <td data-sort="{{.Rank}}"><span class="badge {{.Severity}}">{{.Severity}}</span></td>
+      <td class="path"><a href="#{{.Anchor}}">{{.Path}}</a></td>
+      <td data-sort="{{.LineStart}}">{{.Lines}}</td>
+      <td>{{.Kind}}</td>
+      <td>{{.PassNames}}</td>
+      <td>{{.Message}}</td>
+    </tr>
+    {{- end}}
+  </tbody>
+</table>
+
+<h2>By File</h2>
+{{- range .Files}}
+<details class="file">
+  <summary><span class="badge {{.Worst}}">{{.Worst}}</span> {{.Path}}<span class="count">{{len .Findings}} finding{{if gt (len .Findings) 1}}s{{end}}</span></summary>
+  {{- range .Findings}}
+  <div class="finding" id="{{.Anchor}}">
+    <div class="title"><span class="badge {{.Severity}}">{{.Severity}}</span> <strong>{{.Kind}}</strong> {{.Message}}</div>
+    <div class="where">Lines {{.Lines}}{{with .PassNames}} &middot; {{.}}{{end}}</div>
+    {{- if .Snippet}}
+    <pre>{{range .Snippet}}<span class="line{{if .Highlight}} hl{{end}}"><span class="num">{{.Number}}</span>{{.Text}}</span>{{end}}</pre>
+    {{- end}}
+    {{- with .Code}}
+    <div class="fix-label">Suggested fix</div>
+    <pre><span class="line">{{.}}</span></pre>
+    {{- end}}
+  </div>
+  {{- end}}
+</details>
+{{- end}}
+{{- else}}
+<p class="empty">No findings.</p>
+{{- end}}
+
+<script>
+(function () {
+  var table = document.getElementById("findings");
+  if (!table) return;
+  var tbody = table.tBodies[0];
+  var rows = Array.prototype.slice.call(tbody.rows);
+  var search = document.getElementById("search");
+  var shown = document.getElementById("shown");
+  var checks = document.querySelectorAll(".severity-filter");
+
+  function filter() {
+    var query = search.value.toLowerCase();
+    var severities = {};
+    checks.forEach(function (c) { severities[c.value] = c.checked; });
+    var count = 0;
+    rows.forEach(function (row) {
+      var visible = severities[row.dataset.severity] !== false &&
+        row.textContent.toLowerCase().indexOf(query) !== -1;
+      row.classList.toggle("hidden", !visible);
+      if (visible) count++;
+    });
+    shown.textContent = count === rows.length ? "" : count + " of " + rows.length + " shown";
+  }
+
+  // Open a file's group when following a link to one of its findings
+  tbody.addEventListener("click", function (event) {
+    var link = event.target.closest("a");
+    if (!link) return;
+    var target = document.getElementById(link.getAttribute("href").slice(1));
+    if (target) target.closest("details").open = true;
+  });
+
+  search.addEventListener("input", filter);
+  checks.forEach(function (c) { c.addEventListener("change", filter); });
+
+  var headers = table.tHead.rows[0].cells;
+  Array.prototype.forEach.call(headers, function (th, index) {
+    th.addEventListener("click", function () {
+      var ascending = !th.classList.contains("sorted-asc");
+      Array.prototype.forEach.call(headers, function (h) { h.classList.remove("sorted-asc", "sorted-desc"); });
+      th.classList.add(ascending ? "sorted-asc" : "sorted-desc");
+
+      var numeric = th.dataset.type === "number";
+      rows.sort(function (a, b) {
+        var x = a.cells[index], y = b.cells[index];
+        var result = numeric
+          ? Number(x.dataset.sort) - Number(y.dataset.sort)
+          : x.textContent.localeCompare(y.textContent);
+        return ascending ? result : -result;
+      });
+      rows.forEach(function (row) { tbody.appendChild(row); });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,171 @@
+package engine
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is a single compiled .gitignore pattern
+type ignoreRule struct {
+	pattern  string // Glob, relative to base, without leading or trailing slash
+	base     string // Directory of the .gitignore, relative to the project root ("" for root)
+	negate   bool   // "!pattern" re-includes a previously ignored path
+	dirOnly  bool   // "pattern/" only matches directories
+	anchored bool   // Patterns containing a slash match relative to base only
+}
+
+// ignoreMatcher evaluates .gitignore rules collected while walking a project
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// loadFile parses a .gitignore file whose directory is base (relative to the root)
+func (m *ignoreMatcher) loadFile(path, base string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreLine(scanner.Text(), base); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseIgnoreLine compiles one line of a .gitignore file
+func parseIgnoreLine(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// A slash at the start or in the middle anchors the pattern to base
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.pattern = line
+	return rule, true
+}
+
+// match reports whether relPath (slash separated, relative to the root) is
+// ignored. The last matching rule wins, so later negations re-include paths.
+func (m *ignoreMatcher) match(relPath string, isDir bool) bool {
+	ignored := false
+
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		target := relPath
+		if rule.base != "" {
+			if !strings.HasPrefix(relPath, rule.base+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(relPath, rule.base+"/")
+		}
+
+		var matched bool
+		if rule.anchored {
+			matched = globMatch(rule.pattern, target)
+		} else {
+			matched = globMatch(rule.pattern, path.Base(target))
+		}
+
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// globMatch matches a slash-separated path against a glob where "**"
+// matches any number of path segments
+func globMatch(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches glob segments against path segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// loadGitignore loads the .gitignore in dir, if any
+func (s *Scanner) loadGitignore(dir string) {
+	relDir, err := filepath.Rel(s.rootPath, dir)
+	if err != nil {
+		return
+	}
+	relDir = filepath.ToSlash(relDir)
+	if relDir == "." {
+		relDir = ""
+	}
+
+	// Missing .gitignore files are the common case
+	_ = s.gitignore.loadFile(filepath.Join(dir, ".gitignore"), relDir)
+}
+
+// loadChurnignore loads the project's .churnignore, which uses .gitignore
+// syntax and adds to the configured ignore patterns
+func loadChurnignore(rootPath string) *ignoreMatcher {
+	matcher := &ignoreMatcher{}
+
+	// A missing .churnignore simply adds no rules
+	_ = matcher.loadFile(filepath.Join(rootPath, ".churnignore"), "")
+
+	return matcher
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	matcher := &ignoreMatcher{}
+	gitignores := map[string]string{
+		"":        "*.go\n!keep.go\nbuild/\n/root.go\n",
+		"web":     "*.js\n!/vendor.js\nassets/\n",
+		"web/app": "!*.js\n",
+	}
+	for _, base := range []string{"", "web", "web/app"} {
+		for _, line := range strings.Split(gitignores[base], "\n") {
+			if rule, ok := parseIgnoreLine(line, base); ok {
+				matcher.rules = append(matcher.rules, rule)
+			}
+		}
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		// Negation re-includes what an earlier rule ignored
+		{"main.go", false, true},
+		{"keep.go", false, false},
+		{"pkg/keep.go", false, false},
+
+		// Directory-only rules skip files of the same name
+		{"build", true, true},
+		{"pkg/build", true, true},
+		{"build", false, false},
+
+		// Anchored rules match relative to their .gitignore only
+		{"root.go", false, true},
+		{"pkg/root.go", false, true}, // Still ignored by *.go
+		{"root.txt", false, false},
+
+		// Nested .gitignore rules apply below their own directory
+		{"web/main.js", false, true},
+		{"main.js", false, false},
+		{"web/vendor.js", false, false},
+		{"web/lib/vendor.js", false, true},
+		{"web/assets", true, true},
+		{"assets", true, false},
+		{"web/app/main.js", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := matcher.match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanRespectsNestedGitignore(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":          "build/\n/root.py\n*.gen.go\n!keep.gen.go\n",
+		"main.go":             "package main",
+		"root.py":             "",
+		"pkg/root.py":         "",
+		"pkg/a.gen.go":        "package pkg",
+		"pkg/keep.gen.go":     "package pkg",
+		"build/out.go":        "package build",
+		"web/.gitignore":      "*.js\n!/index.js\n",
+		"web/index.js":        "",
+		"web/app.js":          "",
+		"web/lib/index.js":    "",
+		"web/lib/.gitignore":  "!app.js\n",
+		"web/lib/app.js":      "",
+		"other/app.js":        "",
+		"other/build.go/x.go": "package x",
+	}
+	for name, contents := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner := NewScanner(root, nil)
+	scanner.SetRespectGitignore(true)
+	scanned, err := scanner.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	var got []string
+	for _, file := range scanned {
+		rel, err := filepath.Rel(root, file.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"main.go",
+		"other/app.js",
+		"other/build.go/x.go",
+		"pkg/keep.gen.go",
+		"pkg/root.py",
+		"web/index.js",
+		"web/lib/app.js",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Scan() found\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
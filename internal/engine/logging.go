@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/churn-plus/internal/engine/providers"
+)
+
+// LogLevelEnv overrides the configured log level
+const LogLevelEnv = "CHURN_LOG_LEVEL"
+
+// discardLogger is used until a logger is set
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// ParseLogLevel parses debug, info, warn, or error (case-insensitive)
+func ParseLogLevel(name string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	default:
+		return slog.LevelInfo, false
+	}
+}
+
+// LogsDir returns the directory analysis logs are written to
+func LogsDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".churn", "logs")
+}
+
+// OpenLog opens the project's log file for today, appending to it, and
+// returns a logger writing to it at level and above. The caller closes the
+// file once the run is over.
+func OpenLog(projectRoot string, level slog.Level) (*slog.Logger, *os.File, error) {
+	dir := LogsDir(projectRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "churn-"+time.Now().Format("2006-01-02")+".log")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: level}))
+	return logger, file, nil
+}
+
+// errorAttrs describes an error for the log, adding the HTTP status when an
+// API returned it
+func errorAttrs(err error) []any {
+	attrs := []any{slog.String("error", err.Error())}
+
+	var apiErr *providers.APIError
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+	}
+	return attrs
+}
+
+// maxLoggedResponse bounds how much of a model response is logged
+const maxLoggedResponse = 4000
+
+// truncateForLog shortens a model response for the log
+func truncateForLog(response string) string {
+	if len(response) <= maxLoggedResponse {
+		return response
+	}
+	return response[:maxLoggedResponse] + "... (truncated)"
+}
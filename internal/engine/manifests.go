@@ -0,0 +1,362 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"strings"
+)
+
+// Manifest parsers read the dependencies a project declares, keyed by
+// package name with the version constraint as written ("" when none is
+// given). They are best-effort: unreadable or malformed manifests yield
+// whatever could be parsed, never an error.
+
+// parseGoModDependencies reads the direct requirements of a go.mod file, in
+// both `require x v1` and `require ( ... )` form
+func parseGoModDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		case !inBlock:
+			continue
+		}
+
+		// Indirect requirements are not part of the project's own stack
+		if strings.TrimSpace(comment) == "indirect" {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) == 2 {
+			deps[fields[0]] = fields[1]
+		}
+	}
+	return deps
+}
+
+// parseRequirementsDependencies reads a pip requirements.txt, skipping
+// options such as -r and -e and bare URLs
+func parseRequirementsDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), " #")
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") ||
+			strings.Contains(line, "://") && !strings.Contains(line, "@") {
+			continue
+		}
+
+		if name, version, ok := parseRequirement(line); ok {
+			deps[name] = version
+		}
+	}
+	return deps
+}
+
+// parsePyprojectDependencies reads PEP 621 project dependencies, Poetry
+// dependency tables, and PDM dev-dependency groups from pyproject.toml
+func parsePyprojectDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	for _, entry := range scanTOML(data) {
+		section := entry.section
+		switch {
+		// [project] dependencies = ["fastapi>=0.110", ...]
+		case section == "project" && entry.key == "dependencies",
+			section == "project.optional-dependencies",
+			section == "tool.pdm.dev-dependencies",
+			section == "dependency-groups":
+			for _, requirement := range entry.array {
+				if name, version, ok := parseRequirement(requirement); ok {
+					deps[name] = version
+				}
+			}
+
+		// [tool.poetry.dependencies] fastapi = "^0.110"
+		case section == "tool.poetry.dependencies",
+			section == "tool.poetry.dev-dependencies",
+			strings.HasPrefix(section, "tool.poetry.group.") && strings.HasSuffix(section, ".dependencies"):
+			if entry.key == "python" {
+				continue
+			}
+			deps[normalizePythonName(entry.key)] = entry.value
+		}
+	}
+
+	return deps
+}
+
+// parseCargoDependencies reads the dependency tables of Cargo.toml, including
+// `[dependencies.name]` sub-tables
+func parseCargoDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	for _, entry := range scanTOML(data) {
+		if isCargoDependencyTable(entry.section) {
+			deps[entry.key] = entry.value
+			continue
+		}
+
+		// [dependencies.serde] with version = "1.0"
+		i := strings.LastIndex(entry.section, ".")
+		if i > 0 && isCargoDependencyTable(entry.section[:i]) {
+			name := entry.section[i+1:]
+			if entry.key == "version" {
+				deps[name] = entry.value
+			} else if _, ok := deps[name]; !ok {
+				deps[name] = ""
+			}
+		}
+	}
+
+	return deps
+}
+
+// isCargoDependencyTable reports whether a Cargo.toml table lists
+// dependencies, e.g. [dependencies] or [target.'cfg(unix)'.dev-dependencies]
+func isCargoDependencyTable(section string) bool {
+	for _, table := range []string{"dependencies", "dev-dependencies", "build-dependencies"} {
+		if section == table || section == "workspace."+table ||
+			(strings.HasPrefix(section, "target.") && strings.HasSuffix(section, "."+table)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComposerDependencies reads require and require-dev from composer.json
+func parseComposerDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	var composer struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if json.Unmarshal(data, &composer) != nil {
+		return deps
+	}
+
+	for _, require := range []map[string]string{composer.Require, composer.RequireDev} {
+		for name, version := range require {
+			deps[name] = version
+		}
+	}
+	return deps
+}
+
+// gemPattern matches `gem 'name'` with an optional first version argument
+var gemPattern = regexp.MustCompile(`^\s*gem\s+['"]([^'"]+)['"](?:\s*,\s*['"]([^'"]+)['"])?`)
+
+// parseGemfileDependencies reads the gems declared in a Gemfile
+func parseGemfileDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if match := gemPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			deps[match[1]] = match[2]
+		}
+	}
+	return deps
+}
+
+// parseMavenDependencies reads the dependencies of a pom.xml, keyed by
+// groupId:artifactId, along with the parent POM (where Spring Boot projects
+// declare their version)
+func parseMavenDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	type artifact struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	}
+	var pom struct {
+		Parent       artifact   `xml:"parent"`
+		Dependencies []artifact `xml:"dependencies>dependency"`
+		Managed      []artifact `xml:"dependencyManagement>dependencies>dependency"`
+	}
+	if xml.Unmarshal(data, &pom) != nil {
+		return deps
+	}
+
+	all := append([]artifact{pom.Parent}, pom.Dependencies...)
+	for _, dep := range append(all, pom.Managed...) {
+		if dep.GroupID == "" || dep.ArtifactID == "" {
+			continue
+		}
+		deps[dep.GroupID+":"+dep.ArtifactID] = strings.TrimSpace(dep.Version)
+	}
+	return deps
+}
+
+// Gradle coordinates ("group:artifact:version") and plugin ids
+// (`id 'org.springframework.boot' version '3.2.0'`, or the Kotlin DSL form)
+var (
+	gradleCoordinatePattern = regexp.MustCompile(`['"]([\w.\-]+):([\w.\-]+)(?::([^'"@:]+))?['"]`)
+	gradlePluginPattern     = regexp.MustCompile(`\bid\s*\(?\s*['"]([\w.\-]+)['"]\s*\)?(?:\s+version\s*\(?\s*['"]([^'"]+)['"])?`)
+)
+
+// parseGradleDependencies reads dependency coordinates and plugin ids from a
+// build.gradle or build.gradle.kts file
+func parseGradleDependencies(data []byte) map[string]string {
+	deps := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		if match := gradlePluginPattern.FindStringSubmatch(line); match != nil {
+			deps[match[1]] = match[2]
+			continue
+		}
+		for _, match := range gradleCoordinatePattern.FindAllStringSubmatch(line, -1) {
+			deps[match[1]+":"+match[2]] = match[3]
+		}
+	}
+	return deps
+}
+
+// requirementPattern splits a PEP 508 requirement such as
+// "uvicorn[standard]>=0.29; python_version > '3.8'" into name and version
+var requirementPattern = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._\-]*)\s*(?:\[[^\]]*\])?\s*([^;]*)`)
+
+// parseRequirement parses a PEP 508 requirement string
+func parseRequirement(requirement string) (name, version string, ok bool) {
+	match := requirementPattern.FindStringSubmatch(requirement)
+	if match == nil {
+		return "", "", false
+	}
+	return normalizePythonName(match[1]), strings.TrimSpace(match[2]), true
+}
+
+// normalizePythonName normalizes a Python package name (PEP 503), so
+// "Flask_SQLAlchemy" and "flask-sqlalchemy" match
+func normalizePythonName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// tomlEntry is a key of a TOML table. value holds a string value, or the
+// version key of an inline table; array holds the strings of an array.
+type tomlEntry struct {
+	section string
+	key     string
+	value   string
+	array   []string
+}
+
+// tomlVersionPattern finds `version = "..."` inside an inline table
+var tomlVersionPattern = regexp.MustCompile(`\bversion\s*=\s*["']([^"']*)["']`)
+
+// scanTOML reads the key/value pairs of a TOML document, enough for
+// dependency declarations: table headers, quoted string values, inline
+// tables (only their version is kept), and arrays of strings, which may span
+// several lines. Other values are returned with an empty value.
+func scanTOML(data []byte) []tomlEntry {
+	var entries []tomlEntry
+	var section string
+	var pending *tomlEntry // Array still waiting for its closing bracket
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if pending != nil {
+			pending.array = append(pending.array, tomlStrings(line)...)
+			if strings.Contains(line, "]") {
+				entries = append(entries, *pending)
+				pending = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			// [table] or [[array-of-tables]]
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		entry := tomlEntry{
+			section: section,
+			key:     strings.Trim(strings.TrimSpace(key), `"'`),
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, "["):
+			entry.array = tomlStrings(value)
+			if !strings.Contains(value, "]") {
+				pending = &entry
+				continue
+			}
+		case strings.HasPrefix(value, "{"):
+			if match := tomlVersionPattern.FindStringSubmatch(value); match != nil {
+				entry.value = match[1]
+			}
+		default:
+			entry.value = strings.Trim(value, `"'`)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// tomlStringPattern matches a quoted TOML string
+var tomlStringPattern = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// tomlStrings returns the quoted strings on a line
+func tomlStrings(line string) []string {
+	var values []string
+	for _, match := range tomlStringPattern.FindAllStringSubmatch(line, -1) {
+		values = append(values, match[1]+match[2])
+	}
+	return values
+}
+
+// stripTOMLComment removes a trailing # comment that is not inside a string
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ReportSchemaVersion is the report shape this build reads and writes. Bump
+// it together with a new entry in reportMigrations whenever fields are
+// renamed or reinterpreted.
+const ReportSchemaVersion = 1
+
+// reportMigrations[i] upgrades a decoded report from version i to i+1
+var reportMigrations = []func(doc map[string]interface{}){
+	migrateReportV0,
+}
+
+// decodeReport parses a report, first upgrading documents written with an
+// older schema. It reports whether a migration ran.
+func decodeReport(data []byte) (*AnalysisReport, bool, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	// JSON numbers decode as float64
+	version := 0
+	if v, ok := doc["schema_version"].(float64); ok {
+		version = int(v)
+	}
+	if version > ReportSchemaVersion {
+		return nil, false, fmt.Errorf("report schema version %d is newer than this churn-plus supports (%d)", version, ReportSchemaVersion)
+	}
+
+	for from := version; from < ReportSchemaVersion; from++ {
+		reportMigrations[from](doc)
+	}
+	doc["schema_version"] = ReportSchemaVersion
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var report AnalysisReport
+	if err := json.Unmarshal(upgraded, &report); err != nil {
+		return nil, false, err
+	}
+
+	return &report, version < ReportSchemaVersion, nil
+}
+
+// rewriteReport saves an upgraded report back over path
+func rewriteReport(path string, report *AnalysisReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
+
+// migrateReportV0 upgrades unversioned reports. Early reports could be
+// written without summary counts, which the menu and compare screens read;
+// they are rebuilt from the findings.
+func migrateReportV0(doc map[string]interface{}) {
+	summary, ok := doc["summary"].(map[string]interface{})
+	if !ok {
+		summary = make(map[string]interface{})
+		doc["summary"] = summary
+	}
+
+	findings, _ := doc["findings"].([]interface{})
+	if _, ok := summary["finding_count"]; !ok {
+		summary["finding_count"] = len(findings)
+	}
+
+	_, hasSeverity := summary["by_severity"].(map[string]interface{})
+	_, hasKind := summary["by_kind"].(map[string]interface{})
+	if hasSeverity && hasKind {
+		return
+	}
+
+	bySeverity := make(map[string]interface{})
+	byKind := make(map[string]interface{})
+	for _, finding := range findings {
+		finding, ok := finding.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if severity, ok := finding["severity"].(string); ok {
+			bySeverity[severity] = countOf(bySeverity[severity]) + 1
+		}
+		if kind, ok := finding["kind"].(string); ok {
+			byKind[kind] = countOf(byKind[kind]) + 1
+		}
+	}
+
+	if !hasSeverity {
+		summary["by_severity"] = bySeverity
+	}
+	if !hasKind {
+		summary["by_kind"] = byKind
+	}
+}
+
+// countOf reads a count stored in a migration map
+func countOf(value interface{}) int {
+	count, _ := value.(int)
+	return count
+}
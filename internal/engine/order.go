@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileOrder sets the order files are analyzed in, so a run that is cut
+// short has covered the most important files
+type FileOrder string
+
+const (
+	FileOrderScan     FileOrder = "scan"     // Scan order (default)
+	FileOrderSize     FileOrder = "size"     // Most lines of code first
+	FileOrderChurn    FileOrder = "churn"    // Most frequently committed first
+	FileOrderModified FileOrder = "modified" // Most recently modified first
+)
+
+// churnCommitLimit bounds how much history is read to measure churn, so
+// large repositories stay fast; recent history matters most anyway
+const churnCommitLimit = 1000
+
+// OrderFiles sorts files in place for analysis. Ties, and files with no
+// data (e.g. untracked files when ordering by churn), keep scan order.
+func OrderFiles(files []*FileInfo, order FileOrder, projectRoot string) error {
+	var score func(file *FileInfo) int64
+
+	switch order {
+	case "", FileOrderScan:
+		return nil
+
+	case FileOrderSize:
+		score = func(file *FileInfo) int64 {
+			if file.CodeLines > 0 {
+				return int64(file.CodeLines)
+			}
+			return int64(file.Lines)
+		}
+
+	case FileOrderChurn:
+		commits, err := gitCommitCounts(projectRoot)
+		if err != nil {
+			return err
+		}
+		score = func(file *FileInfo) int64 {
+			return int64(commits[relativePath(projectRoot, file.Path)])
+		}
+
+	case FileOrderModified:
+		modified := make(map[string]time.Time, len(files))
+		for _, file := range files {
+			if info, err := os.Stat(file.Path); err == nil {
+				modified[file.Path] = info.ModTime()
+			}
+		}
+		score = func(file *FileInfo) int64 {
+			return modified[file.Path].UnixNano()
+		}
+
+	default:
+		return fmt.Errorf("unknown file order %q", order)
+	}
+
+	scores := make(map[*FileInfo]int64, len(files))
+	for _, file := range files {
+		scores[file] = score(file)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return scores[files[i]] > scores[files[j]]
+	})
+	return nil
+}
+
+// gitCommitCounts returns how many recent commits touched each file, keyed
+// by path relative to projectRoot with forward slashes
+func gitCommitCounts(projectRoot string) (map[string]int, error) {
+	toplevel, err := runGit(projectRoot, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git repository", projectRoot)
+	}
+	toplevel = strings.TrimSpace(toplevel)
+
+	// -z keeps paths unquoted, so names with spaces, newlines or non-ASCII
+	// characters come through as they are on disk
+	log, err := runGit(projectRoot, "log", "--no-merges", "--format=", "--name-only", "-z",
+		fmt.Sprintf("--max-count=%d", churnCommitLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	// Git reports paths from the repository root, which may be above projectRoot
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	counts := make(map[string]int)
+	for _, name := range strings.Split(log, "\x00") {
+		if name == "" {
+			continue
+		}
+		rel, err := filepath.Rel(root, filepath.Join(toplevel, filepath.FromSlash(name)))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		counts[filepath.ToSlash(rel)]++
+	}
+
+	return counts, nil
+}
+
+// relativePath returns path relative to root with forward slashes
+func relativePath(root, path string) string {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitCommitCountsUnusualNames(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := runGit(root, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := []string{"plain.go", "with space.go", "ünïcode.go", "quote\".go"}
+
+	git("init", "-q")
+	for _, name := range names {
+		write(name, "1")
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "first")
+	write("with space.go", "2")
+	write("ünïcode.go", "2")
+	git("commit", "-q", "-a", "-m", "second")
+
+	counts, err := gitCommitCounts(root)
+	if err != nil {
+		t.Fatalf("gitCommitCounts: %v", err)
+	}
+
+	want := map[string]int{"plain.go": 1, "with space.go": 2, "ünïcode.go": 2, "quote\".go": 1}
+	if len(counts) != len(want) {
+		t.Errorf("gitCommitCounts() = %v, want %v", counts, want)
+	}
+	for name, n := range want {
+		if counts[name] != n {
+			t.Errorf("counts[%q] = %d, want %d", name, counts[name], n)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package passes
+
+import (
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+)
+
+// CreateDocumentationPass creates the opt-in Documentation pass
+func CreateDocumentationPass(provider, model string) *engine.Pass {
+	return &engine.Pass{
+		Name:        "documentation",
+		Description: "Flags missing or outdated documentation on public APIs",
+		Status:      engine.PassPending,
+		Model:       model,
+		Provider:    provider,
+	}
+}
@@ -0,0 +1,16 @@
+package passes
+
+import (
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+)
+
+// CreateNamingPass creates the opt-in Naming conventions pass
+func CreateNamingPass(provider, model string) *engine.Pass {
+	return &engine.Pass{
+		Name:        "naming",
+		Description: "Flags inconsistent or misleading identifier names",
+		Status:      engine.PassPending,
+		Model:       model,
+		Provider:    provider,
+	}
+}
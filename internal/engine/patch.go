@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PatchResult describes a patch written to disk
+type PatchResult struct {
+	Path       string // File that was patched
+	BackupPath string // Copy of the original under .churn/backups/
+	Additions  int
+	Deletions  int
+}
+
+// ApplyDiffToFile applies a diff to its file inside projectRoot. The original
+// is backed up under .churn/backups/<timestamp>/ and the new content is
+// written atomically. When expectedHash is set (the sha256 recorded at
+// analysis time), the patch is refused if the file has changed since.
+func ApplyDiffToFile(projectRoot string, diff *Diff, expectedHash string) (*PatchResult, error) {
+	path := diff.FilePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+
+	if strings.Contains(path, ArchiveSeparator) {
+		return nil, fmt.Errorf("cannot patch files inside archives")
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if expectedHash != "" && fmt.Sprintf("%x", sha256.Sum256(content)) != expectedHash {
+		return nil, fmt.Errorf("%s changed since it was analyzed; re-run the analysis", diff.FilePath)
+	}
+
+	modified, err := diff.Apply(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	backupPath, err := backupFile(projectRoot, path, content, stat.Mode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFileAtomic(path, []byte(modified), stat.Mode()); err != nil {
+		return nil, err
+	}
+
+	additions, deletions := diff.GetChangeCount()
+	return &PatchResult{
+		Path:       path,
+		BackupPath: backupPath,
+		Additions:  additions,
+		Deletions:  deletions,
+	}, nil
+}
+
+// backupFile copies content to .churn/backups/<timestamp>/<relative path>
+func backupFile(projectRoot, path string, content []byte, mode os.FileMode) (string, error) {
+	relPath, err := filepath.Rel(projectRoot, path)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		relPath = filepath.Base(path)
+	}
+
+	timestamp := time.Now().Format("2006-01-02T15-04-05")
+	backupPath := filepath.Join(projectRoot, ".churn", "backups", timestamp, relPath)
+
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := os.WriteFile(backupPath, content, mode); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it into place
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".churn-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	return nil
+}
@@ -2,8 +2,27 @@ package engine
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
+
+	"github.com/cloudboy-jh/churn-plus/internal/engine/providers"
+)
+
+// ErrorPolicy controls what happens to the remaining passes when one fails
+type ErrorPolicy string
+
+const (
+	ErrorPolicyStop     ErrorPolicy = "stop"
+	ErrorPolicyContinue ErrorPolicy = "continue" // Default
 )
 
 // PipelineOrchestrator manages the execution of analysis passes
@@ -11,6 +30,51 @@ type PipelineOrchestrator struct {
 	pipeline *Pipeline
 	provider ModelProvider
 	events   chan PipelineEvent
+
+	// eventsClosed makes closing events safe from both Execute and runs
+	// that fail before reaching it
+	eventsClosed sync.Once
+
+	// Providers for passes whose Provider differs from the default one,
+	// keyed by provider name
+	passProviders map[string]ModelProvider
+
+	// Optional NDJSON sink that receives each finding as soon as it is
+	// parsed; it is dropped after the first failed write
+	findingStream io.Writer
+	streamMu      sync.Mutex
+
+	// concurrencyLimit returns how many files may be analyzed at once per provider
+	concurrencyLimit func(provider string) int
+
+	// Optional cache of raw LLM responses
+	cache *ResponseCache
+
+	// Optional checkpoint of finished files, so an interrupted run can resume
+	checkpoint *Checkpoint
+
+	onError ErrorPolicy
+
+	// promptOptions controls how file content is rendered in prompts
+	promptOptions PromptOptions
+
+	// contextWindows overrides model context windows, keyed by name prefix
+	contextWindows map[string]int
+
+	// requestTimeout bounds requests of passes without their own timeout
+	requestTimeout time.Duration
+
+	// structuredOutput requests findings as schema-checked JSON from
+	// providers that support it; models that reject it are remembered in
+	// unstructuredModels and fall back to prompt-only mode
+	structuredOutput   bool
+	unstructuredModels map[string]bool
+	structuredMu       sync.Mutex
+
+	// usageMu guards token usage accumulated on passes by concurrent workers
+	usageMu sync.Mutex
+
+	logger *slog.Logger
 }
 
 // NewPipelineOrchestrator creates a new pipeline orchestrator
@@ -21,13 +85,24 @@ func NewPipelineOrchestrator(provider ModelProvider) *PipelineOrchestrator {
 			Findings:  make([]*Finding, 0),
 			StartTime: time.Now(),
 		},
-		provider: provider,
-		events:   make(chan PipelineEvent, 100),
+		provider:      provider,
+		events:        make(chan PipelineEvent, 100),
+		passProviders: make(map[string]ModelProvider),
+		onError:       ErrorPolicyContinue,
+
+		promptOptions:      DefaultPromptOptions(),
+		structuredOutput:   true,
+		unstructuredModels: make(map[string]bool),
+
+		logger: discardLogger,
 	}
 }
 
-// AddPass adds a pass to the pipeline
+// AddPass adds a pass to the pipeline, defaulting its mode by name
 func (po *PipelineOrchestrator) AddPass(pass *Pass) {
+	if pass.Mode == "" {
+		pass.Mode = DefaultPassMode(pass.Name)
+	}
 	po.pipeline.Passes = append(po.pipeline.Passes, pass)
 }
 
@@ -36,28 +111,129 @@ func (po *PipelineOrchestrator) SetContext(ctx *ProjectContext) {
 	po.pipeline.Context = ctx
 }
 
+// SetFindingStream sets a writer that receives every finding as a single JSON
+// line (NDJSON) the moment it is returned from the LLM. If a write fails,
+// the error is logged and the stream is no longer written to.
+func (po *PipelineOrchestrator) SetFindingStream(w io.Writer) {
+	po.streamMu.Lock()
+	defer po.streamMu.Unlock()
+	po.findingStream = w
+}
+
+// SetConcurrencyLimit sets how many files are analyzed in parallel for a
+// pass, looked up by the pass's provider
+func (po *PipelineOrchestrator) SetConcurrencyLimit(limit func(provider string) int) {
+	po.concurrencyLimit = limit
+}
+
+// SetErrorPolicy sets whether a failed pass stops the pipeline or lets the
+// remaining passes run. Unknown values fall back to continue.
+func (po *PipelineOrchestrator) SetErrorPolicy(policy ErrorPolicy) {
+	if policy != ErrorPolicyStop {
+		policy = ErrorPolicyContinue
+	}
+	po.onError = policy
+}
+
+// SetPromptOptions sets how file content is rendered in prompts
+func (po *PipelineOrchestrator) SetPromptOptions(opts PromptOptions) {
+	po.promptOptions = opts
+}
+
+// SetContextWindows overrides the context window of models, keyed by model
+// name prefix. Files whose prompts exceed the window are analyzed in chunks.
+func (po *PipelineOrchestrator) SetContextWindows(windows map[string]int) {
+	po.contextWindows = windows
+}
+
+// SetRequestTimeout sets the timeout for passes that don't set their own;
+// zero uses the provider default
+func (po *PipelineOrchestrator) SetRequestTimeout(timeout time.Duration) {
+	po.requestTimeout = timeout
+}
+
+// SetStructuredOutput sets whether findings are requested as structured JSON
+// from providers that support it (enabled by default)
+func (po *PipelineOrchestrator) SetStructuredOutput(enabled bool) {
+	po.structuredOutput = enabled
+}
+
+// SetPromptTemplates sets per-pass prompt templates that replace the built-in prompt
+func (po *PipelineOrchestrator) SetPromptTemplates(templates map[string]*template.Template) {
+	po.promptOptions.Templates = templates
+}
+
+// SetCache sets the response cache consulted before each LLM request
+func (po *PipelineOrchestrator) SetCache(cache *ResponseCache) {
+	po.cache = cache
+}
+
+// SetCheckpoint sets the checkpoint that finished files are recorded to.
+// Files it already holds with unchanged content are not analyzed again.
+func (po *PipelineOrchestrator) SetCheckpoint(checkpoint *Checkpoint) {
+	po.checkpoint = checkpoint
+}
+
+// SetPassProvider sets the provider that passes with the given Provider
+// name are sent to instead of the default one
+func (po *PipelineOrchestrator) SetPassProvider(name string, provider ModelProvider) {
+	po.passProviders[name] = provider
+}
+
+// hasPassProvider reports whether a provider was set for name
+func (po *PipelineOrchestrator) hasPassProvider(name string) bool {
+	_, ok := po.passProviders[name]
+	return ok
+}
+
+// providerFor returns the provider a pass's requests are sent to
+func (po *PipelineOrchestrator) providerFor(pass *Pass) ModelProvider {
+	if provider, ok := po.passProviders[pass.Provider]; ok {
+		return provider
+	}
+	return po.provider
+}
+
+// SetLogger sets the logger that per-file outcomes, parse failures, and
+// request errors are logged to
+func (po *PipelineOrchestrator) SetLogger(logger *slog.Logger) {
+	po.logger = logger
+}
+
 // Events returns the event channel for subscribing to pipeline updates
 func (po *PipelineOrchestrator) Events() <-chan PipelineEvent {
 	return po.events
 }
 
-// Execute runs the pipeline
+// closeEvents closes the event channel, once
+func (po *PipelineOrchestrator) closeEvents() {
+	po.eventsClosed.Do(func() { close(po.events) })
+}
+
+// Execute runs the pipeline, closing the event channel when it returns.
+// Failed passes are marked PassFailed; with the continue policy the
+// remaining passes still run and Execute returns nil.
 func (po *PipelineOrchestrator) Execute(ctx context.Context, files []*FileInfo) error {
-	defer close(po.events)
+	defer po.closeEvents()
 
-	for _, pass := range po.pipeline.Passes {
-		if err := po.executePass(ctx, pass, files); err != nil {
+	for i, pass := range po.pipeline.Passes {
+		if err := po.executePass(ctx, i, pass, files); err != nil {
 			pass.Status = PassFailed
 			pass.Error = err.Error()
 			pass.EndTime = time.Now()
 
+			po.logger.Error("pass failed", append([]any{slog.String("pass", pass.Name)}, errorAttrs(err)...)...)
+
 			po.events <- PipelineEvent{
-				Type:  EventPassFailed,
-				Pass:  pass,
-				Error: err,
+				Type:      EventPassFailed,
+				Pass:      pass,
+				Error:     err,
+				PassIndex: i,
 			}
 
-			return fmt.Errorf("pass %s failed: %w", pass.Name, err)
+			if po.onError == ErrorPolicyStop || ctx.Err() != nil {
+				return fmt.Errorf("pass %s failed: %w", pass.Name, err)
+			}
 		}
 	}
 
@@ -66,81 +242,468 @@ func (po *PipelineOrchestrator) Execute(ctx context.Context, files []*FileInfo)
 }
 
 // executePass runs a single pass
-func (po *PipelineOrchestrator) executePass(ctx context.Context, pass *Pass, files []*FileInfo) error {
+func (po *PipelineOrchestrator) executePass(ctx context.Context, index int, pass *Pass, files []*FileInfo) error {
 	pass.Status = PassRunning
 	pass.StartTime = time.Now()
 
+	// Chained passes build on what the passes before them found
+	var prior map[string][]*Finding
+	if pass.Mode.UsesFindings() {
+		prior = findingsByFile(po.pipeline.Findings)
+		if pass.Mode == PassModeFindings {
+			files = filesWithFindings(files, prior)
+		}
+	}
+
+	po.logger.Info("pass started",
+		slog.String("pass", pass.Name),
+		slog.String("provider", pass.Provider),
+		slog.String("model", pass.Model),
+		slog.String("mode", string(pass.Mode)),
+		slog.Int("files", len(files)))
+
 	po.events <- PipelineEvent{
-		Type: EventPassStarted,
-		Pass: pass,
+		Type:      EventPassStarted,
+		Pass:      pass,
+		PassIndex: index,
+		Total:     len(files),
 	}
 
 	// Execute the pass based on its type
-	findings, err := po.runPassAnalysis(ctx, pass, files)
+	findings, err := po.runPassAnalysis(ctx, index, pass, files, prior)
 	if err != nil {
 		return err
 	}
 
-	// Add findings to pipeline
-	for _, finding := range findings {
-		finding.Pass = pass.Name
-		po.pipeline.Findings = append(po.pipeline.Findings, finding)
-
-		po.events <- PipelineEvent{
-			Type:    EventFindingAdded,
-			Finding: finding,
-		}
-	}
+	// Add findings to pipeline (events were already emitted per file)
+	po.pipeline.Findings = append(po.pipeline.Findings, findings...)
 
 	pass.Status = PassCompleted
 	pass.EndTime = time.Now()
 
+	po.logger.Info("pass completed",
+		slog.String("pass", pass.Name),
+		slog.Int("findings", len(findings)),
+		slog.Duration("duration", pass.EndTime.Sub(pass.StartTime)),
+		slog.Int("prompt_tokens", pass.Usage.PromptTokens),
+		slog.Int("completion_tokens", pass.Usage.CompletionTokens))
+
 	po.events <- PipelineEvent{
-		Type: EventPassCompleted,
-		Pass: pass,
+		Type:      EventPassCompleted,
+		Pass:      pass,
+		PassIndex: index,
+		Current:   len(files),
+		Total:     len(files),
 	}
 
 	return nil
 }
 
-// runPassAnalysis performs the actual analysis for a pass
-func (po *PipelineOrchestrator) runPassAnalysis(ctx context.Context, pass *Pass, files []*FileInfo) ([]*Finding, error) {
+// runPassAnalysis performs the actual analysis for a pass, spreading files
+// across a worker pool sized by the pass provider's concurrency limit. A
+// progress event is sent as each file finishes. prior holds earlier passes'
+// findings by file for chained passes.
+func (po *PipelineOrchestrator) runPassAnalysis(ctx context.Context, index int, pass *Pass, files []*FileInfo, prior map[string][]*Finding) ([]*Finding, error) {
+	results := make([][]*Finding, len(files))
+
+	var done atomic.Int64
+
+	workers := 1
+	if po.concurrencyLimit != nil {
+		if limit := po.concurrencyLimit(pass.Provider); limit > 0 {
+			workers = limit
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = po.analyzeFile(ctx, pass, files[idx], prior[files[idx].Path])
+
+				po.events <- PipelineEvent{
+					Type:      EventPassProgress,
+					Pass:      pass,
+					Message:   fmt.Sprintf("Analyzed %s", files[idx].Path),
+					PassIndex: index,
+					Current:   int(done.Add(1)),
+					Total:     len(files),
+				}
+			}
+		}()
+	}
+
+	// Stop handing out files once the context is cancelled
+dispatch:
+	for idx := range files {
+		select {
+		case jobs <- idx:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
 	findings := make([]*Finding, 0)
+	for _, fileFindings := range results {
+		findings = append(findings, fileFindings...)
+	}
+
+	return findings, ctx.Err()
+}
+
+// findingsByFile groups findings by the file they were reported in
+func findingsByFile(findings []*Finding) map[string][]*Finding {
+	byFile := make(map[string][]*Finding)
+	for _, finding := range findings {
+		byFile[finding.File] = append(byFile[finding.File], finding)
+	}
+	return byFile
+}
 
-	// For each file, send to LLM for analysis
+// filesWithFindings returns the files that have findings in byFile
+func filesWithFindings(files []*FileInfo, byFile map[string][]*Finding) []*FileInfo {
+	kept := make([]*FileInfo, 0, len(byFile))
 	for _, file := range files {
-		// Send progress event
-		po.events <- PipelineEvent{
-			Type:    EventPassProgress,
-			Pass:    pass,
-			Message: fmt.Sprintf("Analyzing %s", file.Path),
+		if len(byFile[file.Path]) > 0 {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// analyzeFile sends a single file to the LLM and returns its findings.
+// prior are the file's findings from earlier passes, given to chained passes.
+func (po *PipelineOrchestrator) analyzeFile(ctx context.Context, pass *Pass, file *FileInfo, prior []*Finding) []*Finding {
+	// Reuse the findings of a file finished before the run was interrupted
+	var hash string
+	if po.checkpoint != nil {
+		hash = contentHash(file.Path)
+		if findings, ok := po.checkpoint.Lookup(pass, file.Path, hash); ok && hash != "" {
+			po.logger.Debug("file reused from checkpoint",
+				slog.String("pass", pass.Name),
+				slog.String("file", file.Path),
+				slog.Int("findings", len(findings)))
+			for _, finding := range findings {
+				po.emitFinding(finding)
+			}
+			return findings
 		}
+	}
 
-		// Build prompt for this file
-		prompt, err := BuildPromptForFile(file, po.pipeline.Context, pass)
+	// Request analysis from LLM
+	opts := DefaultRequestOptions()
+	opts.Model = pass.Model
+	opts.SystemPrompt = GetSystemPromptForPass(pass)
+	if pass.Temperature != nil {
+		opts.Temperature = *pass.Temperature
+	}
+	if pass.MaxTokens > 0 {
+		opts.MaxTokens = pass.MaxTokens
+	}
+	opts.Timeout = po.requestTimeout
+	if pass.Timeout > 0 {
+		opts.Timeout = pass.Timeout
+	}
+	if po.useStructuredOutput(pass, opts.Model) {
+		opts.ResponseSchema = findingsSchema
+	}
+
+	fileFindings, err := po.requestFindings(ctx, pass, file, prior, opts)
+	if err != nil {
+		// Log error but continue with other files
+		if ctx.Err() == nil {
+			po.logger.Warn("file skipped", append([]any{
+				slog.String("pass", pass.Name),
+				slog.String("file", file.Path),
+			}, errorAttrs(err)...)...)
+		}
+		return nil
+	}
+
+	// Drop findings silenced in the source and note function complexity
+	var spans []functionSpan
+	var content string
+	if len(fileFindings) > 0 {
+		if data, err := ReadFileContent(file.Path); err == nil {
+			content = string(data)
+			fileFindings = FilterSuppressed(fileFindings, content)
+			spans = analyzeFunctions(content, file.Language)
+		}
+	}
+
+	for _, finding := range fileFindings {
+		finding.Pass = pass.Name
+		finding.FunctionComplexity = enclosingComplexity(spans, finding.LineStart)
+		finding.Source = findingSource(content, finding.LineStart, finding.LineEnd)
+		po.emitFinding(finding)
+	}
+
+	po.logger.Debug("file analyzed",
+		slog.String("pass", pass.Name),
+		slog.String("file", file.Path),
+		slog.Int("findings", len(fileFindings)))
+
+	if po.checkpoint != nil && hash != "" {
+		if err := po.checkpoint.Record(pass, file.Path, hash, fileFindings); err != nil && po.checkpoint.disable() {
+			po.logger.Warn("checkpoint disabled", errorAttrs(err)...)
+			po.events <- PipelineEvent{
+				Type:    EventWarning,
+				Pass:    pass,
+				Message: fmt.Sprintf("checkpoint disabled, an interrupted run will start over: %v", err),
+				Error:   fmt.Errorf("%w: %v", ErrCheckpointUnavailable, err),
+			}
+		}
+	}
+
+	return fileFindings
+}
+
+// requestFindings analyzes a file in one request, or in overlapping chunks
+// when the prompt would not fit the model's context window
+func (po *PipelineOrchestrator) requestFindings(ctx context.Context, pass *Pass, file *FileInfo, prior []*Finding, opts RequestOptions) ([]*Finding, error) {
+	promptOpts := po.promptOptions
+	if pass.Mode.UsesFindings() {
+		promptOpts.PriorFindings = prior
+	}
+
+	prompt, err := BuildPromptForFile(file, po.pipeline.Context, pass, promptOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+
+	// Leave room for the response, but never more than a quarter of the window
+	window := ContextWindow(opts.Model, po.contextWindows)
+	budget := window - min(opts.MaxTokens, window/4) - EstimateTokens(opts.SystemPrompt)
+	promptTokens := EstimateTokens(prompt)
+	// Findings-only prompts carry no file content to split
+	if promptTokens <= budget || file.Lines <= 1 || pass.Mode == PassModeFindings {
+		response, err := po.request(ctx, pass, prompt, opts)
 		if err != nil {
-			continue // Skip files we can't build prompts for
+			return nil, err
 		}
+		return po.parseFindings(pass, file, response, opts), nil
+	}
+
+	// Size chunks from the fixed prompt overhead (measured with a one-line
+	// chunk) and the average tokens per line
+	probeOpts := promptOpts
+	probeOpts.Chunk = &LineRange{Start: 1, End: 1}
+	probe, err := BuildPromptForFile(file, po.pipeline.Context, pass, probeOpts)
+	if err != nil {
+		return nil, err
+	}
+	overhead := EstimateTokens(probe)
+	perLine := float64(promptTokens-overhead) / float64(file.Lines-1)
+	linesPerChunk := 1
+	if perLine > 0 {
+		// Keep a small margin since line lengths vary across the file
+		linesPerChunk = int(float64(budget-overhead) * 0.95 / perLine)
+	}
+	if budget <= overhead || linesPerChunk < 1 {
+		return nil, fmt.Errorf("%s does not fit the context window of %s", file.Path, opts.Model)
+	}
+	overlap := linesPerChunk / 10
+
+	po.logger.Debug("file analyzed in chunks",
+		slog.String("pass", pass.Name),
+		slog.String("file", file.Path),
+		slog.Int("lines_per_chunk", linesPerChunk),
+		slog.Int("overlap", overlap))
 
-		// Request analysis from LLM
-		opts := DefaultRequestOptions()
-		opts.Model = pass.Model
-		opts.SystemPrompt = GetSystemPromptForPass(pass)
+	findings := make([]*Finding, 0)
+	seen := make(map[string]bool)
+	for start := 1; start <= file.Lines; start += linesPerChunk - overlap {
+		end := min(start+linesPerChunk-1, file.Lines)
 
-		response, err := po.provider.Request(ctx, prompt, opts)
+		chunkOpts := promptOpts
+		chunkOpts.Chunk = &LineRange{Start: start, End: end}
+		chunkPrompt, err := BuildPromptForFile(file, po.pipeline.Context, pass, chunkOpts)
 		if err != nil {
-			// Log error but continue with other files
-			continue
+			return nil, err
 		}
 
-		// Parse findings from response
-		fileFindings := ParseFindingsFromResponse(file.Path, response)
-		findings = append(findings, fileFindings...)
+		response, err := po.request(ctx, pass, chunkPrompt, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, finding := range po.parseFindings(pass, file, response, opts) {
+			// Unnumbered chunks report lines relative to the chunk
+			if !chunkOpts.NumberLines {
+				finding.LineStart += start - 1
+				finding.LineEnd += start - 1
+			}
+
+			// Overlapping regions may report the same issue twice
+			key := fmt.Sprintf("%d:%s", finding.LineStart, finding.Kind)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, finding)
+		}
+
+		if end == file.Lines {
+			break
+		}
 	}
 
 	return findings, nil
 }
 
+// parseFindings parses a response, warning subscribers when it cannot be
+// parsed instead of silently dropping its findings
+func (po *PipelineOrchestrator) parseFindings(pass *Pass, file *FileInfo, response string, opts RequestOptions) []*Finding {
+	findings, err := parseFindings(file.Path, response, opts.ResponseSchema != nil)
+	if err != nil {
+		po.logger.Warn("unparseable response", append([]any{
+			slog.String("pass", pass.Name),
+			slog.String("file", file.Path),
+		}, errorAttrs(err)...)...)
+		po.logger.Debug("unparseable response body",
+			slog.String("file", file.Path),
+			slog.String("response", truncateForLog(response)))
+		po.events <- PipelineEvent{
+			Type:    EventWarning,
+			Pass:    pass,
+			Message: fmt.Sprintf("%s: %v", file.Path, err),
+			Error:   err,
+		}
+	}
+	return findings
+}
+
+// request sends a prompt to the provider, serving it from the cache when an
+// identical request was answered within the TTL
+func (po *PipelineOrchestrator) request(ctx context.Context, pass *Pass, prompt string, opts RequestOptions) (string, error) {
+	if po.cache == nil {
+		return po.requestWithUsage(ctx, pass, prompt, opts)
+	}
+
+	// The prompt embeds the file content, so edits invalidate the entry
+	key := CacheKey(po.providerFor(pass).Name(), opts, prompt, pass.Name)
+	if response, ok := po.cache.Get(key); ok {
+		po.logger.Debug("response served from cache", slog.String("pass", pass.Name))
+		return response, nil
+	}
+
+	response, err := po.requestWithUsage(ctx, pass, prompt, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// A failed cache write only costs a future API call
+	_ = po.cache.Put(key, response)
+
+	return response, nil
+}
+
+// requestWithUsage sends a prompt and adds the tokens used to the pass
+func (po *PipelineOrchestrator) requestWithUsage(ctx context.Context, pass *Pass, prompt string, opts RequestOptions) (string, error) {
+	response, usage, err := po.providerFor(pass).RequestWithUsage(ctx, prompt, opts)
+
+	po.usageMu.Lock()
+	pass.Usage = pass.Usage.Add(usage)
+	pass.EstimatedCost += EstimateCost(opts.Model, usage)
+	po.usageMu.Unlock()
+
+	// A model without JSON mode or tool use rejects the request outright;
+	// retry in prompt-only mode and stop asking for it
+	if err != nil && opts.ResponseSchema != nil && isStructuredOutputRejected(err) {
+		po.disableStructuredOutput(pass, opts.Model, err)
+		opts.ResponseSchema = nil
+		return po.requestWithUsage(ctx, pass, prompt, opts)
+	}
+
+	return response, err
+}
+
+// ErrStructuredOutputUnsupported marks the warning sent when a model rejects
+// structured output and the pipeline falls back to prompt-only mode
+var ErrStructuredOutputUnsupported = errors.New("structured output not supported")
+
+// useStructuredOutput reports whether findings from model should be
+// requested as structured JSON
+func (po *PipelineOrchestrator) useStructuredOutput(pass *Pass, model string) bool {
+	if !po.structuredOutput || !providers.SupportsStructuredOutput(po.providerFor(pass)) {
+		return false
+	}
+	po.structuredMu.Lock()
+	defer po.structuredMu.Unlock()
+	return !po.unstructuredModels[model]
+}
+
+// disableStructuredOutput switches model to prompt-only mode, warning once
+func (po *PipelineOrchestrator) disableStructuredOutput(pass *Pass, model string, err error) {
+	po.structuredMu.Lock()
+	alreadyDisabled := po.unstructuredModels[model]
+	po.unstructuredModels[model] = true
+	po.structuredMu.Unlock()
+
+	if !alreadyDisabled {
+		po.logger.Warn("structured output unsupported, using prompt-only mode",
+			append([]any{slog.String("model", model)}, errorAttrs(err)...)...)
+		po.events <- PipelineEvent{
+			Type:    EventWarning,
+			Pass:    pass,
+			Message: fmt.Sprintf("%s does not support structured output, falling back to prompt-only mode", model),
+			Error:   fmt.Errorf("%w: %v", ErrStructuredOutputUnsupported, err),
+		}
+	}
+}
+
+// structuredOutputParams are the request parameters a 400 names when the
+// model does not support JSON mode or tool use
+var structuredOutputParams = []string{"response_format", "json_schema", "tools", "tool_choice"}
+
+// isStructuredOutputRejected reports whether the API refused the request
+// (400) because of its structured output parameters. Other 400s, such as an
+// overlong prompt or an unknown model, are not fixed by dropping them.
+func isStructuredOutputRejected(err error) bool {
+	var apiErr *providers.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	body := strings.ToLower(apiErr.Body)
+	for _, param := range structuredOutputParams {
+		if strings.Contains(body, param) {
+			return true
+		}
+	}
+	return false
+}
+
+// emitFinding publishes a finding to subscribers and the NDJSON stream
+func (po *PipelineOrchestrator) emitFinding(finding *Finding) {
+	po.events <- PipelineEvent{
+		Type:    EventFindingAdded,
+		Finding: finding,
+	}
+
+	po.streamMu.Lock()
+	defer po.streamMu.Unlock()
+	if po.findingStream == nil {
+		return
+	}
+
+	data, err := json.Marshal(finding)
+	if err != nil {
+		return
+	}
+
+	if _, err := po.findingStream.Write(append(data, '\n')); err != nil {
+		// A closed pipe fails every write; report it once and stop
+		po.logger.Error("finding stream failed, no longer writing to it", errorAttrs(err)...)
+		po.findingStream = nil
+	}
+}
+
 // GetPipeline returns the pipeline
 func (po *PipelineOrchestrator) GetPipeline() *Pipeline {
 	return po.pipeline
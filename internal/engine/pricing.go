@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// modelPrice is the USD price per million tokens
+type modelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// modelPrices maps model name prefixes to list prices. Longer prefixes are
+// checked first so "gpt-4o-mini" is not priced as "gpt-4o".
+var modelPrices = map[string]modelPrice{
+	"claude-3-5-haiku":  {Input: 0.80, Output: 4},
+	"claude-3-5-sonnet": {Input: 3, Output: 15},
+	"claude-3.5-sonnet": {Input: 3, Output: 15},
+	"claude-3-opus":     {Input: 15, Output: 75},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.60},
+	"gpt-4o":            {Input: 2.50, Output: 10},
+	"gpt-4.1-mini":      {Input: 0.40, Output: 1.60},
+	"gpt-4.1":           {Input: 2, Output: 8},
+	"gpt-4-turbo":       {Input: 10, Output: 30},
+	"gpt-4":             {Input: 30, Output: 60},
+	"gpt-3.5-turbo":     {Input: 0.50, Output: 1.50},
+	"o1-mini":           {Input: 1.10, Output: 4.40},
+	"o1":                {Input: 15, Output: 60},
+	"o3-mini":           {Input: 1.10, Output: 4.40},
+	"gemini-1.5-flash":  {Input: 0.075, Output: 0.30},
+	"gemini-1.5-pro":    {Input: 1.25, Output: 5},
+	"gemini-2.0-flash":  {Input: 0.10, Output: 0.40},
+}
+
+// EstimateCost returns the approximate USD cost of usage on a model. Unknown
+// and local models cost nothing.
+func EstimateCost(model string, usage Usage) float64 {
+	price, ok := lookupPrice(model)
+	if !ok {
+		return 0
+	}
+	return (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1e6
+}
+
+// lookupPrice finds the price for the longest matching model prefix
+func lookupPrice(model string) (modelPrice, bool) {
+	best := ""
+	for prefix := range modelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return modelPrice{}, false
+	}
+	return modelPrices[best], true
+}
+
+// UsageSummary formats a pass's usage, e.g. "refactor pass: 214k in / 38k out / ~$1.12"
+func (p *Pass) UsageSummary() string {
+	return fmt.Sprintf("%s pass: %s in / %s out / ~$%.2f",
+		p.Name, formatTokens(p.Usage.PromptTokens), formatTokens(p.Usage.CompletionTokens), p.EstimatedCost)
+}
+
+// formatTokens abbreviates token counts (e.g. 214000 -> "214k")
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
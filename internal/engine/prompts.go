@@ -3,18 +3,82 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"sort"
 	"strings"
+	"text/template"
+
+	"github.com/cloudboy-jh/churn-plus/internal/engine/languages"
+	"github.com/cloudboy-jh/churn-plus/internal/engine/providers"
 )
 
+// PromptOptions controls how file content is presented to the model
+type PromptOptions struct {
+	// NumberLines prefixes each line with its line number so the model can
+	// report line_start/line_end reliably
+	NumberLines bool
+
+	// WindowThreshold switches files longer than this many lines to windowed
+	// mode, which sends only the regions of interest (0 disables windowing)
+	WindowThreshold int
+
+	// WindowContext is how many lines surround each region in windowed mode
+	WindowContext int
+
+	// Regions are extra 1-based line ranges of interest for windowed mode.
+	// Functions above HighComplexityThreshold are always included.
+	Regions []LineRange
+
+	// Chunk, when set, sends only these lines of the file. Used to split
+	// files that would overflow the model's context window.
+	Chunk *LineRange
+
+	// Templates are per-pass prompt templates from LoadPromptTemplates
+	Templates map[string]*template.Template
+
+	// PriorFindings are the file's findings from earlier passes, shown to
+	// passes whose mode uses findings
+	PriorFindings []*Finding
+}
+
+// LineRange is an inclusive, 1-based range of lines
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// DefaultPromptOptions numbers lines and sends whole files
+func DefaultPromptOptions() PromptOptions {
+	return PromptOptions{
+		NumberLines:   true,
+		WindowContext: 20,
+	}
+}
+
 // BuildPromptForFile creates an analysis prompt for a file
-func BuildPromptForFile(file *FileInfo, ctx *ProjectContext, pass *Pass) (string, error) {
+func BuildPromptForFile(file *FileInfo, ctx *ProjectContext, pass *Pass, opts PromptOptions) (string, error) {
 	// Read file content
-	content, err := os.ReadFile(file.Path)
+	content, err := ReadFileContent(file.Path)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 
+	// Earlier findings within the lines being sent, which windowed mode
+	// also makes sure to show
+	prior := priorFindingsInRange(opts.PriorFindings, opts.Chunk)
+	if len(prior) > 0 {
+		regions := append([]LineRange{}, opts.Regions...)
+		for _, finding := range prior {
+			regions = append(regions, LineRange{Start: finding.LineStart, End: finding.LineEnd})
+		}
+		opts.Regions = regions
+	}
+	priorSection := formatPriorFindings(prior)
+
+	body, note := formatFileContent(string(content), file.Language, opts)
+	if pass.Mode == PassModeFindings {
+		body, note = "", ""
+	}
+
 	// Build context information
 	contextInfo := fmt.Sprintf(`Project Context:
 - Root: %s
@@ -31,19 +95,65 @@ func BuildPromptForFile(file *FileInfo, ctx *ProjectContext, pass *Pass) (string
 	)
 
 	// Build analysis instructions based on pass type
-	instructions := GetAnalysisInstructions(pass.Name, file.Language)
+	instructions := GetAnalysisInstructions(pass.Name, file.Language, ctx.Frameworks)
+
+	// Call out functions whose control flow is hard to follow
+	if notes := complexityNotes(string(content), file.Language); notes != "" && pass.Mode != PassModeFindings {
+		instructions += "\n\nComplexity:\n" + notes
+	}
+
+	// Passes that see earlier findings are asked only for new ones
+	chained := pass.Mode == PassModeFindings || priorSection != ""
+
+	// A project template for this pass replaces the built-in layout
+	if tmpl, ok := opts.Templates[pass.Name]; ok {
+		return renderPromptTemplate(tmpl, PromptTemplateData{
+			FilePath:     file.Path,
+			Language:     file.Language,
+			Lines:        file.Lines,
+			Content:      body,
+			ContentNote:  strings.TrimSpace(note),
+			Instructions: instructions,
+			Context:      ctx,
+			PassName:     pass.Name,
+
+			PriorFindings: priorSection,
+		}, chained)
+	}
+
+	if pass.Mode == PassModeFindings {
+		return fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", contextInfo, instructions, priorSection, chainedOutputFormat), nil
+	}
+
+	outputFormat := findingsOutputFormat
+	if chained {
+		outputFormat = priorSection + "\n\n" + chainedOutputFormat
+	}
 
 	// Combine into full prompt
 	prompt := fmt.Sprintf(`%s
 
 %s
 
-File Content:
+File Content:%s
 `+"```"+`%s
 %s
 `+"```"+`
 
-Analyze this file and identify issues. Return your findings as a JSON array with this structure:
+%s`,
+		contextInfo,
+		instructions,
+		note,
+		file.Language,
+		body,
+		outputFormat,
+	)
+
+	return prompt, nil
+}
+
+// findingsOutputFormat tells the model how to return findings
+const findingsOutputFormat = `Analyze this file and identify issues. Return your findings as a JSON array with this structure:
 [
   {
     "line_start": <number>,
@@ -56,14 +166,172 @@ Analyze this file and identify issues. Return your findings as a JSON array with
 ]
 
 If no issues are found, return an empty array: []
-`,
-		contextInfo,
-		instructions,
-		file.Language,
-		string(content),
-	)
+`
 
-	return prompt, nil
+// chainedOutputFormat asks a pass that sees earlier findings to add to them
+// rather than repeat them
+const chainedOutputFormat = `Review the previous findings above. Return only findings that are new, or that correct a previous finding (e.g. its lines, severity, or fix); leave out previous findings you agree with. Use the same JSON array structure:
+[
+  {
+    "line_start": <number>,
+    "line_end": <number>,
+    "severity": "low|medium|high|critical",
+    "kind": "unused-import|unreachable-code|security|performance|etc",
+    "message": "Description of the issue",
+    "code": "Optional suggested fix"
+  }
+]
+
+If there is nothing to add, return an empty array: []
+`
+
+// priorFindingsInRange returns the findings that overlap chunk, or all of
+// them when chunk is nil
+func priorFindingsInRange(findings []*Finding, chunk *LineRange) []*Finding {
+	if chunk == nil {
+		return findings
+	}
+	inRange := make([]*Finding, 0, len(findings))
+	for _, finding := range findings {
+		if finding.LineStart <= chunk.End && finding.LineEnd >= chunk.Start {
+			inRange = append(inRange, finding)
+		}
+	}
+	return inRange
+}
+
+// formatPriorFindings lists earlier passes' findings for a prompt, or
+// returns "" when there are none
+func formatPriorFindings(findings []*Finding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Previous Findings:\n")
+	for _, finding := range findings {
+		sb.WriteString(fmt.Sprintf("- lines %d-%d [%s] %s (%s pass): %s\n",
+			finding.LineStart, finding.LineEnd, finding.Severity, finding.Kind, finding.Pass, finding.Message))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// findingsSchema is the structured-output form of findingsOutputFormat. APIs
+// want an object at the top level, so the array is wrapped under "findings".
+var findingsSchema = &providers.ResponseSchema{
+	Name:        "report_findings",
+	Description: "Report the issues found in the file",
+	Schema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"findings": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"line_start": map[string]interface{}{"type": "integer"},
+						"line_end":   map[string]interface{}{"type": "integer"},
+						"severity": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"low", "medium", "high", "critical"},
+						},
+						"kind":    map[string]interface{}{"type": "string"},
+						"message": map[string]interface{}{"type": "string"},
+						"code":    map[string]interface{}{"type": "string"},
+					},
+					"required": []string{"line_start", "line_end", "severity", "kind", "message"},
+				},
+			},
+		},
+		"required": []string{"findings"},
+	},
+}
+
+// formatFileContent renders file content for a prompt according to opts,
+// returning the body and a note explaining its format to the model
+func formatFileContent(content, language string, opts PromptOptions) (string, string) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	if opts.Chunk != nil {
+		start := max(opts.Chunk.Start, 1)
+		end := min(opts.Chunk.End, len(lines))
+		if !opts.NumberLines {
+			return strings.Join(lines[start-1:end], "\n"),
+				fmt.Sprintf("\n(Only lines %d-%d of %d are shown; number lines from the first line shown.)", start, end, len(lines))
+		}
+		return renderWindows(lines[:end], []LineRange{{Start: start, End: end}}),
+			fmt.Sprintf("\n(Only lines %d-%d of %d are shown. Each line is prefixed with its line number and a \"|\"; use these numbers for line_start/line_end and do not include the prefix in suggested code.)", start, end, len(lines))
+	}
+
+	if opts.WindowThreshold > 0 && len(lines) > opts.WindowThreshold {
+		if windows := promptWindows(content, language, len(lines), opts); len(windows) > 0 {
+			return renderWindows(lines, windows),
+				"\n(Large file: only regions of interest are shown. Each line is prefixed with its line number and a \"|\"; use these numbers for line_start/line_end and do not include the prefix in suggested code.)"
+		}
+	}
+
+	if !opts.NumberLines {
+		return content, ""
+	}
+
+	return renderWindows(lines, []LineRange{{Start: 1, End: len(lines)}}),
+		"\n(Each line is prefixed with its line number and a \"|\"; use these numbers for line_start/line_end and do not include the prefix in suggested code.)"
+}
+
+// promptWindows returns the merged, context-padded ranges to send for a large file
+func promptWindows(content, language string, lineCount int, opts PromptOptions) []LineRange {
+	regions := append([]LineRange(nil), opts.Regions...)
+	for _, fn := range analyzeFunctions(content, language) {
+		if fn.Complexity >= HighComplexityThreshold {
+			regions = append(regions, LineRange{Start: fn.StartLine, End: fn.EndLine})
+		}
+	}
+	if len(regions) == 0 {
+		return nil
+	}
+
+	for i := range regions {
+		regions[i].Start = max(regions[i].Start-opts.WindowContext, 1)
+		regions[i].End = min(regions[i].End+opts.WindowContext, lineCount)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Start < regions[j].Start
+	})
+
+	merged := []LineRange{regions[0]}
+	for _, r := range regions[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End+1 {
+			last.End = max(last.End, r.End)
+		} else {
+			merged = append(merged, r)
+		}
+	}
+
+	return merged
+}
+
+// renderWindows writes the given line ranges with line-number prefixes,
+// marking omitted lines between them
+func renderWindows(lines []string, windows []LineRange) string {
+	width := len(fmt.Sprint(len(lines)))
+
+	var sb strings.Builder
+	next := 1
+	for _, w := range windows {
+		if w.Start > next {
+			sb.WriteString(fmt.Sprintf("%*s | ... (lines %d-%d omitted)\n", width, "", next, w.Start-1))
+		}
+		for n := w.Start; n <= w.End && n <= len(lines); n++ {
+			sb.WriteString(fmt.Sprintf("%*d | %s\n", width, n, lines[n-1]))
+		}
+		next = w.End + 1
+	}
+	if next <= len(lines) {
+		sb.WriteString(fmt.Sprintf("%*s | ... (lines %d-%d omitted)\n", width, "", next, len(lines)))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
 }
 
 // GetSystemPromptForPass returns the system prompt for a pass
@@ -75,6 +343,10 @@ func GetSystemPromptForPass(pass *Pass) string {
 		return "You are an expert software architect analyzing code for structural improvements, design patterns, and refactoring opportunities. Focus on maintainability and best practices."
 	case "local-refinement":
 		return "You are a code reviewer refining previous analysis results. Focus on validating findings and ensuring recommendations are practical."
+	case "documentation":
+		return "You are a technical writer reviewing code documentation. Flag public APIs that are undocumented or whose documentation no longer matches the code. Ignore private helpers unless they are non-obvious."
+	case "naming":
+		return "You are a code reviewer focused on identifier naming. Flag names that break the language's conventions or mislead the reader. Do not flag names that are idiomatic for their scope."
 	case "summary":
 		return "You are synthesizing analysis results to ensure consistency and provide an overall assessment. Identify any conflicting recommendations."
 	default:
@@ -82,8 +354,9 @@ func GetSystemPromptForPass(pass *Pass) string {
 	}
 }
 
-// GetAnalysisInstructions returns language and pass-specific instructions
-func GetAnalysisInstructions(passName, language string) string {
+// GetAnalysisInstructions returns language and pass-specific instructions.
+// frameworks are the project's detected frameworks, which add their own rules.
+func GetAnalysisInstructions(passName, language string, frameworks []string) string {
 	var instructions strings.Builder
 
 	instructions.WriteString(fmt.Sprintf("Pass: %s\n\n", passName))
@@ -115,67 +388,142 @@ func GetAnalysisInstructions(passName, language string) string {
 		instructions.WriteString("- Overall code quality assessment\n")
 		instructions.WriteString("- Consistency across findings\n")
 		instructions.WriteString("- Priority ordering of issues\n")
+
+	case "documentation":
+		instructions.WriteString("Focus on:\n")
+		instructions.WriteString("- Exported functions and types with no doc comments (Go)\n")
+		instructions.WriteString("- Missing JSDoc on public APIs (JavaScript/TypeScript)\n")
+		instructions.WriteString("- Parameter or return descriptions that no longer match the signature\n")
+		instructions.WriteString("- Missing README sections (installation, usage, configuration)\n")
+		instructions.WriteString("- Documentation examples that would not compile or run\n")
+
+	case "naming":
+		instructions.WriteString("Focus on:\n")
+		instructions.WriteString("- Mixing camelCase and snake_case within a file\n")
+		instructions.WriteString("- Single-letter variables outside of short-scope loops\n")
+		instructions.WriteString("- Misleading boolean names (e.g. negated names like isDisabled vs disabled)\n")
+		instructions.WriteString("- Overly abbreviated names that hide intent\n")
+		instructions.WriteString(namingConventions(language))
 	}
 
-	// Add language-specific guidance
-	instructions.WriteString(fmt.Sprintf("\nLanguage-specific considerations for %s:\n", language))
+	// Add language and framework guidance from the shared rule sets
+	if rules := languageRules(language); len(rules) > 0 {
+		instructions.WriteString(fmt.Sprintf("\nLanguage-specific considerations for %s:\n", language))
+		for _, rule := range rules {
+			instructions.WriteString("- " + rule + "\n")
+		}
+	}
 
-	switch language {
-	case "typescript", "javascript":
-		instructions.WriteString("- React hooks must be called in the same order every render\n")
-		instructions.WriteString("- Async/await patterns and promise handling\n")
-		instructions.WriteString("- Type safety (TypeScript)\n")
-		instructions.WriteString("- Modern ES6+ patterns\n")
+	if usesReact(language, frameworks) {
+		instructions.WriteString("\nReact considerations:\n")
+		for _, rule := range languages.ReactRules() {
+			instructions.WriteString("- " + rule + "\n")
+		}
+	}
 
-	case "python":
-		instructions.WriteString("- Type hints and PEP 8 compliance\n")
-		instructions.WriteString("- Pythonic idioms (list comprehensions, generators)\n")
-		instructions.WriteString("- Async/await patterns\n")
-		instructions.WriteString("- Exception handling\n")
+	return instructions.String()
+}
 
+// namingConventions returns the expected naming style for a language
+func namingConventions(language string) string {
+	switch language {
 	case "go":
-		instructions.WriteString("- Error handling (check all errors)\n")
-		instructions.WriteString("- Goroutine and channel patterns\n")
-		instructions.WriteString("- Go idioms and conventions\n")
-		instructions.WriteString("- Use of standard library\n")
+		return "- Go: MixedCaps for exported names, mixedCaps for unexported, initialisms kept upper case (ID, URL, HTTP)\n"
+	case "python":
+		return "- Python: snake_case for functions and variables, PascalCase for classes, UPPER_CASE for constants\n"
+	case "typescript", "javascript":
+		return "- JS/TS: camelCase for variables and functions, PascalCase for classes, types and components\n"
+	case "rust":
+		return "- Rust: snake_case for functions and variables, PascalCase for types and traits, SCREAMING_SNAKE_CASE for constants\n"
+	case "java", "kotlin", "csharp":
+		return "- camelCase for methods and fields, PascalCase for types\n"
+	case "ruby":
+		return "- Ruby: snake_case for methods and variables, PascalCase for classes and modules, predicate methods end in ?\n"
+	default:
+		return "- Follow the dominant convention already used in the file\n"
+	}
+}
 
+// languageRules returns the rule set for a language, if there is one
+func languageRules(language string) []string {
+	switch language {
+	case "go":
+		return languages.GoRules()
+	case "python":
+		return languages.PythonRules()
 	case "rust":
-		instructions.WriteString("- Ownership and borrowing\n")
-		instructions.WriteString("- Error handling (Result/Option)\n")
-		instructions.WriteString("- Memory safety\n")
-		instructions.WriteString("- Lifetime annotations\n")
+		return languages.RustRules()
+	case "typescript":
+		return languages.TypeScriptRules()
+	case "javascript":
+		return languages.JavaScriptRules()
+	default:
+		return nil
 	}
+}
 
-	return instructions.String()
+// usesReact reports whether React rules apply to a file in this language
+func usesReact(language string, frameworks []string) bool {
+	if language != "typescript" && language != "javascript" {
+		return false
+	}
+	for _, framework := range frameworks {
+		if strings.EqualFold(framework, "react") || strings.EqualFold(framework, "next.js") {
+			return true
+		}
+	}
+	return false
 }
 
 // ParseFindingsFromResponse extracts findings from LLM response
 func ParseFindingsFromResponse(filePath, response string) []*Finding {
+	findings, _ := parseFindings(filePath, response, false)
+	return findings
+}
+
+// rawFinding is a finding as the model returns it
+type rawFinding struct {
+	LineStart int    `json:"line_start"`
+	LineEnd   int    `json:"line_end"`
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+	Code      string `json:"code"`
+}
+
+// parseFindings extracts findings from an LLM response, tolerating fenced
+// or surrounding prose, objects wrapping the array, and trailing commas. A
+// structured response is decoded as is, falling back to that salvage only
+// if it isn't valid. An error describes a response that could not be parsed
+// at all.
+func parseFindings(filePath, response string, structured bool) ([]*Finding, error) {
 	findings := make([]*Finding, 0)
 
-	// Try to extract JSON array from response
-	// LLMs sometimes wrap JSON in markdown code blocks
-	jsonStr := extractJSON(response)
-	if jsonStr == "" {
-		return findings
+	var rawFindings []rawFinding
+	var parseErr error
+	if structured {
+		rawFindings, parseErr = decodeRawFindings(strings.TrimSpace(response))
 	}
-
-	var rawFindings []struct {
-		LineStart int    `json:"line_start"`
-		LineEnd   int    `json:"line_end"`
-		Severity  string `json:"severity"`
-		Kind      string `json:"kind"`
-		Message   string `json:"message"`
-		Code      string `json:"code"`
+	if !structured || parseErr != nil {
+		rawFindings, parseErr = salvageRawFindings(response)
+		if parseErr != nil {
+			return findings, parseErr
+		}
 	}
 
-	if err := json.Unmarshal([]byte(jsonStr), &rawFindings); err != nil {
-		// Failed to parse, return empty
-		return findings
+	// Line numbers are checked against the file when it can be read
+	lineCount := -1
+	if content, err := ReadFileContent(filePath); err == nil {
+		lineCount = len(splitLines(string(content)))
 	}
 
 	// Convert to Finding structs
 	for _, rf := range rawFindings {
+		lineStart, lineEnd, ok := clampLines(rf.LineStart, rf.LineEnd, lineCount)
+		if !ok {
+			continue
+		}
+
 		severity := SeverityMedium
 		switch strings.ToLower(rf.Severity) {
 		case "low":
@@ -190,8 +538,8 @@ func ParseFindingsFromResponse(filePath, response string) []*Finding {
 
 		findings = append(findings, &Finding{
 			File:      filePath,
-			LineStart: rf.LineStart,
-			LineEnd:   rf.LineEnd,
+			LineStart: lineStart,
+			LineEnd:   lineEnd,
 			Severity:  severity,
 			Kind:      rf.Kind,
 			Message:   rf.Message,
@@ -199,31 +547,207 @@ func ParseFindingsFromResponse(filePath, response string) []*Finding {
 		})
 	}
 
-	return findings
+	return findings, nil
 }
 
-// extractJSON attempts to extract JSON from text (handles markdown code blocks)
-func extractJSON(text string) string {
-	// Try to find JSON array in markdown code block
-	if strings.Contains(text, "```json") {
-		start := strings.Index(text, "```json")
-		if start != -1 {
-			start += 7 // Skip "```json"
-			end := strings.Index(text[start:], "```")
-			if end != -1 {
-				return strings.TrimSpace(text[start : start+end])
+// salvageRawFindings finds and decodes the findings JSON in free-form text
+func salvageRawFindings(response string) ([]rawFinding, error) {
+	candidates := jsonCandidates(response)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no JSON in response: %s", snippet(response))
+	}
+
+	var parseErr error
+	for _, candidate := range candidates {
+		rawFindings, err := decodeRawFindings(stripTrailingCommas(candidate))
+		if err == nil {
+			return rawFindings, nil
+		}
+		parseErr = err
+	}
+	return nil, fmt.Errorf("failed to parse findings (%v): %s", parseErr, snippet(response))
+}
+
+// clampLines normalizes a finding's line range: inverted ranges are swapped,
+// a missing end becomes the start, and the range is clamped to [1, lineCount].
+// It reports false for negative ranges and ranges that start past the end of
+// the file. A negative lineCount means the length is unknown.
+func clampLines(start, end, lineCount int) (int, int, bool) {
+	if end == 0 {
+		end = start
+	}
+	if end < start {
+		start, end = end, start
+	}
+
+	if end < 0 || (lineCount >= 0 && start > lineCount) {
+		return 0, 0, false
+	}
+
+	start = max(start, 1)
+	end = max(end, start)
+	if lineCount > 0 {
+		end = min(end, lineCount)
+	}
+	return start, end, true
+}
+
+// decodeRawFindings accepts a findings array, an object wrapping one under a
+// "findings", "issues" or "results" key, or a single finding object
+func decodeRawFindings(data string) ([]rawFinding, error) {
+	var list []rawFinding
+	if strings.HasPrefix(data, "[") {
+		if err := json.Unmarshal([]byte(data), &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &wrapper); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"findings", "issues", "results"} {
+		if inner, ok := wrapper[key]; ok {
+			if err := json.Unmarshal(inner, &list); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+	}
+
+	if _, ok := wrapper["line_start"]; ok {
+		var single rawFinding
+		if err := json.Unmarshal([]byte(data), &single); err != nil {
+			return nil, err
+		}
+		return []rawFinding{single}, nil
+	}
+
+	return nil, fmt.Errorf("object has no findings array")
+}
+
+// jsonCandidates returns the likely JSON payloads in a response, best first:
+// fenced code blocks, then each balanced array or object in the text
+func jsonCandidates(text string) []string {
+	var candidates []string
+
+	rest := text
+	for {
+		start := strings.Index(rest, "```")
+		if start == -1 {
+			break
+		}
+		body := rest[start+3:]
+		// Skip the info string, e.g. "json"
+		if nl := strings.Index(body, "\n"); nl != -1 {
+			body = body[nl+1:]
+		}
+		end := strings.Index(body, "```")
+		if end == -1 {
+			break
+		}
+		if block := strings.TrimSpace(body[:end]); strings.HasPrefix(block, "[") || strings.HasPrefix(block, "{") {
+			candidates = append(candidates, block)
+		}
+		rest = body[end+3:]
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '[' && text[i] != '{' {
+			continue
+		}
+		if end := balancedEnd(text, i); end != -1 {
+			candidates = append(candidates, text[i:end+1])
+			i = end
+		}
+	}
+
+	return candidates
+}
+
+// balancedEnd returns the index closing the array or object that opens at
+// start, skipping brackets inside strings, or -1 if it never closes
+func balancedEnd(text string, start int) int {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+			if depth == 0 {
+				return i
 			}
 		}
 	}
 
-	// Try to find JSON array directly
-	if strings.Contains(text, "[") {
-		start := strings.Index(text, "[")
-		end := strings.LastIndex(text, "]")
-		if start != -1 && end != -1 && end > start {
-			return strings.TrimSpace(text[start : end+1])
+	return -1
+}
+
+// stripTrailingCommas removes commas directly before a closing bracket,
+// leaving string contents untouched
+func stripTrailingCommas(data string) string {
+	var sb strings.Builder
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			sb.WriteByte(c)
+			continue
 		}
+
+		if c == '"' {
+			inString = true
+		}
+		if c == ',' {
+			j := i + 1
+			for j < len(data) && strings.ContainsRune(" \t\r\n", rune(data[j])) {
+				j++
+			}
+			if j < len(data) && (data[j] == ']' || data[j] == '}') {
+				continue
+			}
+		}
+		sb.WriteByte(c)
 	}
 
-	return ""
+	return sb.String()
+}
+
+// snippet shortens a response for warnings
+func snippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) > 200 {
+		return text[:200] + "..."
+	}
+	return text
 }
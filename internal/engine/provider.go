@@ -7,6 +7,7 @@ import (
 // Re-export provider types to avoid import cycles
 type ModelProvider = providers.ModelProvider
 type RequestOptions = providers.RequestOptions
+type Usage = providers.Usage
 
 // DefaultRequestOptions returns sensible defaults
 func DefaultRequestOptions() RequestOptions {
@@ -6,24 +6,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"time"
+	"strings"
 )
 
 // AnthropicProvider implements the ModelProvider interface for Anthropic Claude
 type AnthropicProvider struct {
-	apiKey string
-	client *http.Client
+	apiKey  string
+	baseURL string
+	client  *http.Client
 }
 
-// NewAnthropicProvider creates a new Anthropic provider
-func NewAnthropicProvider(apiKey string) *AnthropicProvider {
+// DefaultAnthropicBaseURL is the Anthropic API used when no base URL is configured
+const DefaultAnthropicBaseURL = "https://api.anthropic.com"
+
+// NewAnthropicProvider creates a new Anthropic provider. An empty baseURL
+// uses DefaultAnthropicBaseURL.
+func NewAnthropicProvider(apiKey, baseURL string) *AnthropicProvider {
+	if baseURL == "" {
+		baseURL = DefaultAnthropicBaseURL
+	}
+
 	return &AnthropicProvider{
-		apiKey: apiKey,
-		client: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  newDefaultHTTPClient(),
 	}
 }
 
@@ -32,6 +39,17 @@ func (p *AnthropicProvider) Name() string {
 	return "anthropic"
 }
 
+// SupportsStructuredOutput reports true: requests with a ResponseSchema force
+// a tool call whose input is the response
+func (p *AnthropicProvider) SupportsStructuredOutput() bool {
+	return true
+}
+
+// SetHTTPClient replaces the client used for API requests
+func (p *AnthropicProvider) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
 // ListModels returns available Anthropic models
 func (p *AnthropicProvider) ListModels(ctx context.Context) ([]string, error) {
 	// Anthropic doesn't have a list endpoint, return known models
@@ -44,8 +62,30 @@ func (p *AnthropicProvider) ListModels(ctx context.Context) ([]string, error) {
 	}, nil
 }
 
+// Ping checks the API key against the models endpoint
+func (p *AnthropicProvider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/v1/models?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	return doPing(p.client, "anthropic", req)
+}
+
 // Request sends a non-streaming request
 func (p *AnthropicProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage sends a non-streaming request and reports token usage
+func (p *AnthropicProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	ctx, cancel := withRequestTimeout(ctx, opts)
+	defer cancel()
+
 	messages := []map[string]string{
 		{"role": "user", "content": prompt},
 	}
@@ -61,14 +101,25 @@ func (p *AnthropicProvider) Request(ctx context.Context, prompt string, opts Req
 		reqBody["system"] = opts.SystemPrompt
 	}
 
+	// Forcing a tool call makes the model return the tool input as JSON
+	// that matches the schema
+	if schema := opts.ResponseSchema; schema != nil {
+		reqBody["tools"] = []map[string]interface{}{{
+			"name":         schema.Name,
+			"description":  schema.Description,
+			"input_schema": schema.Schema,
+		}}
+		reqBody["tool_choice"] = map[string]string{"type": "tool", "name": schema.Name}
+	}
+
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/v1/messages", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -77,31 +128,43 @@ func (p *AnthropicProvider) Request(ctx context.Context, prompt string, opts Req
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("anthropic API error (status %d): %s", resp.StatusCode, string(body))
+		return "", Usage{}, newAPIError("anthropic", resp)
 	}
 
 	var result struct {
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Input json.RawMessage `json:"input"` // Set on tool_use blocks
 		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(result.Content) == 0 {
-		return "", fmt.Errorf("empty response from Anthropic")
+		return "", Usage{}, fmt.Errorf("empty response from Anthropic")
 	}
 
-	return result.Content[0].Text, nil
+	usage := Usage{PromptTokens: result.Usage.InputTokens, CompletionTokens: result.Usage.OutputTokens}
+	if opts.ResponseSchema != nil {
+		for _, block := range result.Content {
+			if block.Type == "tool_use" {
+				return string(block.Input), usage, nil
+			}
+		}
+	}
+	return result.Content[0].Text, usage, nil
 }
 
 // Stream sends a streaming request
@@ -113,6 +176,9 @@ func (p *AnthropicProvider) Stream(ctx context.Context, prompt string, opts Requ
 		defer close(tokenChan)
 		defer close(errChan)
 
+		ctx, cancel := withRequestTimeout(ctx, opts)
+		defer cancel()
+
 		messages := []map[string]string{
 			{"role": "user", "content": prompt},
 		}
@@ -135,7 +201,7 @@ func (p *AnthropicProvider) Stream(ctx context.Context, prompt string, opts Requ
 			return
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/v1/messages", bytes.NewBuffer(jsonData))
 		if err != nil {
 			errChan <- fmt.Errorf("failed to create request: %w", err)
 			return
@@ -153,8 +219,7 @@ func (p *AnthropicProvider) Stream(ctx context.Context, prompt string, opts Requ
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			errChan <- fmt.Errorf("anthropic API error (status %d): %s", resp.StatusCode, string(body))
+			errChan <- newAPIError("anthropic", resp)
 			return
 		}
 
@@ -0,0 +1,251 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AzureOpenAIProvider implements the ModelProvider interface for an Azure
+// OpenAI deployment. Azure routes by deployment rather than model name, so
+// the model in RequestOptions is ignored.
+type AzureOpenAIProvider struct {
+	apiKey     string
+	endpoint   string
+	deployment string
+	apiVersion string
+	client     *http.Client
+}
+
+// NewAzureOpenAIProvider creates a new Azure OpenAI provider for the given
+// resource endpoint (e.g. "https://my-resource.openai.azure.com"),
+// deployment name, and REST API version
+func NewAzureOpenAIProvider(apiKey, endpoint, deployment, apiVersion string) *AzureOpenAIProvider {
+	return &AzureOpenAIProvider{
+		apiKey:     apiKey,
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		deployment: deployment,
+		apiVersion: apiVersion,
+		client:     newDefaultHTTPClient(),
+	}
+}
+
+// Name returns the provider name
+func (p *AzureOpenAIProvider) Name() string {
+	return "azure"
+}
+
+// SupportsStructuredOutput reports true: requests with a ResponseSchema use
+// JSON mode
+func (p *AzureOpenAIProvider) SupportsStructuredOutput() bool {
+	return true
+}
+
+// SetHTTPClient replaces the client used for API requests
+func (p *AzureOpenAIProvider) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
+// ListModels returns the configured deployment, which is the only model an
+// Azure OpenAI provider can address
+func (p *AzureOpenAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	if p.deployment == "" {
+		return []string{}, nil
+	}
+	return []string{p.deployment}, nil
+}
+
+// chatCompletionsURL returns the deployment's chat completions endpoint
+func (p *AzureOpenAIProvider) chatCompletionsURL() string {
+	return fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		p.endpoint, url.PathEscape(p.deployment), url.QueryEscape(p.apiVersion))
+}
+
+// newRequest builds a chat completions request for the prompt
+func (p *AzureOpenAIProvider) newRequest(ctx context.Context, prompt string, opts RequestOptions, stream bool) (*http.Request, error) {
+	systemPrompt := opts.SystemPrompt
+	if opts.ResponseSchema != nil {
+		// JSON mode takes no schema, so describe it in the instructions
+		systemPrompt = strings.TrimSpace(systemPrompt + "\n\n" + schemaInstruction(opts.ResponseSchema))
+	}
+
+	messages := []map[string]string{}
+
+	if systemPrompt != "" {
+		messages = append(messages, map[string]string{
+			"role": "system", "content": systemPrompt,
+		})
+	}
+
+	messages = append(messages, map[string]string{
+		"role": "user", "content": prompt,
+	})
+
+	reqBody := map[string]interface{}{
+		"messages":    messages,
+		"max_tokens":  opts.MaxTokens,
+		"temperature": opts.Temperature,
+	}
+	if stream {
+		reqBody["stream"] = true
+	}
+	if opts.ResponseSchema != nil {
+		reqBody["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.chatCompletionsURL(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api-key", p.apiKey)
+
+	return req, nil
+}
+
+// Ping sends a one-token completion to the deployment, since Azure has no
+// cheaper data-plane check for a key and deployment together
+func (p *AzureOpenAIProvider) Ping(ctx context.Context) error {
+	opts := DefaultRequestOptions()
+	opts.MaxTokens = 1
+
+	req, err := p.newRequest(ctx, "ping", opts, false)
+	if err != nil {
+		return err
+	}
+
+	return doPing(p.client, "azure", req)
+}
+
+// Request sends a non-streaming request
+func (p *AzureOpenAIProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage sends a non-streaming request and reports token usage
+func (p *AzureOpenAIProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	ctx, cancel := withRequestTimeout(ctx, opts)
+	defer cancel()
+
+	req, err := p.newRequest(ctx, prompt, opts, false)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", Usage{}, newAPIError("azure", resp)
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", Usage{}, fmt.Errorf("empty response from Azure OpenAI")
+	}
+
+	usage := Usage{PromptTokens: result.Usage.PromptTokens, CompletionTokens: result.Usage.CompletionTokens}
+	return result.Choices[0].Message.Content, usage, nil
+}
+
+// Stream sends a streaming request
+func (p *AzureOpenAIProvider) Stream(ctx context.Context, prompt string, opts RequestOptions) (<-chan string, <-chan error) {
+	tokenChan := make(chan string, 100)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		ctx, cancel := withRequestTimeout(ctx, opts)
+		defer cancel()
+
+		req, err := p.newRequest(ctx, prompt, opts, true)
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			errChan <- fmt.Errorf("failed to send request: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			errChan <- newAPIError("azure", resp)
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				break
+			}
+
+			// Azure sends content-filter chunks with no choices; skip them
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}
+
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				select {
+				case tokenChan <- chunk.Choices[0].Delta.Content:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errChan <- fmt.Errorf("stream reading error: %w", err)
+		}
+	}()
+
+	return tokenChan, errChan
+}
@@ -0,0 +1,341 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AuthStyle controls how GenericOpenAIProvider sends its API key
+type AuthStyle string
+
+const (
+	AuthBearer AuthStyle = "bearer" // Authorization: Bearer <key>
+	AuthHeader AuthStyle = "header" // <AuthHeader>: <key>
+	AuthNone   AuthStyle = "none"   // No credentials
+)
+
+// GenericOpenAIConfig describes an OpenAI-compatible API such as Mistral,
+// Groq, OpenRouter, or Together
+type GenericOpenAIConfig struct {
+	Name       string    // Provider name reported by Name(), e.g. "mistral"
+	BaseURL    string    // e.g. "https://api.mistral.ai/v1"
+	APIKey     string    // Sent according to AuthStyle
+	AuthStyle  AuthStyle // Default: AuthBearer
+	AuthHeader string    // Header name for AuthHeader style, default "api-key"
+
+	// FallbackModels is returned when the /models endpoint can't be reached
+	FallbackModels []string
+}
+
+// GenericOpenAIProvider implements the ModelProvider interface for any API
+// that speaks the OpenAI chat completions protocol
+type GenericOpenAIProvider struct {
+	cfg    GenericOpenAIConfig
+	client *http.Client
+}
+
+// NewGenericOpenAIProvider creates a provider for an OpenAI-compatible API
+func NewGenericOpenAIProvider(cfg GenericOpenAIConfig) *GenericOpenAIProvider {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Name == "" {
+		cfg.Name = "openai-compatible"
+	}
+	if cfg.AuthStyle == "" {
+		cfg.AuthStyle = AuthBearer
+	}
+	if cfg.AuthHeader == "" {
+		cfg.AuthHeader = "api-key"
+	}
+
+	return &GenericOpenAIProvider{
+		cfg:    cfg,
+		client: newDefaultHTTPClient(),
+	}
+}
+
+// Name returns the provider name
+func (p *GenericOpenAIProvider) Name() string {
+	return p.cfg.Name
+}
+
+// SetHTTPClient replaces the client used for API requests
+func (p *GenericOpenAIProvider) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
+// genericModelCache keeps listed models per base URL and API key for the
+// process lifetime
+var genericModelCache = struct {
+	sync.Mutex
+	models map[string][]string
+}{models: make(map[string][]string)}
+
+// ListModels returns the models listed by the API's /models endpoint,
+// falling back to the configured list when the request fails
+func (p *GenericOpenAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	cacheKey := p.cfg.BaseURL + "\x00" + p.cfg.APIKey
+	genericModelCache.Lock()
+	models, ok := genericModelCache.models[cacheKey]
+	genericModelCache.Unlock()
+	if ok {
+		return models, nil
+	}
+
+	// Fetch unlocked so one slow server doesn't block listing others
+	models, err := p.fetchModels(ctx)
+	if err != nil || len(models) == 0 {
+		if len(p.cfg.FallbackModels) > 0 {
+			return p.cfg.FallbackModels, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("no models listed by %s", p.cfg.Name)
+		}
+		return nil, err
+	}
+
+	genericModelCache.Lock()
+	genericModelCache.models[cacheKey] = models
+	genericModelCache.Unlock()
+	return models, nil
+}
+
+// fetchModels lists models from the /models endpoint
+func (p *GenericOpenAIProvider) fetchModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.cfg.BaseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.setAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(p.cfg.Name, resp)
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	models := make([]string, 0, len(result.Data))
+	for _, model := range result.Data {
+		models = append(models, model.ID)
+	}
+	sort.Strings(models)
+
+	return models, nil
+}
+
+// Ping checks the API key against the models endpoint. Servers without a
+// models endpoint answer 404, which still proves they are reachable.
+func (p *GenericOpenAIProvider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.cfg.BaseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.setAuth(req)
+
+	err = doPing(p.client, p.cfg.Name, req)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// setAuth adds the API key in the configured style
+func (p *GenericOpenAIProvider) setAuth(req *http.Request) {
+	if p.cfg.APIKey == "" {
+		return
+	}
+
+	switch p.cfg.AuthStyle {
+	case AuthHeader:
+		req.Header.Set(p.cfg.AuthHeader, p.cfg.APIKey)
+	case AuthNone:
+	default:
+		req.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	}
+}
+
+// newRequest builds a chat completions request for the prompt
+func (p *GenericOpenAIProvider) newRequest(ctx context.Context, prompt string, opts RequestOptions, stream bool) (*http.Request, error) {
+	messages := []map[string]string{}
+
+	if opts.SystemPrompt != "" {
+		messages = append(messages, map[string]string{
+			"role": "system", "content": opts.SystemPrompt,
+		})
+	}
+
+	messages = append(messages, map[string]string{
+		"role": "user", "content": prompt,
+	})
+
+	reqBody := map[string]interface{}{
+		"model":       opts.Model,
+		"messages":    messages,
+		"max_tokens":  opts.MaxTokens,
+		"temperature": opts.Temperature,
+	}
+	if stream {
+		reqBody["stream"] = true
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.cfg.BaseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	p.setAuth(req)
+
+	return req, nil
+}
+
+// Request sends a non-streaming request
+func (p *GenericOpenAIProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage sends a non-streaming request and reports token usage
+func (p *GenericOpenAIProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	ctx, cancel := withRequestTimeout(ctx, opts)
+	defer cancel()
+
+	req, err := p.newRequest(ctx, prompt, opts, false)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", Usage{}, newAPIError(p.cfg.Name, resp)
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", Usage{}, fmt.Errorf("empty response from %s", p.cfg.Name)
+	}
+
+	usage := Usage{PromptTokens: result.Usage.PromptTokens, CompletionTokens: result.Usage.CompletionTokens}
+	return result.Choices[0].Message.Content, usage, nil
+}
+
+// Stream sends a streaming request
+func (p *GenericOpenAIProvider) Stream(ctx context.Context, prompt string, opts RequestOptions) (<-chan string, <-chan error) {
+	tokenChan := make(chan string, 100)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		ctx, cancel := withRequestTimeout(ctx, opts)
+		defer cancel()
+
+		req, err := p.newRequest(ctx, prompt, opts, true)
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			errChan <- fmt.Errorf("failed to send request: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			errChan <- newAPIError(p.cfg.Name, resp)
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			// Some servers omit the space after "data:"
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}
+
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+
+			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+				select {
+				case tokenChan <- chunk.Choices[0].Delta.Content:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errChan <- fmt.Errorf("stream reading error: %w", err)
+		}
+	}()
+
+	return tokenChan, errChan
+}
@@ -1,28 +1,37 @@
 package providers
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+	"strings"
 )
 
 // GoogleProvider implements the ModelProvider interface for Google Gemini
 type GoogleProvider struct {
-	apiKey string
-	client *http.Client
+	apiKey  string
+	baseURL string
+	client  *http.Client
 }
 
-// NewGoogleProvider creates a new Google provider
-func NewGoogleProvider(apiKey string) *GoogleProvider {
+// DefaultGoogleBaseURL is the Gemini API used when no base URL is configured
+const DefaultGoogleBaseURL = "https://generativelanguage.googleapis.com"
+
+// NewGoogleProvider creates a new Google provider. An empty baseURL uses
+// DefaultGoogleBaseURL.
+func NewGoogleProvider(apiKey, baseURL string) *GoogleProvider {
+	if baseURL == "" {
+		baseURL = DefaultGoogleBaseURL
+	}
+
 	return &GoogleProvider{
-		apiKey: apiKey,
-		client: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  newDefaultHTTPClient(),
 	}
 }
 
@@ -31,6 +40,11 @@ func (p *GoogleProvider) Name() string {
 	return "google"
 }
 
+// SetHTTPClient replaces the client used for API requests
+func (p *GoogleProvider) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
 // ListModels returns available Google models
 func (p *GoogleProvider) ListModels(ctx context.Context) ([]string, error) {
 	// Return known Gemini models
@@ -42,8 +56,35 @@ func (p *GoogleProvider) ListModels(ctx context.Context) ([]string, error) {
 	}, nil
 }
 
+// setHeaders authenticates a request. The key goes in a header rather than
+// the URL so it never shows up in error messages or logs.
+func (p *GoogleProvider) setHeaders(req *http.Request) {
+	req.Header.Set("x-goog-api-key", p.apiKey)
+}
+
+// Ping checks the API key against the models endpoint
+func (p *GoogleProvider) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/v1beta/models?pageSize=1", p.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	p.setHeaders(req)
+
+	return doPing(p.client, "google", req)
+}
+
 // Request sends a non-streaming request
 func (p *GoogleProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage sends a non-streaming request and reports token usage
+func (p *GoogleProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	ctx, cancel := withRequestTimeout(ctx, opts)
+	defer cancel()
+
 	contents := []map[string]interface{}{
 		{
 			"parts": []map[string]string{
@@ -70,26 +111,26 @@ func (p *GoogleProvider) Request(ctx context.Context, prompt string, opts Reques
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", opts.Model, p.apiKey)
+	url := fmt.Sprintf("%s/v1beta/models/%s:generateContent", p.baseURL, opts.Model)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	p.setHeaders(req)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("google API error (status %d): %s", resp.StatusCode, string(body))
+		return "", Usage{}, newAPIError("google", resp)
 	}
 
 	var result struct {
@@ -100,17 +141,25 @@ func (p *GoogleProvider) Request(ctx context.Context, prompt string, opts Reques
 				} `json:"parts"`
 			} `json:"content"`
 		} `json:"candidates"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from Google")
+		return "", Usage{}, fmt.Errorf("empty response from Google")
 	}
 
-	return result.Candidates[0].Content.Parts[0].Text, nil
+	usage := Usage{
+		PromptTokens:     result.UsageMetadata.PromptTokenCount,
+		CompletionTokens: result.UsageMetadata.CandidatesTokenCount,
+	}
+	return result.Candidates[0].Content.Parts[0].Text, usage, nil
 }
 
 // Stream sends a streaming request
@@ -122,6 +171,9 @@ func (p *GoogleProvider) Stream(ctx context.Context, prompt string, opts Request
 		defer close(tokenChan)
 		defer close(errChan)
 
+		ctx, cancel := withRequestTimeout(ctx, opts)
+		defer cancel()
+
 		contents := []map[string]interface{}{
 			{
 				"parts": []map[string]string{
@@ -152,7 +204,7 @@ func (p *GoogleProvider) Stream(ctx context.Context, prompt string, opts Request
 			return
 		}
 
-		url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:streamGenerateContent?key=%s&alt=sse", opts.Model, p.apiKey)
+		url := fmt.Sprintf("%s/v1beta/models/%s:streamGenerateContent?alt=sse", p.baseURL, opts.Model)
 		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			errChan <- fmt.Errorf("failed to create request: %w", err)
@@ -160,6 +212,7 @@ func (p *GoogleProvider) Stream(ctx context.Context, prompt string, opts Request
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		p.setHeaders(req)
 
 		resp, err := p.client.Do(req)
 		if err != nil {
@@ -169,25 +222,132 @@ func (p *GoogleProvider) Stream(ctx context.Context, prompt string, opts Request
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			errChan <- fmt.Errorf("google API error (status %d): %s", resp.StatusCode, string(body))
+			errChan <- newAPIError("google", resp)
 			return
 		}
 
-		// Google uses SSE (Server-Sent Events) for streaming
-		// For simplicity, fall back to non-streaming for now
-		// Full SSE implementation would require more complex parsing
-		response, err := p.Request(ctx, prompt, opts)
+		err = parseGeminiSSE(resp.Body, func(text string) bool {
+			select {
+			case tokenChan <- text:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
 		if err != nil {
-			errChan <- err
-			return
-		}
-
-		select {
-		case tokenChan <- response:
-		case <-ctx.Done():
+			errChan <- fmt.Errorf("stream reading error: %w", err)
 		}
 	}()
 
 	return tokenChan, errChan
 }
+
+// geminiStreamChunk is one event of a streamGenerateContent response
+type geminiStreamChunk struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+
+	// Error is set on an error frame sent mid-stream
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// maxPendingJSON caps the undecoded JSON carried across events
+const maxPendingJSON = 1024 * 1024
+
+// parseGeminiSSE reads a Gemini SSE stream and calls emit with the text of
+// every part of every candidate. An event's data may span several "data:"
+// lines and ends at a blank line; JSON split across events is buffered until
+// it decodes. Data that can never decode (not the start of valid JSON, or
+// past maxPendingJSON) is skipped so later events still arrive, and JSON
+// left incomplete at the end of the stream is an error. Returns early
+// without error when emit returns false.
+func parseGeminiSSE(r io.Reader, emit func(text string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var event []byte   // Data lines of the current event
+	var pending []byte // Incomplete JSON carried across events
+
+	dispatch := func() (bool, error) {
+		if len(event) == 0 {
+			return true, nil
+		}
+		pending = append(pending, event...)
+		event = event[:0]
+
+		decoder := json.NewDecoder(bytes.NewReader(pending))
+		for {
+			start := decoder.InputOffset()
+
+			var chunk geminiStreamChunk
+			err := decoder.Decode(&chunk)
+			switch {
+			case err == io.EOF:
+				pending = pending[:0]
+				return true, nil
+			case err == io.ErrUnexpectedEOF && len(pending) <= maxPendingJSON:
+				// Wait for the rest of the JSON
+				pending = append(pending[:0], pending[start:]...)
+				return true, nil
+			case err != nil:
+				// Malformed, so no later data can complete it
+				pending = pending[:0]
+				return true, nil
+			}
+
+			if chunk.Error != nil {
+				return false, fmt.Errorf("gemini stream error %d: %s", chunk.Error.Code, chunk.Error.Message)
+			}
+			for _, candidate := range chunk.Candidates {
+				for _, part := range candidate.Content.Parts {
+					if part.Text != "" && !emit(part.Text) {
+						return false, nil
+					}
+				}
+			}
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			if ok, err := dispatch(); !ok || err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue // Comments and other SSE fields
+		}
+
+		data := bytes.TrimPrefix(line, []byte("data:"))
+		data = bytes.TrimPrefix(data, []byte(" "))
+		if len(event) > 0 {
+			event = append(event, '\n')
+		}
+		event = append(event, data...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// The stream may close without a trailing blank line
+	if ok, err := dispatch(); !ok || err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(pending)) > 0 {
+		return fmt.Errorf("stream ended inside a JSON chunk (%d bytes undecoded)", len(pending))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultRequestTimeout bounds a request when RequestOptions.Timeout is unset
+const DefaultRequestTimeout = 5 * time.Minute
+
+// HTTPSettings configures the HTTP client a provider sends requests with
+type HTTPSettings struct {
+	// ProxyURL routes requests through a proxy. Empty honors HTTPS_PROXY,
+	// HTTP_PROXY, and NO_PROXY from the environment.
+	ProxyURL string
+
+	// Headers are added to every request, e.g. a gateway's own auth header
+	Headers map[string]string
+}
+
+// HTTPClientSetter is implemented by providers whose HTTP client can be
+// replaced
+type HTTPClientSetter interface {
+	SetHTTPClient(client *http.Client)
+}
+
+// NewHTTPClient creates an HTTP client with the given proxy and headers
+func NewHTTPClient(settings HTTPSettings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if settings.ProxyURL != "" {
+		proxyURL, err := url.Parse(settings.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", settings.ProxyURL, err)
+		}
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			// Like the environment proxy, never route a local server
+			// (e.g. Ollama) through the proxy
+			if isLoopbackHost(req.URL.Hostname()) {
+				return nil, nil
+			}
+			return proxyURL, nil
+		}
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if len(settings.Headers) > 0 {
+		roundTripper = &headerTransport{base: transport, headers: settings.Headers}
+	}
+
+	// No client timeout: requests are bounded by their context so a pass
+	// can run longer than the default
+	return &http.Client{Transport: roundTripper}, nil
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback address
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newDefaultHTTPClient is the client providers start with
+func newDefaultHTTPClient() *http.Client {
+	return &http.Client{}
+}
+
+// headerTransport adds fixed headers to each request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
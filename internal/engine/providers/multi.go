@@ -0,0 +1,289 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LoadBalanceStrategy controls how MultiProvider picks a backend
+type LoadBalanceStrategy string
+
+const (
+	RoundRobin LoadBalanceStrategy = "round-robin"
+	LeastLoad  LoadBalanceStrategy = "least-load" // Fewest in-flight requests
+	Random     LoadBalanceStrategy = "random"
+)
+
+// DefaultCooldown is how long a rate-limited backend is skipped
+const DefaultCooldown = 30 * time.Second
+
+// MultiProvider spreads requests across several providers (e.g. multiple
+// API keys) and routes around backends that are being rate limited
+type MultiProvider struct {
+	providers []ModelProvider
+	strategy  LoadBalanceStrategy
+	cooldown  time.Duration
+
+	mu           sync.Mutex
+	next         int
+	inFlight     []int
+	coolingUntil []time.Time
+}
+
+// NewMultiProvider creates a provider that load balances across providers
+func NewMultiProvider(providers []ModelProvider, strategy LoadBalanceStrategy) *MultiProvider {
+	return &MultiProvider{
+		providers:    providers,
+		strategy:     strategy,
+		cooldown:     DefaultCooldown,
+		inFlight:     make([]int, len(providers)),
+		coolingUntil: make([]time.Time, len(providers)),
+	}
+}
+
+// SetCooldown sets how long a backend is skipped after a 429
+func (p *MultiProvider) SetCooldown(d time.Duration) {
+	p.cooldown = d
+}
+
+// SupportsStructuredOutput reports whether every backend does, since any
+// of them may serve a request
+func (p *MultiProvider) SupportsStructuredOutput() bool {
+	if len(p.providers) == 0 {
+		return false
+	}
+	for _, provider := range p.providers {
+		if !SupportsStructuredOutput(provider) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetHTTPClient passes the client on to every backend
+func (p *MultiProvider) SetHTTPClient(client *http.Client) {
+	for _, provider := range p.providers {
+		if setter, ok := provider.(HTTPClientSetter); ok {
+			setter.SetHTTPClient(client)
+		}
+	}
+}
+
+// Name returns the name of the underlying providers
+func (p *MultiProvider) Name() string {
+	if len(p.providers) == 0 {
+		return "multi"
+	}
+	return p.providers[0].Name()
+}
+
+// ListModels returns the models of the first backend that answers
+func (p *MultiProvider) ListModels(ctx context.Context) ([]string, error) {
+	var lastErr error
+	for _, provider := range p.providers {
+		models, err := provider.ListModels(ctx)
+		if err == nil {
+			return models, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no providers configured")
+	}
+	return nil, lastErr
+}
+
+// Ping succeeds when any backend is healthy
+func (p *MultiProvider) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, provider := range p.providers {
+		err := provider.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no providers configured")
+	}
+	return lastErr
+}
+
+// Request routes a request to one backend, retrying on the next backend
+// when the chosen one is rate limited
+func (p *MultiProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage is Request that also reports token usage
+func (p *MultiProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	tried := make(map[int]bool)
+	var lastErr error
+
+	for len(tried) < len(p.providers) {
+		idx := p.acquire(tried)
+		if idx < 0 {
+			break
+		}
+		tried[idx] = true
+
+		response, usage, err := p.providers[idx].RequestWithUsage(ctx, prompt, opts)
+		p.release(idx, err)
+		if err == nil {
+			return response, usage, nil
+		}
+
+		lastErr = err
+		if !isRateLimited(err) {
+			return "", Usage{}, err
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no providers available")
+	}
+	return "", Usage{}, lastErr
+}
+
+// Stream routes a streaming request to one backend. Streams cannot be
+// replayed, so a 429 only marks the backend as cooling off.
+func (p *MultiProvider) Stream(ctx context.Context, prompt string, opts RequestOptions) (<-chan string, <-chan error) {
+	idx := p.acquire(map[int]bool{})
+	if idx < 0 {
+		tokenChan := make(chan string)
+		errChan := make(chan error, 1)
+		errChan <- fmt.Errorf("no providers available")
+		close(tokenChan)
+		close(errChan)
+		return tokenChan, errChan
+	}
+
+	tokens, errs := p.providers[idx].Stream(ctx, prompt, opts)
+
+	tokenChan := make(chan string, 100)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		var streamErr error
+		defer func() { p.release(idx, streamErr) }()
+
+		for tokens != nil || errs != nil {
+			select {
+			case token, ok := <-tokens:
+				if !ok {
+					tokens = nil
+					continue
+				}
+				select {
+				case tokenChan <- token:
+				case <-ctx.Done():
+					return
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if err != nil {
+					streamErr = err
+					errChan <- err
+				}
+			}
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// acquire picks a backend that is not cooling off and was not tried yet,
+// returning -1 when none is available
+func (p *MultiProvider) acquire(tried map[int]bool) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	candidates := make([]int, 0, len(p.providers))
+	for i := range p.providers {
+		if tried[i] || now.Before(p.coolingUntil[i]) {
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+
+	// If every untried backend is cooling off, use the one that recovers first
+	if len(candidates) == 0 {
+		best := -1
+		for i := range p.providers {
+			if tried[i] {
+				continue
+			}
+			if best < 0 || p.coolingUntil[i].Before(p.coolingUntil[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			return -1
+		}
+		candidates = append(candidates, best)
+	}
+
+	idx := candidates[0]
+	switch p.strategy {
+	case LeastLoad:
+		for _, i := range candidates {
+			if p.inFlight[i] < p.inFlight[idx] {
+				idx = i
+			}
+		}
+	case Random:
+		idx = candidates[rand.Intn(len(candidates))]
+	default: // RoundRobin
+		for offset := 0; offset < len(p.providers); offset++ {
+			i := (p.next + offset) % len(p.providers)
+			if containsIndex(candidates, i) {
+				idx = i
+				break
+			}
+		}
+		p.next = (idx + 1) % len(p.providers)
+	}
+
+	p.inFlight[idx]++
+	return idx
+}
+
+// release marks a request as finished and starts a cooldown on 429
+func (p *MultiProvider) release(idx int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inFlight[idx]--
+	if isRateLimited(err) {
+		p.coolingUntil[idx] = time.Now().Add(p.cooldown)
+	}
+}
+
+// isRateLimited reports whether err is a 429 from a provider
+func isRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// containsIndex reports whether idx is in indices
+func containsIndex(indices []int, idx int) bool {
+	for _, i := range indices {
+		if i == idx {
+			return true
+		}
+	}
+	return false
+}
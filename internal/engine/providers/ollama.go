@@ -5,12 +5,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
 	"os/exec"
 	"strings"
-	"time"
 )
 
 // OllamaProvider implements the ModelProvider interface for Ollama
@@ -19,17 +19,24 @@ type OllamaProvider struct {
 	client  *http.Client
 }
 
-// NewOllamaProvider creates a new Ollama provider
+// NewOllamaProvider creates a new Ollama provider. Like OLLAMA_HOST, baseURL
+// may be a bare host ("gpu-box" or "gpu-box:11434").
 func NewOllamaProvider(baseURL string) *OllamaProvider {
 	if baseURL == "" {
 		baseURL = "http://localhost:11434" // Default Ollama endpoint
 	}
+	if !strings.Contains(baseURL, "://") {
+		host := strings.TrimRight(baseURL, "/")
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, "11434")
+		}
+		baseURL = "http://" + host
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
 
 	return &OllamaProvider{
 		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		client:  newDefaultHTTPClient(),
 	}
 }
 
@@ -38,8 +45,38 @@ func (p *OllamaProvider) Name() string {
 	return "ollama"
 }
 
-// ListModels returns available models from `ollama list`
+// SetHTTPClient replaces the client used for API requests
+func (p *OllamaProvider) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
+// Errors returned by ListModels so callers can tell the two failure modes apart
+var (
+	ErrOllamaUnreachable = errors.New("ollama server not reachable (is it running?)")
+	ErrOllamaNoModels    = errors.New("no ollama models installed (run 'ollama pull <model>')")
+)
+
+// ListModels returns the models pulled into the server from GET /api/tags,
+// falling back to `ollama list` if the HTTP call fails
 func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	models, err := p.pulledModels(ctx)
+	if err != nil {
+		cliModels, cliErr := listModelsCLI(ctx)
+		if cliErr != nil {
+			return nil, err
+		}
+		models = cliModels
+	}
+
+	if len(models) == 0 {
+		return nil, ErrOllamaNoModels
+	}
+
+	return models, nil
+}
+
+// listModelsCLI returns available models from `ollama list`
+func listModelsCLI(ctx context.Context) ([]string, error) {
 	// Execute `ollama list` command
 	cmd := exec.CommandContext(ctx, "ollama", "list")
 	output, err := cmd.Output()
@@ -78,8 +115,79 @@ func (p *OllamaProvider) ListModels(ctx context.Context) ([]string, error) {
 	return models, nil
 }
 
+// Ping checks that the Ollama server is running
+func (p *OllamaProvider) Ping(ctx context.Context) error {
+	_, err := p.pulledModels(ctx)
+	return err
+}
+
+// CheckModel returns an error unless model has been pulled into the Ollama
+// server. A name without a tag matches its ":latest" tag.
+func (p *OllamaProvider) CheckModel(ctx context.Context, model string) error {
+	models, err := p.pulledModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := model
+	if !strings.Contains(name, ":") {
+		name += ":latest"
+	}
+	for _, pulled := range models {
+		if pulled == model || pulled == name {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ollama model %q is not pulled (run 'ollama pull %s')", model, model)
+}
+
+// pulledModels lists the models the server has via GET /api/tags
+func (p *OllamaProvider) pulledModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w at %s: %v", ErrOllamaUnreachable, p.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("ollama", resp)
+	}
+
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	models := make([]string, 0, len(result.Models))
+	for _, model := range result.Models {
+		models = append(models, model.Name)
+	}
+
+	return models, nil
+}
+
 // Request sends a non-streaming request
 func (p *OllamaProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage sends a non-streaming request and reports token usage
+func (p *OllamaProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	ctx, cancel := withRequestTimeout(ctx, opts)
+	defer cancel()
+
 	reqBody := map[string]interface{}{
 		"model":  opts.Model,
 		"prompt": prompt,
@@ -96,35 +204,37 @@ func (p *OllamaProvider) Request(ctx context.Context, prompt string, opts Reques
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
+		return "", Usage{}, newAPIError("ollama", resp)
 	}
 
 	var result struct {
-		Response string `json:"response"`
+		Response        string `json:"response"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result.Response, nil
+	usage := Usage{PromptTokens: result.PromptEvalCount, CompletionTokens: result.EvalCount}
+	return result.Response, usage, nil
 }
 
 // Stream sends a streaming request
@@ -136,6 +246,9 @@ func (p *OllamaProvider) Stream(ctx context.Context, prompt string, opts Request
 		defer close(tokenChan)
 		defer close(errChan)
 
+		ctx, cancel := withRequestTimeout(ctx, opts)
+		defer cancel()
+
 		reqBody := map[string]interface{}{
 			"model":  opts.Model,
 			"prompt": prompt,
@@ -171,8 +284,7 @@ func (p *OllamaProvider) Stream(ctx context.Context, prompt string, opts Request
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			errChan <- fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
+			errChan <- newAPIError("ollama", resp)
 			return
 		}
 
@@ -6,24 +6,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"time"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // OpenAIProvider implements the ModelProvider interface for OpenAI
 type OpenAIProvider struct {
-	apiKey string
-	client *http.Client
+	apiKey  string
+	baseURL string
+	client  *http.Client
 }
 
-// NewOpenAIProvider creates a new OpenAI provider
-func NewOpenAIProvider(apiKey string) *OpenAIProvider {
+// DefaultOpenAIBaseURL is the OpenAI API used when no base URL is configured
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// NewOpenAIProvider creates a new OpenAI provider. baseURL points it at any
+// OpenAI-compatible server (e.g. "http://localhost:8000/v1"); empty uses
+// DefaultOpenAIBaseURL.
+func NewOpenAIProvider(apiKey, baseURL string) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+
 	return &OpenAIProvider{
-		apiKey: apiKey,
-		client: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  newDefaultHTTPClient(),
 	}
 }
 
@@ -32,28 +42,151 @@ func (p *OpenAIProvider) Name() string {
 	return "openai"
 }
 
-// ListModels returns available OpenAI models
+// SupportsStructuredOutput reports true: requests with a ResponseSchema use
+// JSON mode. Models without it reject the request with a 400.
+func (p *OpenAIProvider) SupportsStructuredOutput() bool {
+	return true
+}
+
+// SetHTTPClient replaces the client used for API requests
+func (p *OpenAIProvider) SetHTTPClient(client *http.Client) {
+	p.client = client
+}
+
+// openAIFallbackModels is used when the models endpoint can't be reached
+var openAIFallbackModels = []string{
+	"gpt-4-turbo",
+	"gpt-4-turbo-preview",
+	"gpt-4-0125-preview",
+	"gpt-4-1106-preview",
+	"gpt-4",
+	"gpt-4-0613",
+	"gpt-3.5-turbo",
+	"gpt-3.5-turbo-0125",
+}
+
+// openAIModelCache keeps listed models per base URL and API key for the
+// process lifetime
+var openAIModelCache = struct {
+	sync.Mutex
+	models map[string][]string
+}{models: make(map[string][]string)}
+
+// ListModels returns the chat models available to the API key, falling back
+// to a built-in list when the key is empty or the request fails. Servers at a
+// custom base URL are always asked, since they often need no key.
 func (p *OpenAIProvider) ListModels(ctx context.Context) ([]string, error) {
-	// Return commonly used models (could be enhanced with actual API call)
-	return []string{
-		"gpt-4-turbo",
-		"gpt-4-turbo-preview",
-		"gpt-4-0125-preview",
-		"gpt-4-1106-preview",
-		"gpt-4",
-		"gpt-4-0613",
-		"gpt-3.5-turbo",
-		"gpt-3.5-turbo-0125",
-	}, nil
+	if p.apiKey == "" && p.baseURL == DefaultOpenAIBaseURL {
+		return openAIFallbackModels, nil
+	}
+
+	cacheKey := p.baseURL + "\x00" + p.apiKey
+	openAIModelCache.Lock()
+	models, ok := openAIModelCache.models[cacheKey]
+	openAIModelCache.Unlock()
+	if ok {
+		return models, nil
+	}
+
+	// Fetch unlocked so one slow server doesn't block listing others
+	models, err := p.fetchModels(ctx)
+	if err != nil || len(models) == 0 {
+		return openAIFallbackModels, nil
+	}
+
+	openAIModelCache.Lock()
+	openAIModelCache.models[cacheKey] = models
+	openAIModelCache.Unlock()
+	return models, nil
+}
+
+// fetchModels lists chat-capable models from the /models endpoint. Listings
+// from OpenAI itself are filtered to chat models; compatible servers are
+// trusted to list only what they serve.
+func (p *OpenAIProvider) fetchModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.setAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("openai", resp)
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	models := make([]string, 0, len(result.Data))
+	for _, model := range result.Data {
+		if p.baseURL != DefaultOpenAIBaseURL {
+			models = append(models, model.ID)
+			continue
+		}
+		if strings.HasPrefix(model.ID, "gpt-") || strings.HasPrefix(model.ID, "o1") || strings.HasPrefix(model.ID, "o3") {
+			models = append(models, model.ID)
+		}
+	}
+	sort.Strings(models)
+
+	return models, nil
+}
+
+// Ping checks the API key against the models endpoint
+func (p *OpenAIProvider) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.setAuth(req)
+
+	return doPing(p.client, "openai", req)
+}
+
+// setAuth adds the bearer token, which keyless compatible servers go without
+func (p *OpenAIProvider) setAuth(req *http.Request) {
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
 }
 
 // Request sends a non-streaming request
 func (p *OpenAIProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage sends a non-streaming request and reports token usage
+func (p *OpenAIProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	ctx, cancel := withRequestTimeout(ctx, opts)
+	defer cancel()
+
+	systemPrompt := opts.SystemPrompt
+	if opts.ResponseSchema != nil {
+		// JSON mode takes no schema, so describe it in the instructions
+		systemPrompt = strings.TrimSpace(systemPrompt + "\n\n" + schemaInstruction(opts.ResponseSchema))
+	}
+
 	messages := []map[string]string{}
 
-	if opts.SystemPrompt != "" {
+	if systemPrompt != "" {
 		messages = append(messages, map[string]string{
-			"role": "system", "content": opts.SystemPrompt,
+			"role": "system", "content": systemPrompt,
 		})
 	}
 
@@ -67,29 +200,31 @@ func (p *OpenAIProvider) Request(ctx context.Context, prompt string, opts Reques
 		"max_tokens":  opts.MaxTokens,
 		"temperature": opts.Temperature,
 	}
+	if opts.ResponseSchema != nil {
+		reqBody["response_format"] = map[string]string{"type": "json_object"}
+	}
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	p.setAuth(req)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, string(body))
+		return "", Usage{}, newAPIError("openai", resp)
 	}
 
 	var result struct {
@@ -98,17 +233,22 @@ func (p *OpenAIProvider) Request(ctx context.Context, prompt string, opts Reques
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return "", Usage{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("empty response from OpenAI")
+		return "", Usage{}, fmt.Errorf("empty response from OpenAI")
 	}
 
-	return result.Choices[0].Message.Content, nil
+	usage := Usage{PromptTokens: result.Usage.PromptTokens, CompletionTokens: result.Usage.CompletionTokens}
+	return result.Choices[0].Message.Content, usage, nil
 }
 
 // Stream sends a streaming request
@@ -120,6 +260,9 @@ func (p *OpenAIProvider) Stream(ctx context.Context, prompt string, opts Request
 		defer close(tokenChan)
 		defer close(errChan)
 
+		ctx, cancel := withRequestTimeout(ctx, opts)
+		defer cancel()
+
 		messages := []map[string]string{}
 
 		if opts.SystemPrompt != "" {
@@ -146,14 +289,14 @@ func (p *OpenAIProvider) Stream(ctx context.Context, prompt string, opts Request
 			return
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 		if err != nil {
 			errChan <- fmt.Errorf("failed to create request: %w", err)
 			return
 		}
 
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+		p.setAuth(req)
 
 		resp, err := p.client.Do(req)
 		if err != nil {
@@ -163,8 +306,7 @@ func (p *OpenAIProvider) Stream(ctx context.Context, prompt string, opts Request
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			errChan <- fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, string(body))
+			errChan <- newAPIError("openai", resp)
 			return
 		}
 
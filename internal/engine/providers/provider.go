@@ -2,6 +2,11 @@ package providers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
 )
 
 // ModelProvider defines the interface for LLM providers
@@ -12,11 +17,18 @@ type ModelProvider interface {
 	// Request sends a prompt and returns the complete response
 	Request(ctx context.Context, prompt string, opts RequestOptions) (string, error)
 
+	// RequestWithUsage is Request that also reports the tokens consumed
+	RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error)
+
 	// Stream sends a prompt and returns a channel of streaming tokens
 	Stream(ctx context.Context, prompt string, opts RequestOptions) (<-chan string, <-chan error)
 
 	// ListModels returns available models for this provider
 	ListModels(ctx context.Context) ([]string, error)
+
+	// Ping cheaply checks that the provider is reachable and the credentials
+	// are accepted
+	Ping(ctx context.Context) error
 }
 
 // RequestOptions contains parameters for LLM requests
@@ -25,12 +37,116 @@ type RequestOptions struct {
 	Temperature  float64 // Sampling temperature (0.0 - 1.0)
 	MaxTokens    int     // Maximum tokens to generate
 	SystemPrompt string  // System prompt/instructions
+
+	// Timeout bounds a request, or a whole stream; zero uses DefaultRequestTimeout
+	Timeout time.Duration
+
+	// ResponseSchema asks for a JSON response matching the schema. Providers
+	// that don't implement StructuredOutputProvider ignore it.
+	ResponseSchema *ResponseSchema
+
+	// Retries of transient errors (used by RetryProvider)
+	MaxAttempts    int           // Total attempts, including the first
+	RetryBaseDelay time.Duration // Delay before the first retry, doubled each time
+}
+
+// ResponseSchema describes the JSON object a structured request must return
+type ResponseSchema struct {
+	Name        string                 // Tool or schema name, e.g. "report_findings"
+	Description string                 // What the object is for
+	Schema      map[string]interface{} // JSON Schema of the object
+}
+
+// StructuredOutputProvider is implemented by providers that honor
+// RequestOptions.ResponseSchema, returning only the JSON object
+type StructuredOutputProvider interface {
+	SupportsStructuredOutput() bool
+}
+
+// SupportsStructuredOutput reports whether provider honors ResponseSchema
+func SupportsStructuredOutput(provider ModelProvider) bool {
+	structured, ok := provider.(StructuredOutputProvider)
+	return ok && structured.SupportsStructuredOutput()
+}
+
+// schemaInstruction tells the model the shape of the JSON object for APIs
+// whose JSON mode doesn't take a schema
+func schemaInstruction(schema *ResponseSchema) string {
+	data, err := json.Marshal(schema.Schema)
+	if err != nil {
+		return "Respond only with a JSON object."
+	}
+	return "Respond only with a JSON object matching this JSON Schema:\n" + string(data)
+}
+
+// Usage reports the tokens consumed by a request
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Add returns the sum of two usages
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+	}
 }
 
 // DefaultRequestOptions returns sensible defaults
 func DefaultRequestOptions() RequestOptions {
 	return RequestOptions{
-		Temperature: 0.7,
-		MaxTokens:   4000,
+		Temperature:    0.7,
+		MaxTokens:      4000,
+		MaxAttempts:    DefaultMaxAttempts,
+		RetryBaseDelay: DefaultRetryBaseDelay,
+	}
+}
+
+// withRequestTimeout bounds ctx by opts.Timeout. The deadline replaces a
+// fixed client timeout so that per-pass timeouts and cancellation both apply.
+func withRequestTimeout(ctx context.Context, opts RequestOptions) (context.Context, context.CancelFunc) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// APIError is returned when a provider responds with a non-200 status
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // From the Retry-After header, if sent
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// doPing sends a health-check request, treating any 200 as healthy
+func doPing(client *http.Client, provider string, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(provider, resp)
+	}
+	return nil
+}
+
+// newAPIError builds an APIError from an unsuccessful response
+func newAPIError(provider string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 	}
 }
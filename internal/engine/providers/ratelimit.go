@@ -0,0 +1,189 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces requests to stay under per-minute request and token
+// limits. Requests are let through evenly rather than in bursts, so workers
+// from the concurrency pool queue here instead of all firing and getting 429s.
+type RateLimiter struct {
+	mu       sync.Mutex
+	requests bucket
+	tokens   bucket
+}
+
+// NewRateLimiter creates a limiter for the given limits; zero disables a limit
+func NewRateLimiter(requestsPerMinute, tokensPerMinute int) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		// A burst of one request keeps starts evenly spaced
+		requests: newBucket(requestsPerMinute, 1, now),
+		// A minute's worth of tokens can be spent up front
+		tokens: newBucket(tokensPerMinute, tokensPerMinute, now),
+	}
+}
+
+// Wait blocks until a request estimated at tokens may be sent, or ctx ends
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	l.mu.Lock()
+	now := time.Now()
+	tokens = min(tokens, int(l.tokens.capacity))
+	delay := max(l.requests.reserve(now, 1), l.tokens.reserve(now, float64(tokens)))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the reservation back for the requests still waiting
+		l.mu.Lock()
+		l.requests.refund(1)
+		l.tokens.refund(float64(tokens))
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Adjust corrects the token bucket once a request's actual usage is known
+// (delta is actual minus estimated tokens)
+func (l *RateLimiter) Adjust(delta int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.refill(time.Now())
+	l.tokens.refund(float64(-delta))
+}
+
+// bucket is a token bucket that may go negative: a reservation is taken
+// immediately and the caller waits for the deficit to refill
+type bucket struct {
+	rate     float64 // Tokens per second; 0 means unlimited
+	capacity float64
+	level    float64
+	last     time.Time
+}
+
+func newBucket(perMinute, capacity int, now time.Time) bucket {
+	return bucket{
+		rate:     float64(perMinute) / 60,
+		capacity: float64(capacity),
+		level:    float64(capacity),
+		last:     now,
+	}
+}
+
+// reserve takes n tokens and returns how long until they are available
+func (b *bucket) reserve(now time.Time, n float64) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refill(now)
+	b.level -= n
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.rate * float64(time.Second))
+}
+
+// refund returns n tokens, up to capacity
+func (b *bucket) refund(n float64) {
+	if b.rate <= 0 {
+		return
+	}
+	b.level = min(b.level+n, b.capacity)
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.level = min(b.level+elapsed*b.rate, b.capacity)
+		b.last = now
+	}
+}
+
+// RateLimitedProvider makes another provider's requests wait on a RateLimiter
+type RateLimitedProvider struct {
+	provider ModelProvider
+	limiter  *RateLimiter
+}
+
+// NewRateLimitedProvider wraps a provider with a rate limiter. Wrap it in a
+// RetryProvider, not the other way round, so that retries are limited too.
+func NewRateLimitedProvider(provider ModelProvider, limiter *RateLimiter) *RateLimitedProvider {
+	return &RateLimitedProvider{provider: provider, limiter: limiter}
+}
+
+// Name returns the wrapped provider's name
+func (p *RateLimitedProvider) Name() string {
+	return p.provider.Name()
+}
+
+// SupportsStructuredOutput reports whether the wrapped provider does
+func (p *RateLimitedProvider) SupportsStructuredOutput() bool {
+	return SupportsStructuredOutput(p.provider)
+}
+
+// SetHTTPClient passes the client on to the wrapped provider
+func (p *RateLimitedProvider) SetHTTPClient(client *http.Client) {
+	if setter, ok := p.provider.(HTTPClientSetter); ok {
+		setter.SetHTTPClient(client)
+	}
+}
+
+// ListModels returns the wrapped provider's models without waiting
+func (p *RateLimitedProvider) ListModels(ctx context.Context) ([]string, error) {
+	return p.provider.ListModels(ctx)
+}
+
+// Ping checks the wrapped provider without waiting
+func (p *RateLimitedProvider) Ping(ctx context.Context) error {
+	return p.provider.Ping(ctx)
+}
+
+// Request waits for the limiter, then sends the request
+func (p *RateLimitedProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage waits for the limiter, then sends the request and
+// corrects the token estimate with the reported usage
+func (p *RateLimitedProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	estimate := estimateRequestTokens(prompt, opts)
+	if err := p.limiter.Wait(ctx, estimate); err != nil {
+		return "", Usage{}, err
+	}
+
+	response, usage, err := p.provider.RequestWithUsage(ctx, prompt, opts)
+	if actual := usage.PromptTokens + usage.CompletionTokens; actual > 0 {
+		p.limiter.Adjust(actual - estimate)
+	}
+	return response, usage, err
+}
+
+// Stream waits for the limiter, then starts the stream
+func (p *RateLimitedProvider) Stream(ctx context.Context, prompt string, opts RequestOptions) (<-chan string, <-chan error) {
+	if err := p.limiter.Wait(ctx, estimateRequestTokens(prompt, opts)); err != nil {
+		tokenChan := make(chan string)
+		errChan := make(chan error, 1)
+		close(tokenChan)
+		errChan <- err
+		close(errChan)
+		return tokenChan, errChan
+	}
+	return p.provider.Stream(ctx, prompt, opts)
+}
+
+// estimateRequestTokens approximates the prompt's tokens at four characters
+// per token; the response is accounted for once usage is reported
+func estimateRequestTokens(prompt string, opts RequestOptions) int {
+	return (len(prompt) + len(opts.SystemPrompt) + 3) / 4
+}
@@ -0,0 +1,256 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Retry defaults used when RequestOptions leaves them unset
+const (
+	DefaultMaxAttempts    = 3
+	DefaultRetryBaseDelay = time.Second
+)
+
+// RetryProvider retries transient failures of another provider with
+// exponential backoff, honoring Retry-After when the API sends it
+type RetryProvider struct {
+	provider ModelProvider
+	logger   *slog.Logger
+}
+
+// NewRetryProvider wraps a provider with retries
+func NewRetryProvider(provider ModelProvider) *RetryProvider {
+	return &RetryProvider{provider: provider, logger: slog.New(slog.DiscardHandler)}
+}
+
+// SetLogger sets the logger that failed attempts are logged to
+func (p *RetryProvider) SetLogger(logger *slog.Logger) {
+	p.logger = logger
+}
+
+// Name returns the wrapped provider's name
+func (p *RetryProvider) Name() string {
+	return p.provider.Name()
+}
+
+// SupportsStructuredOutput reports whether the wrapped provider does
+func (p *RetryProvider) SupportsStructuredOutput() bool {
+	return SupportsStructuredOutput(p.provider)
+}
+
+// SetHTTPClient passes the client on to the wrapped provider
+func (p *RetryProvider) SetHTTPClient(client *http.Client) {
+	if setter, ok := p.provider.(HTTPClientSetter); ok {
+		setter.SetHTTPClient(client)
+	}
+}
+
+// ListModels returns the wrapped provider's models
+func (p *RetryProvider) ListModels(ctx context.Context) ([]string, error) {
+	return p.provider.ListModels(ctx)
+}
+
+// Ping checks the wrapped provider once; a health check shouldn't back off
+func (p *RetryProvider) Ping(ctx context.Context) error {
+	return p.provider.Ping(ctx)
+}
+
+// Request sends a request, retrying transient errors
+func (p *RetryProvider) Request(ctx context.Context, prompt string, opts RequestOptions) (string, error) {
+	response, _, err := p.RequestWithUsage(ctx, prompt, opts)
+	return response, err
+}
+
+// RequestWithUsage is Request that also reports token usage
+func (p *RetryProvider) RequestWithUsage(ctx context.Context, prompt string, opts RequestOptions) (string, Usage, error) {
+	var response string
+	var usage Usage
+	err := p.withRetry(ctx, opts, func() error {
+		var err error
+		response, usage, err = p.provider.RequestWithUsage(ctx, prompt, opts)
+		return err
+	})
+	return response, usage, err
+}
+
+// Stream starts a stream, retrying while it fails before the first token.
+// Once tokens flow, errors are passed through since they can't be replayed.
+func (p *RetryProvider) Stream(ctx context.Context, prompt string, opts RequestOptions) (<-chan string, <-chan error) {
+	tokenChan := make(chan string, 100)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(tokenChan)
+		defer close(errChan)
+
+		var tokens <-chan string
+		var errs <-chan error
+		var first string
+		started := false
+
+		err := p.withRetry(ctx, opts, func() error {
+			tokens, errs = p.provider.Stream(ctx, prompt, opts)
+			select {
+			case token, ok := <-tokens:
+				if !ok {
+					// Stream ended without tokens; report its error, if any
+					tokens = nil
+					return <-errs
+				}
+				first, started = token, true
+				return nil
+			case err := <-errs:
+				if err != nil {
+					return err
+				}
+				// Error channel closed with no error; wait for tokens
+				errs = nil
+				token, ok := <-tokens
+				if !ok {
+					tokens = nil
+					return nil
+				}
+				first, started = token, true
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		if started {
+			select {
+			case tokenChan <- first:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for tokens != nil || errs != nil {
+			select {
+			case token, ok := <-tokens:
+				if !ok {
+					tokens = nil
+					continue
+				}
+				select {
+				case tokenChan <- token:
+				case <-ctx.Done():
+					return
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if err != nil {
+					errChan <- err
+					return
+				}
+			}
+		}
+	}()
+
+	return tokenChan, errChan
+}
+
+// withRetry calls fn until it succeeds, fails permanently, or runs out of
+// attempts, logging each retried failure
+func (p *RetryProvider) withRetry(ctx context.Context, opts RequestOptions, fn func() error) error {
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+	baseDelay := opts.RetryBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		err = fn()
+		if err == nil || !isRetryable(err) || attempt == attempts-1 {
+			return err
+		}
+
+		delay := retryDelay(err, baseDelay, attempt)
+		attrs := []any{
+			slog.String("provider", p.provider.Name()),
+			slog.String("model", opts.Model),
+			slog.Int("attempt", attempt+1),
+			slog.String("error", err.Error()),
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+		}
+		p.logger.Info("request failed, retrying", append(attrs, slog.Duration("delay", delay))...)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// retryDelay prefers the server's Retry-After, otherwise backs off
+// exponentially with jitter
+func retryDelay(err error, baseDelay time.Duration, attempt int) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	delay := baseDelay << attempt
+	return delay + time.Duration(rand.Int63n(int64(baseDelay)))
+}
+
+// isRetryable reports whether err is a rate limit, a transient server error,
+// or a network failure
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable:
+			return true
+		}
+		return false
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as a date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
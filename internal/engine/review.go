@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReviewStatus tracks what a reviewer has done about a finding
+type ReviewStatus string
+
+const (
+	ReviewPending   ReviewStatus = "pending"
+	ReviewResolved  ReviewStatus = "resolved"
+	ReviewDismissed ReviewStatus = "dismissed"
+)
+
+// ReviewState records review statuses by HashFinding so progress survives
+// across sessions and re-opened reports. Pending findings are not stored.
+type ReviewState struct {
+	UpdatedAt time.Time               `json:"updated_at"`
+	Statuses  map[string]ReviewStatus `json:"statuses"`
+}
+
+// ReviewStatePath returns the location of the project's review state file
+func ReviewStatePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".churn", "review-state.json")
+}
+
+// LoadReviewState reads the project's review state. A missing file yields an empty state.
+func LoadReviewState(projectRoot string) (*ReviewState, error) {
+	state := &ReviewState{Statuses: make(map[string]ReviewStatus)}
+
+	data, err := os.ReadFile(ReviewStatePath(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read review state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse review state: %w", err)
+	}
+	if state.Statuses == nil {
+		state.Statuses = make(map[string]ReviewStatus)
+	}
+
+	return state, nil
+}
+
+// Status returns the review status of a finding
+func (s *ReviewState) Status(finding *Finding) ReviewStatus {
+	if status, ok := s.Statuses[HashFinding(finding)]; ok {
+		return status
+	}
+	return ReviewPending
+}
+
+// SetStatus records the review status of a finding
+func (s *ReviewState) SetStatus(finding *Finding, status ReviewStatus) {
+	if s.Statuses == nil {
+		s.Statuses = make(map[string]ReviewStatus)
+	}
+
+	hash := HashFinding(finding)
+	if status == ReviewPending {
+		delete(s.Statuses, hash)
+		return
+	}
+	s.Statuses[hash] = status
+}
+
+// Save writes the review state to .churn/review-state.json
+func (s *ReviewState) Save(projectRoot string) error {
+	path := ReviewStatePath(projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create review state directory: %w", err)
+	}
+
+	s.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal review state: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write review state: %w", err)
+	}
+
+	return nil
+}
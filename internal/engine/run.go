@@ -0,0 +1,201 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// AnalysisRun bundles everything needed to execute a pipeline over a project
+// and turn the result into a saved report
+type AnalysisRun struct {
+	ProjectRoot  string
+	Files        []*FileInfo
+	Tree         *FileNode
+	Context      *ProjectContext
+	Orchestrator *PipelineOrchestrator
+	ScanStats    ScanStats
+
+	// Baseline, when set, filters known findings out of the report
+	Baseline *Baseline
+
+	// DedupOverlap is the threshold for merging overlapping findings
+	DedupOverlap float64
+
+	// MinSeverity, when set, drops less severe findings from the report
+	MinSeverity Severity
+
+	// Retention prunes old reports once the new one is saved
+	Retention ReportRetention
+
+	// Blame attaches git blame details to the report's findings
+	Blame bool
+
+	// LogPath is the file the run logs to under .churn/logs/
+	LogPath string
+	logFile *os.File
+	logger  *slog.Logger
+}
+
+// PrepareRun scans the project and builds a configured pipeline for it,
+// checking up front that the provider is usable. The run logs to
+// .churn/logs/ until it is closed.
+func (f *Factory) PrepareRun(projectRoot string) (run *AnalysisRun, err error) {
+	logger, logFile, err := OpenLog(projectRoot, f.LogLevel())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			logger.Error("run failed to start", errorAttrs(err)...)
+			logFile.Close()
+		}
+	}()
+	f.SetLogger(logger)
+
+	provider, err := f.CreateProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	orchestrator, err := f.CreateDefaultPipeline(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipeline: %w", err)
+	}
+
+	// Fail fast on a bad key or a stopped server rather than per request
+	if err := f.CheckProvider(context.Background(), orchestrator); err != nil {
+		return nil, err
+	}
+
+	files, tree, err := f.ScanProject(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	scanAttrs := []any{slog.String("project", projectRoot), slog.Int("files", len(files))}
+	if skipped := f.LastScanStats().String(); skipped != "" {
+		scanAttrs = append(scanAttrs, slog.String("skipped", skipped))
+	}
+	logger.Info("project scanned", scanAttrs...)
+
+	projectCtx := f.BuildContext(projectRoot, files)
+
+	orchestrator.SetContext(projectCtx)
+	orchestrator.SetCache(f.CreateResponseCache(projectRoot))
+
+	templates, err := LoadPromptTemplates(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	orchestrator.SetPromptTemplates(templates)
+
+	var baseline *Baseline
+	if f.cfg.Project.UseBaseline {
+		baseline, err = LoadBaseline(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &AnalysisRun{
+		ProjectRoot:  projectRoot,
+		Files:        files,
+		Tree:         tree,
+		Context:      projectCtx,
+		Orchestrator: orchestrator,
+		ScanStats:    f.LastScanStats(),
+		Baseline:     baseline,
+		DedupOverlap: f.DedupOverlap(),
+		MinSeverity:  f.MinSeverity(),
+		Retention:    f.ReportRetention(),
+		Blame:        f.cfg.Project.Blame,
+		LogPath:      logFile.Name(),
+		logFile:      logFile,
+		logger:       logger,
+	}, nil
+}
+
+// Execute runs the pipeline over the scanned files, checkpointing finished
+// files to .churn/state/ and discarding any earlier checkpoint. The
+// orchestrator's event channel is closed when it returns, even on error.
+func (r *AnalysisRun) Execute(ctx context.Context) error {
+	checkpoint, err := NewCheckpoint(r.ProjectRoot)
+	if err != nil {
+		r.Orchestrator.closeEvents()
+		return err
+	}
+	r.Orchestrator.SetCheckpoint(checkpoint)
+	return r.Orchestrator.Execute(ctx, r.Files)
+}
+
+// Resume runs the pipeline like Execute, but skips files the checkpoint of
+// an interrupted run already holds for a pass. Files changed since are
+// analyzed again.
+func (r *AnalysisRun) Resume(ctx context.Context) error {
+	checkpoint, err := LoadCheckpoint(r.ProjectRoot)
+	if err != nil {
+		r.Orchestrator.closeEvents()
+		return err
+	}
+	r.Orchestrator.SetCheckpoint(checkpoint)
+	return r.Orchestrator.Execute(ctx, r.Files)
+}
+
+// Report builds the analysis report from the pipeline's current state
+func (r *AnalysisRun) Report() *AnalysisReport {
+	pipeline := r.Orchestrator.GetPipeline()
+
+	endTime := pipeline.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+
+	findings := pipeline.Findings
+	suppressed := 0
+	if r.Baseline != nil {
+		findings, suppressed = r.Baseline.Filter(findings)
+	}
+	findings, belowMin := FilterBySeverity(findings, r.MinSeverity)
+
+	report := GenerateReport(r.Context, r.Files, findings, pipeline.Passes, pipeline.StartTime, endTime, r.DedupOverlap)
+	report.Summary.BaselineSuppressed = suppressed
+	report.Summary.MinSeverity = r.MinSeverity
+	report.Summary.BelowMinSeverity = belowMin
+	report.GitBranch, report.GitCommit = gitRevision(r.ProjectRoot)
+	if r.Blame {
+		AnnotateBlame(r.ProjectRoot, report.Findings)
+	}
+	return report
+}
+
+// Finish builds the report and saves it to .churn/reports/, pruning reports
+// beyond the retention limits. The checkpoint is removed once the report is
+// saved, since the run is complete.
+func (r *AnalysisRun) Finish() (*AnalysisReport, error) {
+	report := r.Report()
+	if err := SaveReport(r.ProjectRoot, report); err != nil {
+		r.logger.Error("failed to save report", errorAttrs(err)...)
+		return report, err
+	}
+	r.logger.Info("report saved",
+		slog.Int("findings", report.Summary.FindingCount),
+		slog.Float64("duration_seconds", report.Summary.Duration))
+	if _, err := PruneReports(r.ProjectRoot, r.Retention); err != nil {
+		return report, err
+	}
+	if err := RemoveCheckpoint(r.ProjectRoot); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// Close closes the run's log file
+func (r *AnalysisRun) Close() error {
+	if r.logFile == nil {
+		return nil
+	}
+	return r.logFile.Close()
+}
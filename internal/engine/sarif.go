@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/cloudboy-jh/churn-plus"
+)
+
+// sarifLog is the top-level SARIF document
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// ExportSARIF writes the report as a SARIF 2.1.0 log, the format GitHub code
+// scanning ingests. Each finding kind becomes a rule.
+func ExportSARIF(report *AnalysisReport, w io.Writer) error {
+	root := ""
+	if report.Context != nil {
+		root = report.Context.RootPath
+	}
+
+	// Rules are sorted so the output is stable between runs
+	kinds := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, finding := range report.Findings {
+		kind := sarifRuleID(finding.Kind)
+		if !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+
+	ruleIndex := make(map[string]int, len(kinds))
+	rules := make([]sarifRule, 0, len(kinds))
+	for i, kind := range kinds {
+		ruleIndex[kind] = i
+		rules = append(rules, sarifRule{
+			ID:               kind,
+			Name:             kind,
+			ShortDescription: sarifMessage{Text: strings.ReplaceAll(kind, "-", " ")},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		kind := sarifRuleID(finding.Kind)
+		results = append(results, sarifResult{
+			RuleID:    kind,
+			RuleIndex: ruleIndex[kind],
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{
+						URI:       reportPath(root, finding.File),
+						URIBaseID: "%SRCROOT%",
+					},
+					Region: findingRegion(finding),
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "churn-plus",
+				Version:        report.Version,
+				InformationURI: sarifToolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(log); err != nil {
+		return fmt.Errorf("failed to write SARIF: %w", err)
+	}
+
+	return nil
+}
+
+// sarifRuleID returns the rule for a finding kind
+func sarifRuleID(kind string) string {
+	if kind == "" {
+		return "general"
+	}
+	return kind
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// findingRegion converts a finding's line range; SARIF lines start at 1
+func findingRegion(finding *Finding) sarifRegion {
+	region := sarifRegion{StartLine: finding.LineStart}
+	if region.StartLine < 1 {
+		region.StartLine = 1
+	}
+	if finding.LineEnd >= region.StartLine {
+		region.EndLine = finding.LineEnd
+	}
+	return region
+}
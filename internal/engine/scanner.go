@@ -1,17 +1,65 @@
 package engine
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 // Scanner scans a project directory and returns structured file information
 type Scanner struct {
-	rootPath       string
-	ignorePatterns []string
+	rootPath           string
+	ignorePatterns     []string
+	extensionOverrides map[string]string
+	scanArchives       bool
+	workers            int
+	maxFileBytes       int64
+	changedOnly        bool
+	countCode          bool
+	baseRef            string
+	respectGitignore   bool
+	gitignore          *ignoreMatcher
+	churnignore        *ignoreMatcher
+
+	statsMu sync.Mutex
+	stats   ScanStats
+}
+
+// DefaultMaxFileBytes is the size above which files are skipped
+const DefaultMaxFileBytes = 512 * 1024
+
+var (
+	// errFileTooLarge marks files skipped for exceeding the size limit
+	errFileTooLarge = errors.New("file exceeds size limit")
+
+	// errBinaryFile marks files skipped for having binary content
+	errBinaryFile = errors.New("file is binary")
+)
+
+// ScanStats reports files the scanner skipped
+type ScanStats struct {
+	SkippedTooLarge int
+	SkippedBinary   int
+	Warnings        []string
+}
+
+// String summarizes the stats, returning "" when nothing was skipped
+func (st ScanStats) String() string {
+	var parts []string
+	if st.SkippedTooLarge > 0 {
+		parts = append(parts, fmt.Sprintf("%d files skipped (too large)", st.SkippedTooLarge))
+	}
+	if st.SkippedBinary > 0 {
+		parts = append(parts, fmt.Sprintf("%d files skipped (binary)", st.SkippedBinary))
+	}
+	parts = append(parts, st.Warnings...)
+	return strings.Join(parts, ", ")
 }
 
 // NewScanner creates a new project scanner
@@ -19,44 +67,112 @@ func NewScanner(rootPath string, ignorePatterns []string) *Scanner {
 	return &Scanner{
 		rootPath:       rootPath,
 		ignorePatterns: ignorePatterns,
+		maxFileBytes:   DefaultMaxFileBytes,
+	}
+}
+
+// SetMaxFileBytes sets the size above which files are skipped; 0 uses the
+// default and a negative value disables the limit
+func (s *Scanner) SetMaxFileBytes(n int64) {
+	if n == 0 {
+		n = DefaultMaxFileBytes
+	}
+	s.maxFileBytes = n
+}
+
+// Stats returns what the last Scan skipped
+func (s *Scanner) Stats() ScanStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	return s.stats
+}
+
+// SetExtensionOverrides maps file extensions to languages, taking precedence
+// over the built-in language table and force-including those extensions
+func (s *Scanner) SetExtensionOverrides(overrides map[string]string) {
+	s.extensionOverrides = make(map[string]string, len(overrides))
+	for ext, lang := range overrides {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		s.extensionOverrides[ext] = lang
 	}
 }
 
-// Scan traverses the project and returns all relevant files
+// SetRespectGitignore enables skipping paths excluded by the project's
+// .gitignore files, including nested ones
+func (s *Scanner) SetRespectGitignore(enabled bool) {
+	s.respectGitignore = enabled
+}
+
+// SetChangedOnly restricts scans to files changed since baseRef (default
+// "main"), plus uncommitted work
+func (s *Scanner) SetChangedOnly(enabled bool, baseRef string) {
+	if baseRef == "" {
+		baseRef = DefaultBaseRef
+	}
+	s.changedOnly = enabled
+	s.baseRef = baseRef
+}
+
+// SetWorkers sets how many files are processed in parallel (default: runtime.NumCPU())
+func (s *Scanner) SetWorkers(n int) {
+	s.workers = n
+}
+
+// SetCountCodeLines enables counting each file's source lines of code
+// (FileInfo.CodeLines), skipping blank and comment-only lines
+func (s *Scanner) SetCountCodeLines(enabled bool) {
+	s.countCode = enabled
+}
+
+// SetScanArchives enables unpacking and scanning .zip and .tar.gz archives
+func (s *Scanner) SetScanArchives(enabled bool) {
+	s.scanArchives = enabled
+}
+
+// Scan traverses the project and returns all relevant files. The tree is
+// walked first to collect candidates, which are then processed in parallel;
+// results keep the walk order.
 func (s *Scanner) Scan() ([]*FileInfo, error) {
-	var files []*FileInfo
+	s.gitignore = &ignoreMatcher{}
+	s.churnignore = loadChurnignore(s.rootPath)
+	s.stats = ScanStats{}
 
-	err := filepath.Walk(s.rootPath, func(path string, info os.FileInfo, err error) error {
+	var candidates []string
+	err := filepath.WalkDir(s.rootPath, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
-			return err
+			if path == s.rootPath {
+				return err
+			}
+			// Skip entries we can't read rather than aborting the scan
+			return nil
 		}
 
 		// Skip directories
-		if info.IsDir() {
+		if entry.IsDir() {
 			// Check if directory should be ignored
-			if s.shouldIgnore(path) {
+			if s.shouldIgnore(path, true) {
 				return filepath.SkipDir
 			}
+			if s.respectGitignore {
+				s.loadGitignore(path)
+			}
 			return nil
 		}
 
 		// Skip ignored files
-		if s.shouldIgnore(path) {
+		if s.shouldIgnore(path, false) {
 			return nil
 		}
 
-		// Only include code files
-		if !s.isCodeFile(path) {
+		// Only include code files, and archives when enabled
+		if !s.isCodeFile(path) && !(s.scanArchives && isArchive(path)) {
 			return nil
 		}
 
-		fileInfo, err := s.getFileInfo(path)
-		if err != nil {
-			// Skip files we can't read
-			return nil
-		}
-
-		files = append(files, fileInfo)
+		candidates = append(candidates, path)
 		return nil
 	})
 
@@ -64,7 +180,107 @@ func (s *Scanner) Scan() ([]*FileInfo, error) {
 		return nil, fmt.Errorf("failed to scan directory: %w", err)
 	}
 
-	return files, nil
+	if s.changedOnly {
+		candidates = s.filterChanged(candidates)
+	}
+
+	return s.processCandidates(candidates), nil
+}
+
+// filterChanged keeps only candidates git reports as changed, falling back
+// to the full list with a warning when git can't tell us
+func (s *Scanner) filterChanged(candidates []string) []string {
+	changed, err := gitChangedFiles(s.rootPath, s.baseRef)
+	if err != nil {
+		s.stats.Warnings = append(s.stats.Warnings, fmt.Sprintf("Scanning all files: %v", err))
+		return candidates
+	}
+
+	filtered := make([]string, 0, len(changed))
+	for _, path := range candidates {
+		relPath, err := filepath.Rel(s.rootPath, path)
+		if err != nil {
+			continue
+		}
+		if changed[relPath] {
+			filtered = append(filtered, path)
+		}
+	}
+	return filtered
+}
+
+// processCandidates builds file info for candidates across the worker pool
+func (s *Scanner) processCandidates(candidates []string) []*FileInfo {
+	results := make([][]*FileInfo, len(candidates))
+
+	workers := s.workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = s.processCandidate(candidates[idx])
+			}
+		}()
+	}
+
+	for idx := range candidates {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	var files []*FileInfo
+	for _, result := range results {
+		files = append(files, result...)
+	}
+	return files
+}
+
+// processCandidate returns the files for one candidate path, or nil to skip it
+func (s *Scanner) processCandidate(path string) []*FileInfo {
+	// Scan inside archives when enabled
+	if s.scanArchives && isArchive(path) {
+		archiveFiles, err := s.ScanArchive(path)
+		if errors.Is(err, errArchiveTooLarge) {
+			name := path
+			if rel, err := filepath.Rel(s.rootPath, path); err == nil {
+				name = filepath.ToSlash(rel)
+			}
+			s.statsMu.Lock()
+			s.stats.Warnings = append(s.stats.Warnings, fmt.Sprintf("Skipped %s: %v", name, err))
+			s.statsMu.Unlock()
+			return nil
+		}
+		if err != nil {
+			// Skip archives we can't unpack
+			return nil
+		}
+		return archiveFiles
+	}
+
+	fileInfo, err := s.getFileInfo(path)
+	if errors.Is(err, errFileTooLarge) || errors.Is(err, errBinaryFile) {
+		s.statsMu.Lock()
+		if errors.Is(err, errFileTooLarge) {
+			s.stats.SkippedTooLarge++
+		} else {
+			s.stats.SkippedBinary++
+		}
+		s.statsMu.Unlock()
+		return nil
+	}
+	if err != nil {
+		// Skip files we can't read
+		return nil
+	}
+	return []*FileInfo{fileInfo}
 }
 
 // getFileInfo extracts metadata about a file
@@ -74,52 +290,69 @@ func (s *Scanner) getFileInfo(path string) (*FileInfo, error) {
 		return nil, err
 	}
 
-	lines, err := s.countLines(path)
+	if s.maxFileBytes > 0 && stat.Size() > s.maxFileBytes {
+		return nil, errFileTooLarge
+	}
+
+	// Check for binary content before reading the whole file
+	binary, err := IsBinaryFile(path)
 	if err != nil {
-		lines = 0 // If we can't count lines, default to 0
+		return nil, err
+	}
+	if binary {
+		return nil, errBinaryFile
 	}
 
-	language := detectLanguage(path)
+	language := s.detectLanguage(path)
+
+	lines, codeLines, err := countFileLines(path, language, s.countCode)
+	if err != nil {
+		lines, codeLines = 0, 0 // If we can't count lines, default to 0
+	}
 
 	return &FileInfo{
-		Path:     path,
-		Language: language,
-		Size:     stat.Size(),
-		Lines:    lines,
+		Path:      path,
+		Language:  language,
+		Size:      stat.Size(),
+		Lines:     lines,
+		CodeLines: codeLines,
 	}, nil
 }
 
-// countLines counts the number of lines in a file
-func (s *Scanner) countLines(path string) (int, error) {
-	file, err := os.Open(path)
+// shouldIgnore checks if a path matches any ignore patterns or .gitignore rules
+func (s *Scanner) shouldIgnore(path string, isDir bool) bool {
+	relPath, err := filepath.Rel(s.rootPath, path)
 	if err != nil {
-		return 0, err
+		relPath = path
 	}
-	defer file.Close()
+	relPath = filepath.ToSlash(relPath)
 
-	count := 0
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		count++
+	// Never ignore the project root itself
+	if relPath == "." {
+		return false
 	}
 
-	return count, scanner.Err()
-}
-
-// shouldIgnore checks if a path matches any ignore patterns
-func (s *Scanner) shouldIgnore(path string) bool {
-	relPath, err := filepath.Rel(s.rootPath, path)
-	if err != nil {
-		relPath = path
+	if s.gitignore != nil && s.gitignore.match(relPath, isDir) {
+		return true
+	}
+	if s.churnignore != nil && s.churnignore.match(relPath, isDir) {
+		return true
 	}
 
+	baseName := filepath.Base(path)
+
 	for _, pattern := range s.ignorePatterns {
-		// Simple pattern matching (can be enhanced with glob later)
-		if strings.Contains(relPath, pattern) {
-			return true
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+
+		// Patterns with a slash match the relative path, others a single
+		// path segment (directories are checked as the walk reaches them)
+		if strings.Contains(pattern, "/") {
+			if globMatch(strings.TrimPrefix(pattern, "/"), relPath) {
+				return true
+			}
+			continue
 		}
-		// Check if basename matches
-		if strings.Contains(filepath.Base(path), pattern) {
+		if ok, _ := filepath.Match(pattern, baseName); ok {
 			return true
 		}
 	}
@@ -131,6 +364,18 @@ func (s *Scanner) shouldIgnore(path string) bool {
 func (s *Scanner) isCodeFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 
+	if _, ok := s.extensionOverrides[ext]; ok {
+		return true
+	}
+
+	// Well-known filenames and scripts identified by their shebang
+	if detectLanguageFromName(path) != "" {
+		return true
+	}
+	if ext == "" && detectLanguageFromShebang(path) != "" {
+		return true
+	}
+
 	codeExtensions := map[string]bool{
 		// JavaScript/TypeScript
 		".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
@@ -166,6 +411,113 @@ func (s *Scanner) isCodeFile(path string) bool {
 	return codeExtensions[ext]
 }
 
+// detectLanguage determines the language, consulting project overrides first
+func (s *Scanner) detectLanguage(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if lang, ok := s.extensionOverrides[ext]; ok {
+		return lang
+	}
+	return DetectLanguage(path)
+}
+
+// DetectLanguage determines a file's language from its extension, special
+// filename or shebang line, returning "unknown" if none match
+func DetectLanguage(path string) string {
+	if lang := detectLanguage(path); lang != "unknown" {
+		return lang
+	}
+	if lang := detectLanguageFromName(path); lang != "" {
+		return lang
+	}
+	if lang := detectLanguageFromShebang(path); lang != "" {
+		return lang
+	}
+	return "unknown"
+}
+
+// filenameLanguages maps special filenames that have no useful extension
+var filenameLanguages = map[string]string{
+	"dockerfile":     "dockerfile",
+	"containerfile":  "dockerfile",
+	"makefile":       "make",
+	"gnumakefile":    "make",
+	"cmakelists.txt": "cmake",
+	"jenkinsfile":    "groovy",
+	"vagrantfile":    "ruby",
+	"gemfile":        "ruby",
+	"rakefile":       "ruby",
+	"podfile":        "ruby",
+	"brewfile":       "ruby",
+	".bashrc":        "bash",
+	".bash_profile":  "bash",
+	".profile":       "bash",
+	".zshrc":         "zsh",
+	".zprofile":      "zsh",
+}
+
+// detectLanguageFromName recognizes special filenames such as Dockerfile
+func detectLanguageFromName(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	if lang, ok := filenameLanguages[base]; ok {
+		return lang
+	}
+
+	// Variants like Dockerfile.prod or app.dockerfile
+	if strings.HasPrefix(base, "dockerfile.") || strings.HasSuffix(base, ".dockerfile") {
+		return "dockerfile"
+	}
+	return ""
+}
+
+// shebangLanguages maps shebang interpreters to languages
+var shebangLanguages = map[string]string{
+	"python": "python",
+	"node":   "javascript",
+	"deno":   "typescript",
+	"bash":   "bash",
+	"sh":     "bash",
+	"zsh":    "zsh",
+	"ruby":   "ruby",
+	"perl":   "perl",
+	"php":    "php",
+}
+
+// detectLanguageFromShebang reads a script's "#!" line to find its language
+func detectLanguageFromShebang(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	buf := make([]byte, 128)
+	n, _ := io.ReadFull(file, buf)
+	line, _, _ := strings.Cut(string(buf[:n]), "\n")
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	// "#!/usr/bin/env python3" names the interpreter in the next field
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = field
+				break
+			}
+		}
+	}
+
+	// Drop version suffixes such as python3 or python3.11
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	return shebangLanguages[interpreter]
+}
+
 // detectLanguage determines the programming language from file extension
 func detectLanguage(path string) string {
 	ext := strings.ToLower(filepath.Ext(path))
@@ -219,7 +571,9 @@ func BuildFileTree(files []*FileInfo, rootPath string) *FileNode {
 			continue
 		}
 
-		parts := strings.Split(filepath.ToSlash(relPath), "/")
+		// Files inside archives are nested under the archive as a directory
+		relPath = strings.ReplaceAll(filepath.ToSlash(relPath), ArchiveSeparator, "/")
+		parts := strings.Split(relPath, "/")
 		current := root
 
 		// Create directory nodes
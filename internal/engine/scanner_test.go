@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestScannerShouldIgnore(t *testing.T) {
+	root := filepath.FromSlash("/project")
+	patterns := []string{"dist", "*.map", "**/generated/**"}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		// Basename globs match at any depth
+		{"app.js.map", false, true},
+		{"web/static/app.js.map", false, true},
+		{"web/static/app.js", false, false},
+		{"map.go", false, false},
+
+		// Recursive globs match the directory and everything under it
+		{"generated", true, true},
+		{"api/generated", true, true},
+		{"api/generated/types.go", false, true},
+		{"api/generator.go", false, false},
+		{"api/generated.go", false, false},
+
+		// Plain names match whole path segments only
+		{"dist", true, true},
+		{"web/dist", true, true},
+		{"distance.go", false, false},
+		{"pkg/distance.go", false, false},
+		{"redist", true, false},
+
+		{".", true, false},
+	}
+
+	scanner := NewScanner(root, patterns)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path := filepath.Join(root, filepath.FromSlash(tt.path))
+			if got := scanner.shouldIgnore(path, tt.isDir); got != tt.want {
+				t.Errorf("shouldIgnore(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
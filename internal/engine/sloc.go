@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+)
+
+// commentSyntax is how a language writes comments, for counting code lines
+type commentSyntax struct {
+	line       []string // Line comment markers
+	blockStart string   // Block comment delimiters; empty when there are none
+	blockEnd   string
+}
+
+var (
+	cLikeComments = commentSyntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashComments  = commentSyntax{line: []string{"#"}}
+	htmlComments  = commentSyntax{blockStart: "<!--", blockEnd: "-->"}
+)
+
+// languageComments maps detected languages to their comment syntax.
+// Languages missing here count every non-blank line as code.
+var languageComments = map[string]commentSyntax{
+	"javascript": cLikeComments,
+	"typescript": cLikeComments,
+	"go":         cLikeComments,
+	"rust":       cLikeComments,
+	"c":          cLikeComments,
+	"cpp":        cLikeComments,
+	"java":       cLikeComments,
+	"kotlin":     cLikeComments,
+	"csharp":     cLikeComments,
+	"swift":      cLikeComments,
+	"scss":       cLikeComments,
+	"less":       cLikeComments,
+	"protobuf":   cLikeComments,
+	"groovy":     cLikeComments,
+	"php":        {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"},
+	"css":        {blockStart: "/*", blockEnd: "*/"},
+	"sql":        {line: []string{"--"}, blockStart: "/*", blockEnd: "*/"},
+	"python":     hashComments,
+	"ruby":       hashComments,
+	"bash":       hashComments,
+	"zsh":        hashComments,
+	"perl":       hashComments,
+	"yaml":       hashComments,
+	"toml":       hashComments,
+	"graphql":    hashComments,
+	"make":       hashComments,
+	"cmake":      hashComments,
+	"dockerfile": hashComments,
+	"html":       htmlComments,
+	"vue":        {line: []string{"//"}, blockStart: "<!--", blockEnd: "-->"},
+	"svelte":     {line: []string{"//"}, blockStart: "<!--", blockEnd: "-->"},
+}
+
+// countFileLines counts a file's lines and, when countCode is set, its
+// source lines of code: lines that are neither blank nor only comments.
+// Block comments are tracked on a best-effort basis; delimiters inside
+// strings are not recognized.
+func countFileLines(path, language string, countCode bool) (lines, codeLines int, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	syntax := languageComments[language]
+	inBlock := false
+
+	reader := bufio.NewReaderSize(file, 32*1024)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lines++
+			if countCode {
+				var isCode bool
+				isCode, inBlock = classifyLine(line, syntax, inBlock)
+				if isCode {
+					codeLines++
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return lines, codeLines, readErr
+		}
+	}
+
+	return lines, codeLines, nil
+}
+
+// classifyLine reports whether a line holds code, and whether a block
+// comment is still open after it
+func classifyLine(line []byte, syntax commentSyntax, inBlock bool) (bool, bool) {
+	rest := bytes.TrimSpace(line)
+	for len(rest) > 0 {
+		if inBlock {
+			end := bytes.Index(rest, []byte(syntax.blockEnd))
+			if end < 0 {
+				return false, true
+			}
+			rest = bytes.TrimSpace(rest[end+len(syntax.blockEnd):])
+			inBlock = false
+			continue
+		}
+
+		for _, marker := range syntax.line {
+			if bytes.HasPrefix(rest, []byte(marker)) {
+				return false, false
+			}
+		}
+
+		if syntax.blockStart != "" && bytes.HasPrefix(rest, []byte(syntax.blockStart)) {
+			rest = rest[len(syntax.blockStart):]
+			inBlock = true
+			continue
+		}
+
+		// Code, though a block comment may open after it
+		if syntax.blockStart != "" {
+			if start := bytes.LastIndex(rest, []byte(syntax.blockStart)); start >= 0 {
+				inBlock = !bytes.Contains(rest[start:], []byte(syntax.blockEnd))
+			}
+		}
+		return true, inBlock
+	}
+	return false, inBlock
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+)
+
+// suppressPattern matches a churn-ignore comment in any common comment
+// syntax, optionally restricted to kinds: "// churn-ignore: security, perf"
+var suppressPattern = regexp.MustCompile(`(?://|#|--|/\*|;|<!--)\s*churn-ignore(?:\s*:\s*([\w\-]+(?:\s*,\s*[\w\-]+)*))?`)
+
+// commentOnlyPattern matches a line holding nothing but a comment
+var commentOnlyPattern = regexp.MustCompile(`^\s*(?://|#|--|/\*|;|<!--)`)
+
+// FilterSuppressed drops findings silenced by a churn-ignore comment on the
+// finding's first line, or on a comment-only line directly above it
+func FilterSuppressed(findings []*Finding, content string) []*Finding {
+	if !strings.Contains(content, "churn-ignore") {
+		return findings
+	}
+
+	lines := strings.Split(content, "\n")
+	kept := make([]*Finding, 0, len(findings))
+	for _, finding := range findings {
+		if !isSuppressed(lines, finding) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// isSuppressed reports whether the lines around a finding silence it
+func isSuppressed(lines []string, finding *Finding) bool {
+	idx := finding.LineStart - 1
+	if idx < 0 || idx >= len(lines) {
+		return false
+	}
+
+	if suppressesKind(lines[idx], finding.Kind) {
+		return true
+	}
+
+	if idx > 0 && commentOnlyPattern.MatchString(lines[idx-1]) {
+		return suppressesKind(lines[idx-1], finding.Kind)
+	}
+
+	return false
+}
+
+// suppressesKind reports whether line has a churn-ignore comment covering kind
+func suppressesKind(line, kind string) bool {
+	match := suppressPattern.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+
+	// A bare churn-ignore silences every kind
+	if match[1] == "" {
+		return true
+	}
+
+	for _, k := range strings.Split(match[1], ",") {
+		if strings.EqualFold(strings.TrimSpace(k), kind) {
+			return true
+		}
+	}
+	return false
+}
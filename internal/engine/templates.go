@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// PromptTemplateData is what a custom prompt template can reference
+type PromptTemplateData struct {
+	FilePath     string
+	Language     string
+	Lines        int
+	Content      string // File content, numbered or windowed per PromptOptions
+	ContentNote  string // Explains how Content is formatted, empty for plain content
+	Instructions string // The built-in pass, language and complexity guidance
+	Context      *ProjectContext
+	PassName     string
+
+	// PriorFindings lists earlier passes' findings for chained passes, and
+	// is empty otherwise. Content is empty for findings-only passes.
+	PriorFindings string
+}
+
+// PromptTemplatesDir returns where per-pass prompt templates live
+func PromptTemplatesDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".churn", "prompts")
+}
+
+// LoadPromptTemplates parses .churn/prompts/<pass>.tmpl files, keyed by pass
+// name. Each template is also executed against sample data so references to
+// unknown fields are reported now rather than mid-analysis.
+func LoadPromptTemplates(projectRoot string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+
+	paths, err := filepath.Glob(filepath.Join(PromptTemplatesDir(projectRoot), "*.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt template %s: %w", path, err)
+		}
+
+		pass := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		tmpl, err := template.New(pass).Option("missingkey=error").Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+		}
+
+		sample := PromptTemplateData{
+			FilePath: "example.go",
+			Language: "go",
+			Lines:    1,
+			Content:  "package example",
+			Context:  &ProjectContext{},
+			PassName: pass,
+		}
+		if err := tmpl.Execute(io.Discard, sample); err != nil {
+			return nil, fmt.Errorf("invalid prompt template %s: %w", path, err)
+		}
+
+		templates[pass] = tmpl
+	}
+
+	return templates, nil
+}
+
+// renderPromptTemplate executes a custom template and appends the output
+// format, which findings parsing depends on. Chained passes get the format
+// asking only for new findings, after the earlier findings if the template
+// left them out.
+func renderPromptTemplate(tmpl *template.Template, data PromptTemplateData, chained bool) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template for %s: %w", data.PassName, err)
+	}
+	prompt := strings.TrimRight(sb.String(), " \t\n")
+
+	if !chained {
+		return prompt + "\n\n" + findingsOutputFormat, nil
+	}
+	if !strings.Contains(prompt, data.PriorFindings) {
+		prompt += "\n\n" + data.PriorFindings
+	}
+	return prompt + "\n\n" + chainedOutputFormat, nil
+}
@@ -0,0 +1,62 @@
+package engine
+
+import "strings"
+
+// DefaultContextWindow is assumed for models missing from modelContextWindows
+const DefaultContextWindow = 8192
+
+// modelContextWindows maps model name prefixes to context windows in tokens.
+// Longer prefixes are checked first, as with modelPrices.
+var modelContextWindows = map[string]int{
+	"claude-3":         200_000,
+	"claude-3.5":       200_000,
+	"gpt-4o":           128_000,
+	"gpt-4.1":          1_000_000,
+	"gpt-4-turbo":      128_000,
+	"gpt-4":            8192,
+	"gpt-3.5-turbo":    16_385,
+	"o1":               128_000,
+	"o3":               200_000,
+	"gemini-1.5":       1_000_000,
+	"gemini-2.0":       1_000_000,
+	"llama2":           4096,
+	"llama3":           8192,
+	"mistral":          32_000,
+	"codellama":        16_384,
+	"qwen2.5-coder":    32_000,
+	"deepseek-coder":   16_384,
+	"deepseek-coder-v": 128_000,
+}
+
+// EstimateTokens approximates the token count of text at four characters per token
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// ContextWindow returns the context window for a model. Overrides are keyed
+// by model name prefix and take precedence over the built-in table.
+func ContextWindow(model string, overrides map[string]int) int {
+	if window, ok := lookupPrefix(model, overrides); ok && window > 0 {
+		return window
+	}
+	if window, ok := lookupPrefix(model, modelContextWindows); ok {
+		return window
+	}
+	return DefaultContextWindow
+}
+
+// lookupPrefix finds the value for the longest key that prefixes model
+func lookupPrefix(model string, table map[string]int) (int, bool) {
+	best := ""
+	found := false
+	for prefix := range table {
+		if strings.HasPrefix(model, prefix) && (!found || len(prefix) > len(best)) {
+			best = prefix
+			found = true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	return table[best], true
+}
@@ -22,6 +22,26 @@ type Finding struct {
 	Message   string   `json:"message"`
 	Pass      string   `json:"pass"`      // Which pass generated this finding
 	Code      string   `json:"code,omitempty"` // Optional: code snippet
+
+	// FunctionComplexity is the cyclomatic complexity of the enclosing function, 0 if unknown
+	FunctionComplexity int `json:"function_complexity,omitempty"`
+
+	// Blame is the last commit to touch the finding's lines, when blame is enabled
+	Blame *BlameInfo `json:"blame,omitempty"`
+
+	// Passes lists every pass that reported this finding when near-duplicates were merged
+	Passes []string `json:"passes,omitempty"`
+
+	// Source is the text of lines LineStart-LineEnd when the finding was
+	// reported; patches are refused once those lines have changed
+	Source string `json:"source,omitempty"`
+}
+
+// BlameInfo identifies who last changed a range of lines, from git blame
+type BlameInfo struct {
+	Author     string    `json:"author"`
+	Commit     string    `json:"commit"`
+	CommitDate time.Time `json:"commit_date"`
 }
 
 // ProjectContext holds metadata about the analyzed project
@@ -44,6 +64,37 @@ const (
 	PassFailed    PassStatus = "failed"
 )
 
+// PassMode sets what a pass is given to analyze
+type PassMode string
+
+const (
+	// PassModeFiles sends each file's content (the default)
+	PassModeFiles PassMode = "files"
+	// PassModeFindings sends the findings of earlier passes instead of file
+	// content; files without earlier findings are skipped
+	PassModeFindings PassMode = "findings"
+	// PassModeBoth sends each file's content alongside its earlier findings
+	PassModeBoth PassMode = "both"
+)
+
+// DefaultPassMode returns the mode of a pass that doesn't set one: the
+// refinement and summary passes build on earlier findings
+func DefaultPassMode(passName string) PassMode {
+	switch passName {
+	case "local-refinement":
+		return PassModeBoth
+	case "summary":
+		return PassModeFindings
+	default:
+		return PassModeFiles
+	}
+}
+
+// UsesFindings reports whether the pass is given earlier passes' findings
+func (m PassMode) UsesFindings() bool {
+	return m == PassModeFindings || m == PassModeBoth
+}
+
 // Pass represents a single analysis pass in the pipeline
 type Pass struct {
 	Name        string     `json:"name"`
@@ -54,6 +105,20 @@ type Pass struct {
 	StartTime   time.Time  `json:"start_time,omitempty"`
 	EndTime     time.Time  `json:"end_time,omitempty"`
 	Error       string     `json:"error,omitempty"`
+
+	// Mode sets whether the pass sees file content, earlier findings, or both
+	Mode PassMode `json:"mode,omitempty"`
+
+	// Sampling overrides; nil Temperature and zero MaxTokens use the request defaults
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+
+	// Timeout bounds each request of the pass; zero uses the pipeline default
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Token usage and estimated cost (USD) accumulated over the pass
+	Usage         Usage   `json:"usage"`
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
 }
 
 // Pipeline represents the multi-pass analysis workflow
@@ -72,6 +137,14 @@ type PipelineEvent struct {
 	Finding *Finding
 	Message string
 	Error   error
+
+	// PassIndex is the pass's position in the pipeline (from 0)
+	PassIndex int
+
+	// Current and Total count the pass's files: Total is set on pass
+	// started and progress events, Current is how many files are done
+	Current int
+	Total   int
 }
 
 type PipelineEventType string
@@ -82,6 +155,9 @@ const (
 	EventPassCompleted PipelineEventType = "pass_completed"
 	EventPassFailed    PipelineEventType = "pass_failed"
 	EventFindingAdded  PipelineEventType = "finding_added"
+
+	// EventWarning reports a non-fatal problem, such as an unparseable response
+	EventWarning PipelineEventType = "warning"
 )
 
 // FileInfo represents metadata about a single file
@@ -90,16 +166,42 @@ type FileInfo struct {
 	Language string
 	Size     int64
 	Lines    int
+
+	// CodeLines counts lines that are neither blank nor only comments; it
+	// is 0 unless the scanner was set to count code lines
+	CodeLines int
 }
 
 // AnalysisReport is the final output structure
 type AnalysisReport struct {
+	// SchemaVersion is the report shape; Version is the churn-plus release
+	SchemaVersion int `json:"schema_version"`
+
 	Version     string          `json:"version"`
 	Timestamp   time.Time       `json:"timestamp"`
 	Context     *ProjectContext `json:"context"`
 	Findings    []*Finding      `json:"findings"`
 	Summary     ReportSummary   `json:"summary"`
 	Pipeline    []*Pass         `json:"pipeline"`
+
+	// FilesAnalyzed records which files the run covered, capped at MaxFileAnalysisRecords
+	FilesAnalyzed []FileAnalysisRecord `json:"files_analyzed,omitempty"`
+
+	// GitBranch and GitCommit (short SHA) identify the checkout analyzed,
+	// empty outside a git repository; GitBranch is empty on a detached HEAD
+	GitBranch string `json:"git_branch,omitempty"`
+	GitCommit string `json:"git_commit,omitempty"`
+}
+
+// FileAnalysisRecord is an audit entry for a single analyzed file
+type FileAnalysisRecord struct {
+	Path         string    `json:"path"`
+	Language     string    `json:"language"`
+	Lines        int       `json:"lines"`
+	ContentHash  string    `json:"content_hash"` // sha256 of the file content
+	AnalyzedAt   time.Time `json:"analyzed_at"`
+	PassesRun    []string  `json:"passes_run"`
+	FindingCount int       `json:"finding_count"`
 }
 
 // ReportSummary provides aggregate statistics
@@ -109,4 +211,19 @@ type ReportSummary struct {
 	BySeverity    map[Severity]int    `json:"by_severity"`
 	ByKind        map[string]int      `json:"by_kind"`
 	Duration      float64             `json:"duration_seconds"`
+
+	// FailedPasses names the passes that did not complete
+	FailedPasses []string `json:"failed_passes,omitempty"`
+
+	// BaselineSuppressed counts findings dropped because they are in the baseline
+	BaselineSuppressed int `json:"baseline_suppressed,omitempty"`
+
+	// MinSeverity is the lowest severity kept in the report, empty for all;
+	// BelowMinSeverity counts the findings it dropped
+	MinSeverity      Severity `json:"min_severity,omitempty"`
+	BelowMinSeverity int      `json:"below_min_severity,omitempty"`
+
+	// Token usage and estimated cost (USD) across all passes
+	Usage         Usage   `json:"usage"`
+	EstimatedCost float64 `json:"estimated_cost,omitempty"`
 }
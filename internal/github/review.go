@@ -0,0 +1,409 @@
+// Package github posts churn-plus findings to GitHub pull requests as
+// review comments.
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+)
+
+// DefaultAPIURL is the GitHub REST API used when no base URL is set
+const DefaultAPIURL = "https://api.github.com"
+
+// commentMarkerPrefix starts the hidden marker that tags each posted
+// comment with its finding, so later runs don't post it again
+const commentMarkerPrefix = "<!-- churn-plus:finding="
+
+// requestTimeout bounds each GitHub API request, so a stalled connection
+// can't hang a CI job
+const requestTimeout = 60 * time.Second
+
+// maxReviewComments caps the inline comments sent in one review; larger
+// sets are posted as several reviews
+const maxReviewComments = 50
+
+var commentMarkerPattern = regexp.MustCompile(`<!-- churn-plus:finding=([0-9a-f]+) -->`)
+
+// Client talks to the GitHub REST API
+type Client struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a client authenticated with token, e.g. the
+// GITHUB_TOKEN of a workflow run
+func NewClient(token string) *Client {
+	return &Client{
+		token:   token,
+		baseURL: DefaultAPIURL,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// SetBaseURL points the client at another API, e.g. GitHub Enterprise
+// Server's "https://github.example.com/api/v3"
+func (c *Client) SetBaseURL(baseURL string) {
+	if baseURL != "" {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// SetHTTPClient replaces the client used for API requests
+func (c *Client) SetHTTPClient(client *http.Client) {
+	c.client = client
+}
+
+// PullRequest identifies a pull request
+type PullRequest struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// ParsePullRequest builds a PullRequest from "owner/repo" (as in
+// GITHUB_REPOSITORY) and a PR number
+func ParsePullRequest(repository string, number int) (PullRequest, error) {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return PullRequest{}, fmt.Errorf("invalid repository %q (expected owner/repo)", repository)
+	}
+	if number <= 0 {
+		return PullRequest{}, fmt.Errorf("invalid pull request number %d", number)
+	}
+	return PullRequest{Owner: owner, Repo: repo, Number: number}, nil
+}
+
+// ParsePullRequestRef parses a pull request reference of the form
+// "owner/repo#N"
+func ParsePullRequestRef(ref string) (PullRequest, error) {
+	repository, number, ok := strings.Cut(ref, "#")
+	if !ok {
+		return PullRequest{}, fmt.Errorf("invalid pull request %q (expected owner/repo#N)", ref)
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("invalid pull request number %q", number)
+	}
+	return ParsePullRequest(repository, n)
+}
+
+// PostOptions controls how findings are posted
+type PostOptions struct {
+	// RepoRoot is the local checkout the findings' paths are relative to.
+	// Empty uses the report's project root.
+	RepoRoot string
+}
+
+// PostResult counts what PostFindings did with the report's findings
+type PostResult struct {
+	Posted      int // New review comments
+	Duplicates  int // Already posted by an earlier run
+	OutsideDiff int // On lines the pull request doesn't change
+}
+
+// APIError is returned for unsuccessful GitHub API responses
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// PostFindings posts the report's findings as a review on the pull request,
+// one inline comment per finding. Only findings on lines in the PR's diff
+// are posted, and findings already commented on by an earlier run are
+// skipped. More than maxReviewComments comments are split over several
+// reviews.
+func (c *Client) PostFindings(ctx context.Context, pr PullRequest, report *engine.AnalysisReport, opts PostOptions) (PostResult, error) {
+	var result PostResult
+
+	root := opts.RepoRoot
+	if root == "" && report.Context != nil {
+		root = report.Context.RootPath
+	}
+
+	var pull struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := c.get(ctx, c.pullURL(pr, ""), &pull); err != nil {
+		return result, fmt.Errorf("failed to load pull request: %w", err)
+	}
+
+	diffLines, err := c.diffLines(ctx, pr)
+	if err != nil {
+		return result, err
+	}
+
+	posted, err := c.postedMarkers(ctx, pr)
+	if err != nil {
+		return result, err
+	}
+
+	var comments []reviewComment
+	for _, finding := range report.Findings {
+		path := repoPath(root, finding.File)
+		line, ok := commentLine(diffLines[path], finding)
+		if !ok {
+			result.OutsideDiff++
+			continue
+		}
+
+		marker := findingMarker(path, finding)
+		if posted[marker] {
+			result.Duplicates++
+			continue
+		}
+		posted[marker] = true
+
+		comments = append(comments, reviewComment{
+			Path: path,
+			Line: line,
+			Side: "RIGHT",
+			Body: commentBody(finding, marker),
+		})
+	}
+
+	if len(comments) == 0 {
+		return result, nil
+	}
+
+	// One review per batch; a failed batch leaves the earlier ones posted,
+	// and their markers keep the next run from posting them again
+	batches := (len(comments) + maxReviewComments - 1) / maxReviewComments
+	for i := 0; i < batches; i++ {
+		batch := comments[i*maxReviewComments : min((i+1)*maxReviewComments, len(comments))]
+
+		body := fmt.Sprintf("churn-plus found %d new issue%s in this pull request.", len(comments), plural(len(comments)))
+		if batches > 1 {
+			body += fmt.Sprintf(" (part %d of %d)", i+1, batches)
+		}
+
+		review := map[string]interface{}{
+			"commit_id": pull.Head.SHA,
+			"event":     "COMMENT",
+			"body":      body,
+			"comments":  batch,
+		}
+		if err := c.post(ctx, c.pullURL(pr, "/reviews"), review); err != nil {
+			return result, fmt.Errorf("failed to post review: %w", err)
+		}
+		result.Posted += len(batch)
+	}
+
+	return result, nil
+}
+
+// reviewComment is an inline comment of a pull request review
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// diffLines returns, per file, the new-file lines the PR's diff shows,
+// which are the lines GitHub accepts review comments on
+func (c *Client) diffLines(ctx context.Context, pr PullRequest) (map[string]map[int]bool, error) {
+	lines := make(map[string]map[int]bool)
+
+	for page := 1; ; page++ {
+		var files []struct {
+			Filename string `json:"filename"`
+			Patch    string `json:"patch"` // Absent for binary or very large diffs
+		}
+		if err := c.get(ctx, c.pullURL(pr, "/files")+pageQuery(page), &files); err != nil {
+			return nil, fmt.Errorf("failed to list pull request files: %w", err)
+		}
+
+		for _, file := range files {
+			lines[file.Filename] = patchLines(file.Patch)
+		}
+		if len(files) < perPage {
+			return lines, nil
+		}
+	}
+}
+
+// postedMarkers returns the markers of findings already commented on
+func (c *Client) postedMarkers(ctx context.Context, pr PullRequest) (map[string]bool, error) {
+	markers := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		var comments []struct {
+			Body string `json:"body"`
+		}
+		if err := c.get(ctx, c.pullURL(pr, "/comments")+pageQuery(page), &comments); err != nil {
+			return nil, fmt.Errorf("failed to list review comments: %w", err)
+		}
+
+		for _, comment := range comments {
+			for _, match := range commentMarkerPattern.FindAllStringSubmatch(comment.Body, -1) {
+				markers[match[1]] = true
+			}
+		}
+		if len(comments) < perPage {
+			return markers, nil
+		}
+	}
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// patchLines returns the new-file line numbers of a unified diff patch's
+// added and context lines
+func patchLines(patch string) map[int]bool {
+	lines := make(map[int]bool)
+
+	line := 0
+	for _, text := range strings.Split(patch, "\n") {
+		if match := hunkHeaderPattern.FindStringSubmatch(text); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			continue
+		}
+		if line == 0 || text == "" {
+			continue
+		}
+
+		switch text[0] {
+		case '+', ' ':
+			lines[line] = true
+			line++
+		case '-', '\\':
+			// Removed lines and "\ No newline at end of file" have no new line
+		}
+	}
+
+	return lines
+}
+
+// commentLine picks the line to comment on: the finding's first line in the
+// diff, or false when none of its lines are
+func commentLine(diffLines map[int]bool, finding *engine.Finding) (int, bool) {
+	end := max(finding.LineEnd, finding.LineStart)
+	for line := finding.LineStart; line <= end; line++ {
+		if diffLines[line] {
+			return line, true
+		}
+	}
+	return 0, false
+}
+
+// findingMarker identifies a finding across runs. Line numbers are left
+// out so that a finding isn't posted again when edits above it shift it.
+func findingMarker(path string, finding *engine.Finding) string {
+	data := fmt.Sprintf("%s:%s:%s", path, finding.Kind, finding.Message)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(data)))[:16]
+}
+
+// commentBody formats a finding as a review comment
+func commentBody(finding *engine.Finding, marker string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s **%s** · `%s`\n\n%s\n",
+		theme.SeverityIcon(string(finding.Severity)), strings.ToUpper(string(finding.Severity)),
+		finding.Kind, finding.Message))
+	if finding.Code != "" {
+		sb.WriteString("\nSuggested fix:\n\n```\n" + strings.TrimRight(finding.Code, "\n") + "\n```\n")
+	}
+	sb.WriteString("\n" + commentMarkerPrefix + marker + " -->")
+
+	return sb.String()
+}
+
+// repoPath returns a finding's path relative to root with forward slashes,
+// as GitHub names files
+func repoPath(root, path string) string {
+	if root != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// perPage is the page size requested from list endpoints
+const perPage = 100
+
+func pageQuery(page int) string {
+	return fmt.Sprintf("?per_page=%d&page=%d", perPage, page)
+}
+
+func (c *Client) pullURL(pr PullRequest, suffix string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/pulls/%d%s", c.baseURL, pr.Owner, pr.Repo, pr.Number, suffix)
+}
+
+func (c *Client) get(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.do(req, out)
+}
+
+func (c *Client) post(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, nil)
+}
+
+// do sends an authenticated request and decodes the response into out
+func (c *Client) do(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+)
+
+func TestPatchLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  []int
+	}{
+		{"empty", "", nil},
+		{
+			name:  "added and context",
+			patch: "@@ -1,3 +1,4 @@\n a\n+b\n c\n d",
+			want:  []int{1, 2, 3, 4},
+		},
+		{
+			name:  "removed lines have no new line",
+			patch: "@@ -10,3 +10,2 @@\n a\n-b\n c",
+			want:  []int{10, 11},
+		},
+		{
+			name:  "several hunks",
+			patch: "@@ -1,2 +1,2 @@\n a\n+b\n@@ -20 +20,2 @@\n x\n+y",
+			want:  []int{1, 2, 20, 21},
+		},
+		{
+			name:  "no newline marker",
+			patch: "@@ -5,1 +5,1 @@\n-old\n\\ No newline at end of file\n+new\n\\ No newline at end of file",
+			want:  []int{5},
+		},
+		{
+			name:  "lines before the first hunk are ignored",
+			patch: "+stray\n@@ -1 +3 @@\n+a",
+			want:  []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := patchLines(tt.patch)
+			if len(got) != len(tt.want) {
+				t.Fatalf("patchLines() = %v, want lines %v", got, tt.want)
+			}
+			for _, line := range tt.want {
+				if !got[line] {
+					t.Errorf("patchLines() = %v, missing line %d", got, line)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentLine(t *testing.T) {
+	diffLines := map[int]bool{5: true, 6: true, 12: true}
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       int
+		wantOK     bool
+	}{
+		{"first line in diff", 5, 7, 5, true},
+		{"later line in diff", 10, 12, 12, true},
+		{"single line", 6, 0, 6, true},
+		{"outside diff", 7, 11, 0, false},
+		{"single line outside diff", 1, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := &engine.Finding{LineStart: tt.start, LineEnd: tt.end}
+			got, ok := commentLine(diffLines, finding)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("commentLine() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFindingMarker(t *testing.T) {
+	base := &engine.Finding{Kind: "bug", Message: "nil dereference", LineStart: 10, LineEnd: 12}
+	marker := findingMarker("a.go", base)
+
+	if !commentMarkerPattern.MatchString(commentMarkerPrefix + marker + " -->") {
+		t.Fatalf("marker %q doesn't match commentMarkerPattern", marker)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		finding engine.Finding
+		same    bool
+	}{
+		{"moved lines", "a.go", engine.Finding{Kind: "bug", Message: "nil dereference", LineStart: 30, LineEnd: 32}, true},
+		{"other file", "b.go", *base, false},
+		{"other kind", "a.go", engine.Finding{Kind: "perf", Message: "nil dereference"}, false},
+		{"other message", "a.go", engine.Finding{Kind: "bug", Message: "unused variable"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findingMarker(tt.path, &tt.finding); (got == marker) != tt.same {
+				t.Errorf("findingMarker() = %q, base %q, want same = %v", got, marker, tt.same)
+			}
+		})
+	}
+}
+
+func TestPostFindingsBatchesReviews(t *testing.T) {
+	const count = maxReviewComments*2 + 1
+
+	var patch strings.Builder
+	patch.WriteString(fmt.Sprintf("@@ -0,0 +1,%d @@\n", count))
+	var findings []*engine.Finding
+	for i := 1; i <= count; i++ {
+		patch.WriteString("+line\n")
+		findings = append(findings, &engine.Finding{
+			File:      "a.go",
+			LineStart: i,
+			Kind:      "bug",
+			Message:   fmt.Sprintf("issue %d", i),
+		})
+	}
+
+	var reviews []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/reviews"):
+			var review struct {
+				Comments []reviewComment `json:"comments"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+				t.Errorf("decoding review: %v", err)
+			}
+			reviews = append(reviews, len(review.Comments))
+		case strings.HasSuffix(r.URL.Path, "/files"):
+			json.NewEncoder(w).Encode([]map[string]string{{"filename": "a.go", "patch": patch.String()}})
+		case strings.HasSuffix(r.URL.Path, "/comments"):
+			w.Write([]byte("[]"))
+		default:
+			w.Write([]byte(`{"head": {"sha": "abc"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.SetBaseURL(server.URL)
+
+	pr := PullRequest{Owner: "o", Repo: "r", Number: 1}
+	result, err := client.PostFindings(context.Background(), pr, &engine.AnalysisReport{Findings: findings}, PostOptions{})
+	if err != nil {
+		t.Fatalf("PostFindings: %v", err)
+	}
+	if result.Posted != count {
+		t.Errorf("Posted = %d, want %d", result.Posted, count)
+	}
+	if want := []int{maxReviewComments, maxReviewComments, 1}; fmt.Sprint(reviews) != fmt.Sprint(want) {
+		t.Errorf("review sizes = %v, want %v", reviews, want)
+	}
+}
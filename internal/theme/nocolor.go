@@ -0,0 +1,44 @@
+package theme
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+// NoColorEnv disables colors and text attributes when set to any non-empty
+// value (see https://no-color.org)
+const NoColorEnv = "NO_COLOR"
+
+// noColor is set while styles render plain text
+var noColor bool
+
+// DetectNoColor reports whether output should be plain text: NO_COLOR is
+// set or stdout is not a terminal, e.g. when piped to a file
+func DetectNoColor() bool {
+	if os.Getenv(NoColorEnv) != "" {
+		return true
+	}
+
+	fd := os.Stdout.Fd()
+	return !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd)
+}
+
+// SetNoColor switches plain-text rendering on or off. While on, every
+// lipgloss style, including the theme's, renders its text without ANSI
+// escapes; layout such as padding and borders is kept.
+func SetNoColor(enabled bool) {
+	noColor = enabled
+	if enabled {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		return
+	}
+	lipgloss.SetColorProfile(termenv.NewOutput(os.Stdout).EnvColorProfile())
+}
+
+// NoColor reports whether styles render plain text
+func NoColor() bool {
+	return noColor
+}
@@ -1,74 +1,192 @@
 package theme
 
 import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Color palette from original Churn
+// Theme is a named color palette
+type Theme struct {
+	Name string
+
+	// Core colors
+	Background     lipgloss.Color
+	CodeBackground lipgloss.Color
+	PrimaryRed     lipgloss.Color
+	SecondaryRed   lipgloss.Color
+	TextPrimary    lipgloss.Color
+	Muted          lipgloss.Color
+
+	// Status colors
+	Info    lipgloss.Color
+	Success lipgloss.Color
+	Warning lipgloss.Color
+	Error   lipgloss.Color
+}
+
+// DefaultThemeName is the theme used when none or an unknown one is set
+const DefaultThemeName = "default"
+
+// Themes are the available palettes, by name
+var Themes = map[string]Theme{
+	// Dark palette from original Churn
+	DefaultThemeName: {
+		Name:           DefaultThemeName,
+		Background:     "#1b1b1b",
+		CodeBackground: "#0d1117",
+		PrimaryRed:     "#ff5656",
+		SecondaryRed:   "#ff8585",
+		TextPrimary:    "#f2e9e4",
+		Muted:          "#a6adc8",
+		Info:           "#8ab4f8",
+		Success:        "#a6e3a1",
+		Warning:        "#f9e2af",
+		Error:          "#f38ba8",
+	},
+	// For light terminal backgrounds
+	"light": {
+		Name:           "light",
+		Background:     "#fafafa",
+		CodeBackground: "#eef0f2",
+		PrimaryRed:     "#d63c3c",
+		SecondaryRed:   "#e86a6a",
+		TextPrimary:    "#2b2b2b",
+		Muted:          "#5c6370",
+		Info:           "#1f5fbf",
+		Success:        "#2e7d32",
+		Warning:        "#8a6100",
+		Error:          "#c2185b",
+	},
+}
+
+// Colors of the active theme
 var (
 	// Core colors
-	ColorBackground   = lipgloss.Color("#1b1b1b")
-	ColorPrimaryRed   = lipgloss.Color("#ff5656")
-	ColorSecondaryRed = lipgloss.Color("#ff8585")
-	ColorTextPrimary  = lipgloss.Color("#f2e9e4")
-	ColorMuted        = lipgloss.Color("#a6adc8")
+	ColorBackground     lipgloss.Color
+	ColorCodeBackground lipgloss.Color
+	ColorPrimaryRed     lipgloss.Color
+	ColorSecondaryRed   lipgloss.Color
+	ColorTextPrimary    lipgloss.Color
+	ColorMuted          lipgloss.Color
 
 	// Status colors
-	ColorInfo    = lipgloss.Color("#8ab4f8")
-	ColorSuccess = lipgloss.Color("#a6e3a1")
-	ColorWarning = lipgloss.Color("#f9e2af")
-	ColorError   = lipgloss.Color("#f38ba8")
+	ColorInfo    lipgloss.Color
+	ColorSuccess lipgloss.Color
+	ColorWarning lipgloss.Color
+	ColorError   lipgloss.Color
+)
+
+// Base styles of the active theme
+var (
+	BaseStyle      lipgloss.Style
+	TitleStyle     lipgloss.Style
+	HighlightStyle lipgloss.Style
+	MutedStyle     lipgloss.Style
+	SuccessStyle   lipgloss.Style
+	ErrorStyle     lipgloss.Style
+	WarningStyle   lipgloss.Style
+	InfoStyle      lipgloss.Style
 )
 
-// Base styles
+// Pane styles of the active theme
 var (
+	PaneBorderStyle       lipgloss.Style
+	ActivePaneBorderStyle lipgloss.Style
+	PaneTitleStyle        lipgloss.Style
+)
+
+// activeTheme is the name of the applied theme
+var activeTheme string
+
+func init() {
+	applyTheme(Themes[DefaultThemeName])
+}
+
+// SetTheme makes the named theme active. An empty name selects the default;
+// an unknown one also falls back to it and returns an error describing the
+// fallback.
+func SetTheme(name string) error {
+	if name == "" {
+		name = DefaultThemeName
+	}
+
+	t, ok := Themes[name]
+	if !ok {
+		applyTheme(Themes[DefaultThemeName])
+		return fmt.Errorf("unknown theme %q, using %q", name, DefaultThemeName)
+	}
+
+	applyTheme(t)
+	return nil
+}
+
+// ActiveTheme returns the name of the active theme
+func ActiveTheme() string {
+	return activeTheme
+}
+
+// applyTheme sets the palette and rebuilds the styles from it
+func applyTheme(t Theme) {
+	activeTheme = t.Name
+
+	ColorBackground = t.Background
+	ColorCodeBackground = t.CodeBackground
+	ColorPrimaryRed = t.PrimaryRed
+	ColorSecondaryRed = t.SecondaryRed
+	ColorTextPrimary = t.TextPrimary
+	ColorMuted = t.Muted
+	ColorInfo = t.Info
+	ColorSuccess = t.Success
+	ColorWarning = t.Warning
+	ColorError = t.Error
+
 	BaseStyle = lipgloss.NewStyle().
-			Foreground(ColorTextPrimary).
-			Background(ColorBackground)
+		Foreground(ColorTextPrimary).
+		Background(ColorBackground)
 
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimaryRed).
-			Bold(true)
+		Foreground(ColorPrimaryRed).
+		Bold(true)
 
 	HighlightStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimaryRed).
-			Bold(true)
+		Foreground(ColorPrimaryRed).
+		Bold(true)
 
 	MutedStyle = lipgloss.NewStyle().
-			Foreground(ColorMuted)
+		Foreground(ColorMuted)
 
 	SuccessStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess)
+		Foreground(ColorSuccess)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorError)
+		Foreground(ColorError)
 
 	WarningStyle = lipgloss.NewStyle().
-			Foreground(ColorWarning)
+		Foreground(ColorWarning)
 
 	InfoStyle = lipgloss.NewStyle().
-			Foreground(ColorInfo)
-)
+		Foreground(ColorInfo)
 
-// Pane styles
-var (
 	PaneBorderStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(ColorMuted).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(ColorMuted).
+		Padding(0, 1)
 
 	ActivePaneBorderStyle = lipgloss.NewStyle().
-				BorderStyle(lipgloss.RoundedBorder()).
-				BorderForeground(ColorPrimaryRed).
-				Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(ColorPrimaryRed).
+		Padding(0, 1)
 
 	PaneTitleStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimaryRed).
-			Bold(true).
-			Padding(0, 1)
-)
+		Foreground(ColorPrimaryRed).
+		Bold(true).
+		Padding(0, 1)
+}
 
 // ASCII Logo - CHURN in retro pixel style matching original design
 const logoRaw = `
@@ -78,7 +196,9 @@ const logoRaw = `
 ██   ██   ██  ██   ██  ██   ██  ██  ██   ██   ██
  ██████   ██  ██    ████     ██  ██       ██████`
 
-// RedGradient applies a red gradient effect to the logo
+// RedGradient applies a red gradient effect to the logo, interpolating
+// each line's color from primary to secondary red. Terminals without
+// truecolor get primary red for the top half and secondary for the bottom.
 // Ported from original Churn's redGradient function
 func RedGradient(text string) string {
 	lines := strings.Split(text, "\n")
@@ -86,14 +206,20 @@ func RedGradient(text string) string {
 
 	// Create gradient from primary to secondary red
 	totalLines := len(lines)
+	from, fromOK := parseHexColor(ColorPrimaryRed)
+	to, toOK := parseHexColor(ColorSecondaryRed)
+	smooth := !noColor && hasTrueColor() && fromOK && toOK
 
 	for i, line := range lines {
-		// Calculate gradient position (0.0 to 1.0)
-		position := float64(i) / float64(totalLines)
-
-		// Interpolate between primary and secondary red
 		var color lipgloss.Color
-		if position < 0.5 {
+		if smooth {
+			// Position runs from 0.0 on the first line to 1.0 on the last
+			position := 0.0
+			if totalLines > 1 {
+				position = float64(i) / float64(totalLines-1)
+			}
+			color = lerpColor(from, to, position)
+		} else if float64(i)/float64(totalLines) < 0.5 {
 			color = ColorPrimaryRed
 		} else {
 			color = ColorSecondaryRed
@@ -109,6 +235,43 @@ func RedGradient(text string) string {
 	return result.String()
 }
 
+// hasTrueColor reports whether the terminal advertises 24-bit color
+func hasTrueColor() bool {
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return true
+	default:
+		return false
+	}
+}
+
+// rgb is a color's red, green, and blue components
+type rgb [3]uint8
+
+// parseHexColor parses a "#rrggbb" color
+func parseHexColor(color lipgloss.Color) (rgb, bool) {
+	hex := strings.TrimPrefix(string(color), "#")
+	if len(hex) != 6 {
+		return rgb{}, false
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgb{}, false
+	}
+	return rgb{uint8(value >> 16), uint8(value >> 8), uint8(value)}, true
+}
+
+// lerpColor returns the color position of the way from a to b, where
+// position runs from 0.0 (a) to 1.0 (b)
+func lerpColor(a, b rgb, position float64) lipgloss.Color {
+	var mixed rgb
+	for i := range mixed {
+		mixed[i] = uint8(math.Round(float64(a[i]) + (float64(b[i])-float64(a[i]))*position))
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", mixed[0], mixed[1], mixed[2]))
+}
+
 // RenderLogo returns the styled ASCII logo
 func RenderLogo() string {
 	return RedGradient(logoRaw)
@@ -1,11 +1,19 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/cloudboy-jh/churn-plus/internal/config"
 	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+	"github.com/cloudboy-jh/churn-plus/internal/ui/help"
 	"github.com/cloudboy-jh/churn-plus/internal/ui/menu"
 	"github.com/cloudboy-jh/churn-plus/internal/ui/tui"
 )
@@ -17,12 +25,59 @@ const (
 	StateMenu AppState = iota
 	StateModelSelect
 	StateSettings
+	StatePipeline
 	StateTUI
 	StateLLMModal
 	StatePatchPreview
 	StateConfirmation
+	StateAnalyzing
+	StateCompare
 )
 
+// analysisCompleteMsg is sent when a background analysis run finishes
+type analysisCompleteMsg struct {
+	runID     int
+	report    *engine.AnalysisReport
+	scanStats engine.ScanStats
+	err       error
+}
+
+// analysisStream carries the pipeline events of a running analysis and,
+// once they stop, its result
+type analysisStream struct {
+	runID  int
+	events <-chan engine.PipelineEvent
+	done   <-chan analysisCompleteMsg
+}
+
+// analysisStartedMsg is sent once the project is scanned and the pipeline
+// starts running
+type analysisStartedMsg struct {
+	stream analysisStream
+
+	// passes is a copy of the pipeline's passes from before it ran, so the
+	// UI never reads them while the orchestrator writes
+	passes []engine.Pass
+}
+
+// analysisEventMsg carries a pipeline event of a running analysis
+type analysisEventMsg struct {
+	stream analysisStream
+	event  engine.PipelineEvent
+}
+
+// next returns a command that yields the stream's next event, or the run's
+// result once every event has been delivered and the channel is closed
+func (s analysisStream) next() tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-s.events
+		if !ok {
+			return <-s.done
+		}
+		return analysisEventMsg{stream: s, event: event}
+	}
+}
+
 // AppModel is the root BubbleTea model
 type AppModel struct {
 	state       AppState
@@ -33,12 +88,25 @@ type AppModel struct {
 	menuModel        *menu.MenuModel
 	modelSelectModel *menu.ModelSelectModel
 	settingsModel    *menu.SettingsModel
+	pipelineModel    *menu.PipelineModel
+	compareModel     *menu.CompareModel
 	tuiModel         *tui.Model
 
 	// Window dimensions
 	width  int
 	height int
 
+	// Help overlay for the menu screens; the TUI manages its own
+	showHelp bool
+
+	// The running analysis: cancelAnalysis stops it, and results from runs
+	// other than analysisRunID (e.g. a cancelled one) are ignored
+	cancelAnalysis context.CancelFunc
+	analysisRunID  int
+
+	// analysisWarnings counts the running analysis's unparseable responses
+	analysisWarnings int
+
 	// Error handling
 	err error
 }
@@ -47,7 +115,8 @@ type AppModel struct {
 func NewAppModel(projectRoot string) AppModel {
 	// Load configuration
 	cfg, err := config.Load(projectRoot)
-	if err != nil {
+	var validationErrs config.ValidationErrors
+	if err != nil && !errors.As(err, &validationErrs) {
 		// If config fails to load, create default
 		cfg = &config.Config{
 			Global:  config.DefaultGlobalConfig(),
@@ -55,11 +124,22 @@ func NewAppModel(projectRoot string) AppModel {
 		}
 	}
 
+	// An unknown theme falls back to the default with a warning
+	if themeErr := theme.SetTheme(cfg.Global.UI.Theme); themeErr != nil {
+		err = errors.Join(err, themeErr)
+	}
+
+	// Load problems are shown on the menu rather than failing later
+	menuModel := menu.NewMenuModel(projectRoot)
+	if err != nil {
+		menuModel.SetError(err)
+	}
+
 	return AppModel{
 		state:       StateMenu,
 		projectRoot: projectRoot,
 		config:      cfg,
-		menuModel:   menu.NewMenuModel(projectRoot),
+		menuModel:   menuModel,
 		err:         nil,
 	}
 }
@@ -76,10 +156,23 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Update all sub-models with window size
+		// Update all sub-models with window size, including ones not on
+		// screen, so none renders at a stale size when returned to
 		if m.menuModel != nil {
 			m.menuModel.SetSize(msg.Width, msg.Height)
 		}
+		if m.modelSelectModel != nil {
+			m.modelSelectModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.settingsModel != nil {
+			m.settingsModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.pipelineModel != nil {
+			m.pipelineModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.compareModel != nil {
+			m.compareModel.SetSize(msg.Width, msg.Height)
+		}
 		if m.tuiModel != nil {
 			m.tuiModel.SetSize(msg.Width, msg.Height)
 		}
@@ -92,6 +185,30 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		if m.showHelp {
+			if help.IsCloseKey(msg.String()) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if msg.String() == help.ToggleKey && m.menuHelpSections() != nil && !m.editingText() {
+			m.showHelp = true
+			return m, nil
+		}
+
+		if m.state == StateAnalyzing && (msg.String() == "esc" || msg.String() == "ctrl+x") {
+			m.stopAnalysis()
+			m.state = StateMenu
+			return m, nil
+		}
+
+		// Findings stream into the TUI while the analysis runs
+		if m.state == StateTUI && m.cancelAnalysis != nil && msg.String() == "ctrl+x" {
+			m.stopAnalysis()
+			m.state = StateMenu
+			return m, nil
+		}
+
 	case menu.MenuSelectionMsg:
 		// Handle menu selection
 		return m.handleMenuSelection(msg)
@@ -102,9 +219,94 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tui.BackToMenuMsg:
-		// Return to main menu from TUI
+		// Return to main menu from TUI, abandoning a run still in progress
+		if m.cancelAnalysis != nil {
+			m.stopAnalysis()
+		}
 		m.state = StateMenu
 		return m, nil
+
+	case tui.ReanalyzeMsg:
+		m.state = StateAnalyzing
+		return m, m.startAnalysis()
+
+	case analysisStartedMsg:
+		if msg.stream.runID != m.analysisRunID {
+			// Keep draining a cancelled run so its pipeline can wind down
+			return m, msg.stream.next()
+		}
+
+		// Show pass progress and findings as the pipeline reports them
+		m.tuiModel = tui.NewModel(m.projectRoot, nil, m.config)
+		m.tuiModel.SetPasses(msg.passes)
+		m.tuiModel.SetRunning(true)
+		m.tuiModel.SetSize(m.width, m.height)
+		m.state = StateTUI
+		return m, tea.Batch(m.tuiModel.Init(), msg.stream.next())
+
+	case analysisEventMsg:
+		if msg.stream.runID == m.analysisRunID && m.tuiModel != nil {
+			if msg.event.Type == engine.EventWarning && isParseWarning(msg.event.Error) {
+				m.analysisWarnings++
+			}
+			m.tuiModel.HandleEvent(msg.event)
+		}
+		return m, msg.stream.next()
+
+	case analysisCompleteMsg:
+		if msg.runID != m.analysisRunID {
+			// Result of a cancelled run
+			return m, nil
+		}
+		m.cancelAnalysis = nil
+
+		// Failures, such as a provider that fails its pre-run check or a
+		// missing API key, are reported on the menu so they can be fixed
+		// without restarting
+		if msg.err != nil {
+			err := fmt.Errorf("analysis failed: %w", msg.err)
+			var checkErr *engine.ProviderCheckError
+			if errors.As(msg.err, &checkErr) {
+				err = checkErr
+			}
+			m.menuModel.SetError(err)
+			m.state = StateMenu
+			return m, nil
+		}
+
+		// The report's findings are deduplicated, filtered and sorted, so
+		// they replace the ones streamed in during the run
+		m.tuiModel.SetFindings(msg.report.Findings)
+		m.tuiModel.SetRunning(false)
+		m.tuiModel.SetFileHashes(msg.report.FileHashes())
+		banner := msg.scanStats.String()
+		if failed := msg.report.Summary.FailedPasses; len(failed) > 0 {
+			if banner != "" {
+				banner += ", "
+			}
+			banner += "failed passes: " + strings.Join(failed, ", ")
+		}
+		if suppressed := msg.report.Summary.BaselineSuppressed; suppressed > 0 {
+			if banner != "" {
+				banner += ", "
+			}
+			banner += fmt.Sprintf("%d baseline findings hidden", suppressed)
+		}
+		if belowMin := msg.report.Summary.BelowMinSeverity; belowMin > 0 {
+			if banner != "" {
+				banner += ", "
+			}
+			banner += fmt.Sprintf("%d findings below %s hidden", belowMin, msg.report.Summary.MinSeverity)
+		}
+		if m.analysisWarnings > 0 {
+			if banner != "" {
+				banner += ", "
+			}
+			banner += fmt.Sprintf("%d responses could not be parsed", m.analysisWarnings)
+		}
+		m.tuiModel.SetBanner(banner)
+		m.state = StateTUI
+		return m, nil
 	}
 
 	// Delegate to current state's sub-model
@@ -113,10 +315,26 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the current state
 func (m AppModel) View() string {
+	view := m.render()
+	if theme.NoColor() {
+		// Styles already render plain text, but lipgloss still wraps borders
+		// in empty escape sequences
+		return ansi.Strip(view)
+	}
+	return view
+}
+
+// render renders the current state's view
+func (m AppModel) render() string {
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\n\nPress Ctrl+C to quit", m.err)
 	}
 
+	if m.showHelp {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			help.Render(m.menuHelpSections()))
+	}
+
 	switch m.state {
 	case StateMenu:
 		if m.menuModel != nil {
@@ -136,30 +354,122 @@ func (m AppModel) View() string {
 		}
 		return "Loading settings..."
 
+	case StatePipeline:
+		if m.pipelineModel != nil {
+			return m.pipelineModel.View()
+		}
+		return "Loading pipeline..."
+
+	case StateCompare:
+		if m.compareModel != nil {
+			return m.compareModel.View()
+		}
+		return "Loading report comparison..."
+
 	case StateTUI:
 		if m.tuiModel != nil {
 			return m.tuiModel.View()
 		}
 		return "Loading TUI..."
 
+	case StateAnalyzing:
+		return fmt.Sprintf("Analyzing %s...\n\nPress Esc to cancel, Ctrl+C to quit", m.projectRoot)
+
 	default:
 		return "Unknown state"
 	}
 }
 
+// menuHelpSections lists the keybindings for the current menu screen, or nil
+// for states that handle help themselves or have none
+func (m AppModel) menuHelpSections() []help.Section {
+	general := help.Section{Title: "General", Bindings: []help.Binding{
+		{Key: "?", Description: "Toggle this help"},
+		{Key: "ctrl+c", Description: "Quit"},
+	}}
+
+	switch m.state {
+	case StateMenu:
+		return []help.Section{{Title: "Main Menu", Bindings: []help.Binding{
+			{Key: "↑/↓", Description: "Navigate"},
+			{Key: "enter", Description: "Select"},
+			{Key: "q/esc", Description: "Jump to exit, press again to quit"},
+		}}, general}
+
+	case StateModelSelect:
+		return []help.Section{{Title: "Model Select", Bindings: []help.Binding{
+			{Key: "↑/↓", Description: "Navigate"},
+			{Key: "enter", Description: "Choose provider, then model"},
+			{Key: "q/esc", Description: "Back to menu"},
+		}}, general}
+
+	case StateSettings:
+		return []help.Section{{Title: "Settings", Bindings: []help.Binding{
+			{Key: "a", Description: "Edit API keys"},
+			{Key: "c", Description: "Edit concurrency limits"},
+			{Key: "h", Description: "Edit cache settings or clear the cache"},
+			{Key: "p", Description: "Switch profile"},
+			{Key: "k", Description: "Move API keys to the OS keyring"},
+			{Key: "q/esc/enter", Description: "Back to menu"},
+		}}, general}
+
+	case StatePipeline:
+		return []help.Section{{Title: "Pipeline", Bindings: []help.Binding{
+			{Key: "↑/↓", Description: "Navigate"},
+			{Key: "space/enter", Description: "Toggle pass, or save"},
+			{Key: "e", Description: "Edit pass"},
+			{Key: "a/d", Description: "Add/delete pass"},
+			{Key: "shift+↑/↓", Description: "Reorder pass"},
+			{Key: "q/esc", Description: "Back to menu without saving"},
+		}}, general}
+
+	case StateCompare:
+		return []help.Section{{Title: "Compare Reports", Bindings: []help.Binding{
+			{Key: "↑/↓ j/k", Description: "Scroll"},
+			{Key: "q/esc/enter", Description: "Back to menu"},
+		}}, general}
+	}
+
+	return nil
+}
+
+// editingText reports whether the current menu screen has a text input
+// focused, so keys like ? are typed rather than handled
+func (m AppModel) editingText() bool {
+	switch m.state {
+	case StateSettings:
+		return m.settingsModel != nil && m.settingsModel.Editing()
+	case StatePipeline:
+		return m.pipelineModel != nil && m.pipelineModel.Editing()
+	}
+	return false
+}
+
 // handleMenuSelection processes menu selections and transitions states
 func (m AppModel) handleMenuSelection(msg menu.MenuSelectionMsg) (AppModel, tea.Cmd) {
 	switch msg.Selection {
 	case menu.MenuOptionStart:
 		// Load findings and transition to TUI
-		findings, err := m.loadFindings()
+		findings, report, err := m.loadFindings()
 		if err != nil {
 			m.err = fmt.Errorf("failed to load findings: %w", err)
 			return m, nil
 		}
 
+		// Without a usable report there is nothing to show yet; analyze the
+		// project instead of opening an empty list
+		if report == nil {
+			m.state = StateAnalyzing
+			return m, m.startAnalysis()
+		}
+
 		// Create TUI model
 		m.tuiModel = tui.NewModel(m.projectRoot, findings, m.config)
+		m.tuiModel.SetFileHashes(report.FileHashes())
+		m.tuiModel.SetBanner(fmt.Sprintf(
+			"Showing cached findings from %s. Press ctrl+r to re-analyze.",
+			report.Timestamp.Format("2006-01-02 15:04:05"),
+		))
 		m.tuiModel.SetSize(m.width, m.height)
 		m.state = StateTUI
 
@@ -167,11 +477,18 @@ func (m AppModel) handleMenuSelection(msg menu.MenuSelectionMsg) (AppModel, tea.
 
 	case menu.MenuOptionModelSelect:
 		// Create model select model
-		m.modelSelectModel = menu.NewModelSelectModel(m.config)
+		m.modelSelectModel = menu.NewModelSelectModel(m.config, m.projectRoot)
 		m.modelSelectModel.SetSize(m.width, m.height)
 		m.state = StateModelSelect
 		return m, nil
 
+	case menu.MenuOptionPipeline:
+		// Create pipeline editor
+		m.pipelineModel = menu.NewPipelineModel(m.config, m.projectRoot)
+		m.pipelineModel.SetSize(m.width, m.height)
+		m.state = StatePipeline
+		return m, nil
+
 	case menu.MenuOptionSettings:
 		// Create settings model
 		m.settingsModel = menu.NewSettingsModel(m.config, m.projectRoot)
@@ -179,6 +496,13 @@ func (m AppModel) handleMenuSelection(msg menu.MenuSelectionMsg) (AppModel, tea.
 		m.state = StateSettings
 		return m, nil
 
+	case menu.MenuOptionCompareReports:
+		// Compare the two most recent reports
+		m.compareModel = menu.NewCompareModel(m.projectRoot)
+		m.compareModel.SetSize(m.width, m.height)
+		m.state = StateCompare
+		return m, nil
+
 	case menu.MenuOptionExit:
 		return m, tea.Quit
 	}
@@ -212,6 +536,20 @@ func (m AppModel) updateCurrentState(msg tea.Msg) (AppModel, tea.Cmd) {
 			cmd = sCmd
 		}
 
+	case StatePipeline:
+		if m.pipelineModel != nil {
+			updatedPipeline, pCmd := m.pipelineModel.Update(msg)
+			m.pipelineModel = updatedPipeline
+			cmd = pCmd
+		}
+
+	case StateCompare:
+		if m.compareModel != nil {
+			updatedCompare, cCmd := m.compareModel.Update(msg)
+			m.compareModel = updatedCompare
+			cmd = cCmd
+		}
+
 	case StateTUI:
 		if m.tuiModel != nil {
 			updatedTUI, tCmd := m.tuiModel.Update(msg)
@@ -223,25 +561,113 @@ func (m AppModel) updateCurrentState(msg tea.Msg) (AppModel, tea.Cmd) {
 	return m, cmd
 }
 
-// loadFindings loads findings from the most recent report
-func (m AppModel) loadFindings() ([]*engine.Finding, error) {
-	// List all reports
-	reports, err := engine.ListReports(m.projectRoot)
+// loadFindings pre-loads findings from the most recent report if it is
+// still within the cache TTL. The report is returned alongside its findings
+// so callers can show its timestamp; it is nil when nothing was loaded.
+func (m AppModel) loadFindings() ([]*engine.Finding, *engine.AnalysisReport, error) {
+	latestReport, err := engine.LatestReport(m.projectRoot)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if len(reports) == 0 {
+	if latestReport == "" {
 		// No reports found, return empty slice
-		return []*engine.Finding{}, nil
+		return []*engine.Finding{}, nil, nil
 	}
 
-	// Load the most recent report (last in list)
-	latestReport := reports[len(reports)-1]
 	report, err := engine.LoadReport(latestReport)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Stale reports are not shown; the user re-analyzes instead
+	ttl := time.Duration(m.config.GetCacheSettings().TTL) * time.Hour
+	if time.Since(report.Timestamp) > ttl {
+		return []*engine.Finding{}, nil, nil
+	}
+
+	// Honor the current threshold, which may be stricter than the report's
+	factory := engine.NewFactory(m.config)
+	findings, _ := engine.FilterBySeverity(report.Findings, factory.MinSeverity())
+
+	aggregator := engine.NewFindingsAggregator()
+	aggregator.SetOverlapThreshold(factory.DedupOverlap())
+	aggregator.AddMultiple(findings)
+	aggregator.Sort()
+
+	return aggregator.GetAll(), report, nil
+}
+
+// startAnalysis scans the project and runs the configured pipeline in the
+// background, saving the resulting report. Once the pipeline starts, its
+// events are read through an analysisStream until the run finishes.
+func (m *AppModel) startAnalysis() tea.Cmd {
+	factory := engine.NewFactory(m.config)
+	projectRoot := m.projectRoot
+
+	m.stopAnalysis()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelAnalysis = cancel
+	m.analysisWarnings = 0
+	runID := m.analysisRunID
+
+	return func() tea.Msg {
+		run, err := factory.PrepareRun(projectRoot)
+		if err != nil {
+			return analysisCompleteMsg{runID: runID, err: err}
+		}
+		if ctx.Err() != nil {
+			// Cancelled while scanning; never start sending requests
+			run.Close()
+			return analysisCompleteMsg{runID: runID, err: ctx.Err()}
+		}
+
+		passes := make([]engine.Pass, 0, len(run.Orchestrator.GetPipeline().Passes))
+		for _, pass := range run.Orchestrator.GetPipeline().Passes {
+			passes = append(passes, *pass)
+		}
+
+		done := make(chan analysisCompleteMsg, 1)
+		go func() {
+			defer run.Close()
+
+			// Pick up where an interrupted run left off
+			execute := run.Execute
+			if engine.HasCheckpoint(projectRoot) {
+				execute = run.Resume
+			}
+			if err := execute(ctx); err != nil {
+				done <- analysisCompleteMsg{runID: runID, err: err}
+				return
+			}
+
+			report, err := run.Finish()
+			done <- analysisCompleteMsg{runID: runID, report: report, scanStats: run.ScanStats, err: err}
+		}()
+
+		return analysisStartedMsg{
+			stream: analysisStream{
+				runID:  runID,
+				events: run.Orchestrator.Events(),
+				done:   done,
+			},
+			passes: passes,
+		}
 	}
+}
 
-	return report.Findings, nil
+// isParseWarning reports whether a pipeline warning is about an unparseable
+// response rather than a fallback the run recovered from
+func isParseWarning(err error) bool {
+	return !errors.Is(err, engine.ErrStructuredOutputUnsupported) && !errors.Is(err, engine.ErrCheckpointUnavailable)
+}
+
+// stopAnalysis cancels the running analysis, if any, so in-flight requests
+// are aborted and no more files are sent. Its result will be ignored.
+func (m *AppModel) stopAnalysis() {
+	if m.cancelAnalysis != nil {
+		m.cancelAnalysis()
+		m.cancelAnalysis = nil
+	}
+	m.analysisRunID++
 }
@@ -0,0 +1,72 @@
+package help
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+)
+
+// ToggleKey opens and closes the help overlay on every screen
+const ToggleKey = "?"
+
+// Binding describes what a key does
+type Binding struct {
+	Key         string
+	Description string
+}
+
+// Section groups related bindings under a heading
+type Section struct {
+	Title    string
+	Bindings []Binding
+}
+
+// IsCloseKey reports whether key dismisses an open help overlay
+func IsCloseKey(key string) bool {
+	return key == ToggleKey || key == "esc" || key == "q"
+}
+
+// Render draws the help overlay box listing the given sections
+func Render(sections []Section) string {
+	keyWidth := 0
+	for _, section := range sections {
+		for _, binding := range section.Bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.Key))
+		}
+	}
+
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true).
+		Width(keyWidth + 2)
+
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Bold(true)
+
+	var b strings.Builder
+	b.WriteString(theme.TitleStyle.Render("KEYBOARD SHORTCUTS"))
+	b.WriteString("\n")
+
+	for _, section := range sections {
+		b.WriteString("\n")
+		b.WriteString(sectionStyle.Render(section.Title))
+		b.WriteString("\n")
+		for _, binding := range section.Bindings {
+			b.WriteString(fmt.Sprintf("  %s%s\n", keyStyle.Render(binding.Key), binding.Description))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(theme.MutedStyle.Render("?/esc: close help"))
+
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Padding(1, 2)
+
+	return boxStyle.Render(b.String())
+}
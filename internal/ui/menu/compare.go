@@ -0,0 +1,229 @@
+package menu
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+)
+
+// CompareModel shows which findings were fixed and which are new between
+// the two most recent reports
+type CompareModel struct {
+	projectRoot string
+	oldReport   *engine.AnalysisReport
+	newReport   *engine.AnalysisReport
+	diff        *engine.ReportDiff
+	err         error
+
+	// Rendered rows and scroll position
+	rows   []string
+	offset int
+
+	width  int
+	height int
+}
+
+// NewCompareModel loads the two most recent reports and compares them
+func NewCompareModel(projectRoot string) *CompareModel {
+	m := &CompareModel{projectRoot: projectRoot}
+
+	reports, err := engine.ListReports(projectRoot)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	if len(reports) < 2 {
+		m.err = fmt.Errorf("at least two reports are needed to compare, found %d", len(reports))
+		return m
+	}
+
+	m.oldReport, err = engine.LoadReport(reports[len(reports)-2])
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.newReport, err = engine.LoadReport(reports[len(reports)-1])
+	if err != nil {
+		m.err = err
+		return m
+	}
+
+	m.diff = engine.CompareReports(m.oldReport, m.newReport)
+	m.rows = m.buildRows()
+
+	return m
+}
+
+// SetSize sets the compare view dimensions
+func (m *CompareModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Init initializes the compare view
+func (m *CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages
+func (m *CompareModel) Update(msg tea.Msg) (*CompareModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "enter":
+			return m, func() tea.Msg {
+				return BackToMenuMsg{}
+			}
+
+		case "up", "k":
+			if m.offset > 0 {
+				m.offset--
+			}
+
+		case "down", "j":
+			if m.offset < len(m.rows)-m.visibleRows() {
+				m.offset++
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the comparison
+func (m *CompareModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n\n")
+	title := theme.TitleStyle.Render("COMPARE REPORTS")
+	b.WriteString(centerText(title, m.width))
+	b.WriteString("\n\n")
+
+	var content string
+	if m.err != nil {
+		content = theme.ErrorStyle.Render(fmt.Sprintf("Cannot compare reports: %v", m.err))
+	} else {
+		content = m.renderComparison()
+	}
+	b.WriteString(centerText(m.renderBox(content), m.width))
+	b.WriteString("\n\n")
+
+	helpText := theme.MutedStyle.Render("↑/↓: scroll | Press 'q' or Enter to go back to menu")
+	b.WriteString(centerText(helpText, m.width))
+
+	return b.String()
+}
+
+// reportLabel names a report by its time and, when known, its git revision
+func reportLabel(report *engine.AnalysisReport) string {
+	label := report.Timestamp.Format("2006-01-02 15:04:05")
+	if revision := report.Revision(); revision != "" {
+		label += " (" + revision + ")"
+	}
+	return label
+}
+
+// renderComparison renders the summary line and the visible finding rows
+func (m *CompareModel) renderComparison() string {
+	var items []string
+
+	items = append(items, theme.MutedStyle.Render(fmt.Sprintf("%s → %s",
+		reportLabel(m.oldReport), reportLabel(m.newReport))))
+	items = append(items, fmt.Sprintf("%s  %s  %s",
+		theme.SuccessStyle.Render(fmt.Sprintf("%d fixed", len(m.diff.Removed))),
+		theme.ErrorStyle.Render(fmt.Sprintf("%d new", len(m.diff.Added))),
+		theme.MutedStyle.Render(fmt.Sprintf("%d unchanged", len(m.diff.Unchanged)))))
+	items = append(items, "")
+
+	end := m.offset + m.visibleRows()
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	items = append(items, m.rows[m.offset:end]...)
+
+	contentStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Padding(0, 2)
+
+	return contentStyle.Render(strings.Join(items, "\n"))
+}
+
+// buildRows lists new findings first, then fixed ones
+func (m *CompareModel) buildRows() []string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true)
+
+	var rows []string
+
+	rows = append(rows, labelStyle.Render("New findings:"))
+	if len(m.diff.Added) == 0 {
+		rows = append(rows, theme.MutedStyle.Render("  none"))
+	}
+	for _, finding := range m.diff.Added {
+		rows = append(rows, "  "+theme.ErrorStyle.Render("+ ")+m.formatFinding(finding))
+	}
+
+	rows = append(rows, "")
+	rows = append(rows, labelStyle.Render("Fixed findings:"))
+	if len(m.diff.Removed) == 0 {
+		rows = append(rows, theme.MutedStyle.Render("  none"))
+	}
+	for _, finding := range m.diff.Removed {
+		rows = append(rows, "  "+theme.SuccessStyle.Render("- ")+m.formatFinding(finding))
+	}
+
+	return rows
+}
+
+// formatFinding renders a finding as a single row
+func (m *CompareModel) formatFinding(finding *engine.Finding) string {
+	path := finding.File
+	if rel, err := filepath.Rel(m.projectRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+
+	message := finding.Message
+	if len(message) > 60 {
+		message = message[:57] + "..."
+	}
+
+	return fmt.Sprintf("%s %s:%d %s",
+		theme.SeverityIcon(string(finding.Severity)), path, finding.LineStart, message)
+}
+
+// visibleRows returns how many finding rows fit on screen
+func (m *CompareModel) visibleRows() int {
+	// Title, summary, borders and help text take the rest
+	rows := m.height - 16
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// renderBox renders content in a box
+func (m *CompareModel) renderBox(content string) string {
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		BorderBackground(lipgloss.Color(theme.ColorBackground)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Padding(1, 0).
+		Width(90)
+
+	title := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true).
+		Render(" Latest vs Previous Report ")
+
+	fullContent := title + "\n" + content
+
+	return boxStyle.Render(fullContent)
+}
@@ -18,7 +18,9 @@ type MenuOption int
 const (
 	MenuOptionStart MenuOption = iota
 	MenuOptionModelSelect
+	MenuOptionPipeline
 	MenuOptionSettings
+	MenuOptionCompareReports
 	MenuOptionExit
 )
 
@@ -42,7 +44,11 @@ type MenuModel struct {
 	latestReport  string
 	findingsCount int
 	lastRunTime   time.Time
+	lastRevision  string // Branch and commit the report was made from
 	hasReport     bool
+
+	// err is shown above the menu until the next selection
+	err error
 }
 
 type menuItem struct {
@@ -55,7 +61,9 @@ func NewMenuModel(projectRoot string) *MenuModel {
 	options := []menuItem{
 		{label: "START ANALYSIS", option: MenuOptionStart},
 		{label: "MODEL SELECT", option: MenuOptionModelSelect},
+		{label: "CONFIGURE PIPELINE", option: MenuOptionPipeline},
 		{label: "SETTINGS", option: MenuOptionSettings},
+		{label: "COMPARE REPORTS", option: MenuOptionCompareReports},
 		{label: "EXIT", option: MenuOptionExit},
 	}
 
@@ -77,6 +85,11 @@ func (m *MenuModel) SetSize(width, height int) {
 	m.height = height
 }
 
+// SetError shows err above the menu until the next selection
+func (m *MenuModel) SetError(err error) {
+	m.err = err
+}
+
 // Init initializes the menu
 func (m *MenuModel) Init() tea.Cmd {
 	return nil
@@ -106,6 +119,7 @@ func (m *MenuModel) Update(msg tea.Msg) (*MenuModel, tea.Cmd) {
 
 		case "enter":
 			// Send selection message
+			m.err = nil
 			selectedOption := m.options[m.selected].option
 			return m, func() tea.Msg {
 				return MenuSelectionMsg{Selection: selectedOption}
@@ -133,9 +147,14 @@ func (m *MenuModel) View() string {
 
 	// Render latest report info
 	if m.hasReport {
+		revision := ""
+		if m.lastRevision != "" {
+			revision = " on " + m.lastRevision
+		}
 		reportInfo := theme.MutedStyle.Render(fmt.Sprintf(
-			"Latest Report: %s (%d findings)",
+			"Latest Report: %s%s (%d findings)",
 			m.lastRunTime.Format("2006-01-02 15:04:05"),
+			revision,
 			m.findingsCount,
 		))
 		b.WriteString(centerText(reportInfo, m.width))
@@ -145,6 +164,11 @@ func (m *MenuModel) View() string {
 	}
 	b.WriteString("\n\n")
 
+	if m.err != nil {
+		b.WriteString(centerText(theme.ErrorStyle.Render("Error: "+m.err.Error()), m.width))
+		b.WriteString("\n\n")
+	}
+
 	// Render menu box
 	menuContent := m.renderMenuItems()
 	menuBox := m.renderMenuBox(menuContent)
@@ -152,7 +176,7 @@ func (m *MenuModel) View() string {
 	b.WriteString("\n\n")
 
 	// Render help text
-	helpText := theme.MutedStyle.Render("↑/↓: navigate | Enter: select | q: quit")
+	helpText := theme.MutedStyle.Render("↑/↓: navigate | Enter: select | ?: help | q: quit")
 	b.WriteString(centerText(helpText, m.width))
 
 	// Add padding to fill screen
@@ -222,14 +246,12 @@ func (m *MenuModel) renderMenuBox(content string) string {
 
 // loadReportInfo loads information about the latest report
 func (m *MenuModel) loadReportInfo() {
-	reports, err := engine.ListReports(m.projectRoot)
-	if err != nil || len(reports) == 0 {
+	latestReport, err := engine.LatestReport(m.projectRoot)
+	if err != nil || latestReport == "" {
 		m.hasReport = false
 		return
 	}
 
-	// Load the most recent report
-	latestReport := reports[len(reports)-1]
 	report, err := engine.LoadReport(latestReport)
 	if err != nil {
 		m.hasReport = false
@@ -239,52 +261,24 @@ func (m *MenuModel) loadReportInfo() {
 	m.latestReport = latestReport
 	m.findingsCount = len(report.Findings)
 	m.lastRunTime = report.Timestamp
+	m.lastRevision = report.Revision()
 	m.hasReport = true
 }
 
-// centerText centers text horizontally
+// centerText centers text horizontally. Multi-line text is centered as a
+// block by its widest line, so lines keep their alignment to each other.
 func centerText(text string, width int) string {
-	lines := strings.Split(text, "\n")
-	var centered []string
-
-	for _, line := range lines {
-		// Remove ANSI codes for length calculation
-		cleanLine := stripAnsi(line)
-		lineLen := len(cleanLine)
-
-		if lineLen >= width {
-			centered = append(centered, line)
-			continue
-		}
-
-		padding := (width - lineLen) / 2
-		centeredLine := strings.Repeat(" ", padding) + line
-		centered = append(centered, centeredLine)
+	// Display width ignores ANSI codes and counts wide runes as two columns
+	textWidth := lipgloss.Width(text)
+	if textWidth >= width {
+		return text
 	}
 
-	return strings.Join(centered, "\n")
-}
-
-// stripAnsi removes ANSI escape codes for length calculation
-func stripAnsi(str string) string {
-	// Simple ANSI stripper for length calculation
-	// This is a basic implementation
-	var result strings.Builder
-	inEscape := false
-
-	for _, r := range str {
-		if r == '\x1b' {
-			inEscape = true
-			continue
-		}
-		if inEscape {
-			if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') {
-				inEscape = false
-			}
-			continue
-		}
-		result.WriteRune(r)
+	padding := strings.Repeat(" ", (width-textWidth)/2)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = padding + line
 	}
 
-	return result.String()
+	return strings.Join(lines, "\n")
 }
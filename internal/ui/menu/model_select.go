@@ -2,11 +2,14 @@ package menu
 
 import (
 	"context"
+	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cloudboy-jh/churn-plus/internal/config"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
 	"github.com/cloudboy-jh/churn-plus/internal/engine/providers"
 	"github.com/cloudboy-jh/churn-plus/internal/theme"
 )
@@ -21,11 +24,12 @@ const (
 
 // ModelSelectModel handles model selection
 type ModelSelectModel struct {
-	config   *config.Config
-	step     ModelSelectStep
-	selected int
-	width    int
-	height   int
+	config      *config.Config
+	projectRoot string
+	step        ModelSelectStep
+	selected    int
+	width       int
+	height      int
 
 	// Provider selection
 	providers []providerOption
@@ -34,6 +38,10 @@ type ModelSelectModel struct {
 	models           []string
 	selectedProvider string
 	loadingModels    bool
+	loadErr          error
+
+	// Set when the selection could not be written to disk
+	saveErr error
 }
 
 type providerOption struct {
@@ -42,19 +50,31 @@ type providerOption struct {
 }
 
 // NewModelSelectModel creates a new model selection model
-func NewModelSelectModel(cfg *config.Config) *ModelSelectModel {
+func NewModelSelectModel(cfg *config.Config, projectRoot string) *ModelSelectModel {
 	providers := []providerOption{
 		{name: "anthropic", label: "Anthropic (Claude)"},
 		{name: "openai", label: "OpenAI (GPT)"},
 		{name: "google", label: "Google (Gemini)"},
+		{name: "azure", label: "Azure OpenAI"},
 		{name: "ollama", label: "Ollama (Local)"},
 	}
 
+	// OpenAI-compatible APIs from the global config, in name order
+	compatibleNames := make([]string, 0, len(cfg.Global.CompatibleProviders))
+	for name := range cfg.Global.CompatibleProviders {
+		compatibleNames = append(compatibleNames, name)
+	}
+	sort.Strings(compatibleNames)
+	for _, name := range compatibleNames {
+		providers = append(providers, providerOption{name: name, label: name + " (OpenAI-compatible)"})
+	}
+
 	return &ModelSelectModel{
-		config:    cfg,
-		step:      StepProvider,
-		selected:  0,
-		providers: providers,
+		config:      cfg,
+		projectRoot: projectRoot,
+		step:        StepProvider,
+		selected:    0,
+		providers:   providers,
 	}
 }
 
@@ -103,6 +123,7 @@ func (m *ModelSelectModel) Update(msg tea.Msg) (*ModelSelectModel, tea.Cmd) {
 
 	case modelsLoadedMsg:
 		m.models = msg.models
+		m.loadErr = msg.err
 		m.loadingModels = false
 		return m, nil
 	}
@@ -136,6 +157,12 @@ func (m *ModelSelectModel) View() string {
 	b.WriteString(centerText(menuBox, m.width))
 	b.WriteString("\n\n")
 
+	if m.saveErr != nil {
+		errText := theme.ErrorStyle.Render("Failed to save model selection: " + m.saveErr.Error())
+		b.WriteString(centerText(errText, m.width))
+		b.WriteString("\n\n")
+	}
+
 	// Render help text
 	helpText := theme.MutedStyle.Render("↑/↓: navigate | Enter: select | q: back to menu")
 	b.WriteString(centerText(helpText, m.width))
@@ -189,6 +216,19 @@ func (m *ModelSelectModel) renderProviderSelection() string {
 func (m *ModelSelectModel) renderModelSelection() string {
 	var items []string
 
+	if len(m.models) == 0 {
+		message := "No models available"
+		if m.loadErr != nil {
+			message = m.loadErr.Error()
+		}
+		emptyStyle := lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.ColorBackground)).
+			Foreground(lipgloss.Color(theme.ColorMuted)).
+			Padding(0, 2).
+			Width(40)
+		items = append(items, emptyStyle.Render(message), "")
+	}
+
 	for i, model := range m.models {
 		var line string
 
@@ -302,17 +342,31 @@ func (m *ModelSelectModel) handleSelection() (*ModelSelectModel, tea.Cmd) {
 			m.step = StepProvider
 			m.selected = 0
 			m.models = nil
+			m.loadErr = nil
 			return m, nil
 		}
 
 		// Save selected model to config
-		selectedModel := m.models[m.selected]
-		m.config.Project.Model = config.ModelSelection{
+		selection := config.ModelSelection{
 			Provider: m.selectedProvider,
-			Model:    selectedModel,
+			Model:    m.models[m.selected],
 		}
 
-		// TODO: Save config to disk
+		// An active profile overrides the project model, so edit it instead
+		if _, _, ok := m.config.ActiveProfile(); ok {
+			if err := m.config.SetProfileModel(selection); err != nil {
+				m.saveErr = err
+				return m, nil
+			}
+		} else {
+			m.config.Project.Model = selection
+
+			// Persist so the choice survives a restart
+			if err := config.SaveProjectConfig(m.projectRoot, m.config.Project); err != nil {
+				m.saveErr = err
+				return m, nil
+			}
+		}
 
 		// Return to menu
 		return m, func() tea.Msg {
@@ -329,30 +383,47 @@ func (m *ModelSelectModel) loadModels() tea.Cmd {
 		switch m.selectedProvider {
 		case "anthropic":
 			apiKey := m.config.GetAPIKey("anthropic")
-			provider = providers.NewAnthropicProvider(apiKey)
+			provider = providers.NewAnthropicProvider(apiKey, m.config.GetEndpoint("anthropic"))
 		case "openai":
 			apiKey := m.config.GetAPIKey("openai")
-			provider = providers.NewOpenAIProvider(apiKey)
+			provider = providers.NewOpenAIProvider(apiKey, m.config.GetEndpoint("openai"))
 		case "google":
 			apiKey := m.config.GetAPIKey("google")
-			provider = providers.NewGoogleProvider(apiKey)
+			provider = providers.NewGoogleProvider(apiKey, m.config.GetEndpoint("google"))
+		case "azure":
+			azure := m.config.Global.AzureOpenAI
+			apiKey := m.config.GetAPIKey("azure")
+			provider = providers.NewAzureOpenAIProvider(apiKey, azure.Endpoint, azure.Deployment, azure.APIVersion)
 		case "ollama":
-			provider = providers.NewOllamaProvider("http://localhost:11434")
+			provider = providers.NewOllamaProvider(m.config.GetEndpoint("ollama"))
 		default:
-			return modelsLoadedMsg{models: []string{}}
+			compatible, ok := m.config.GetCompatibleProvider(m.selectedProvider)
+			if !ok {
+				return modelsLoadedMsg{models: []string{}}
+			}
+			provider = engine.NewCompatibleProvider(m.selectedProvider, compatible)
+		}
+		if err := engine.ConfigureHTTP(m.config, m.selectedProvider, provider); err != nil {
+			return modelsLoadedMsg{models: []string{}, err: err}
 		}
 
-		models, err := provider.ListModels(context.Background())
+		ctx, cancel := context.WithTimeout(context.Background(), listModelsTimeout)
+		defer cancel()
+
+		models, err := provider.ListModels(ctx)
 		if err != nil {
-			// Return empty list on error
-			return modelsLoadedMsg{models: []string{}}
+			return modelsLoadedMsg{models: []string{}, err: err}
 		}
 
 		return modelsLoadedMsg{models: models}
 	}
 }
 
+// listModelsTimeout bounds the model list request
+const listModelsTimeout = 30 * time.Second
+
 // modelsLoadedMsg is sent when models are loaded
 type modelsLoadedMsg struct {
 	models []string
+	err    error
 }
@@ -0,0 +1,505 @@
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cloudboy-jh/churn-plus/internal/config"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+)
+
+// Pass fields in the order tab cycles through them
+const (
+	passFieldName = iota
+	passFieldModel
+	passFieldProvider
+	passFieldEnabled
+	passFieldTemperature
+	passFieldMaxTokens
+	passFieldCount
+)
+
+// PipelineModel configures the analysis passes saved to the project config
+type PipelineModel struct {
+	config      *config.Config
+	projectRoot string
+	width       int
+	height      int
+
+	passes   []config.PassConfig
+	selected int // len(passes) selects the save option
+
+	// Field editing for the selected pass
+	editing    bool
+	editField  int // One of the passField constants
+	fieldInput textinput.Model
+
+	// Set when the pipeline could not be written to disk
+	saveErr error
+}
+
+// NewPipelineModel creates a pipeline editor for the configured passes, or
+// the defaults when none are configured
+func NewPipelineModel(cfg *config.Config, projectRoot string) *PipelineModel {
+	passes := defaultPasses(cfg)
+	if cfg.Project.Pipeline != nil && len(cfg.Project.Pipeline.Passes) > 0 {
+		// Edit a copy so unsaved changes don't leak into the running config
+		passes = append([]config.PassConfig(nil), cfg.Project.Pipeline.Passes...)
+	}
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 100
+	input.Width = 40
+
+	return &PipelineModel{
+		config:      cfg,
+		projectRoot: projectRoot,
+		passes:      passes,
+		fieldInput:  input,
+	}
+}
+
+// defaultPasses returns the passes offered when the project has none
+// configured
+func defaultPasses(cfg *config.Config) []config.PassConfig {
+	modelSelection := cfg.GetModelSelection()
+	// Quick passes use a smaller model where the provider has one
+	lintModel := modelSelection.Model
+	switch modelSelection.Provider {
+	case "anthropic":
+		lintModel = "claude-3-5-haiku-20241022"
+	case "openai":
+		lintModel = "gpt-3.5-turbo"
+	}
+
+	return []config.PassConfig{
+		{
+			Name:        "lint",
+			Description: "Quick structural checks for unused code and basic issues",
+			Enabled:     true,
+			Model:       lintModel,
+			Provider:    modelSelection.Provider,
+		},
+		{
+			Name:        "refactor",
+			Description: "Deep analysis for architectural improvements",
+			Enabled:     true,
+			Model:       modelSelection.Model,
+			Provider:    modelSelection.Provider,
+		},
+		{
+			Name:        "documentation",
+			Description: "Missing or outdated documentation (opt-in)",
+			Enabled:     false,
+			Model:       lintModel,
+			Provider:    modelSelection.Provider,
+		},
+		{
+			Name:        "naming",
+			Description: "Identifier naming conventions (opt-in)",
+			Enabled:     false,
+			Model:       lintModel,
+			Provider:    modelSelection.Provider,
+		},
+		// Summary runs last so it sees every other pass's findings
+		{
+			Name:        "summary",
+			Description: "Coherence check and overall assessment",
+			Enabled:     true,
+			Model:       modelSelection.Model,
+			Provider:    modelSelection.Provider,
+		},
+	}
+}
+
+// SetSize sets the editor dimensions
+func (m *PipelineModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Init initializes the editor
+func (m *PipelineModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages
+func (m *PipelineModel) Update(msg tea.Msg) (*PipelineModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.editing {
+		return m.updateEditor(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "q", "esc":
+		// Return to main menu, discarding unsaved changes
+		return m, func() tea.Msg {
+			return BackToMenuMsg{}
+		}
+
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+
+	case "down", "j":
+		if m.selected < len(m.passes) {
+			m.selected++
+		}
+
+	case "enter", " ":
+		// Toggle the selected pass, or save on the save option
+		if m.selected < len(m.passes) {
+			m.passes[m.selected].Enabled = !m.passes[m.selected].Enabled
+		} else {
+			return m.save()
+		}
+
+	case "e":
+		if m.selected < len(m.passes) {
+			m.editing = true
+			return m, m.focusField(passFieldName)
+		}
+
+	case "a":
+		// Add a pass and select it so it can be edited right away
+		modelSelection := m.config.GetModelSelection()
+		m.passes = append(m.passes, config.PassConfig{
+			Name:        "new-pass",
+			Description: "New pass description",
+			Enabled:     true,
+			Model:       modelSelection.Model,
+			Provider:    modelSelection.Provider,
+		})
+		m.selected = len(m.passes) - 1
+
+	case "d":
+		// Delete the selected pass, keeping at least one
+		if m.selected < len(m.passes) && len(m.passes) > 1 {
+			m.passes = append(m.passes[:m.selected:m.selected], m.passes[m.selected+1:]...)
+			if m.selected >= len(m.passes) {
+				m.selected = len(m.passes) - 1
+			}
+		}
+
+	case "shift+up", "K":
+		m.movePass(-1)
+
+	case "shift+down", "J":
+		m.movePass(1)
+	}
+
+	return m, nil
+}
+
+// Editing reports whether a pass is being edited
+func (m *PipelineModel) Editing() bool {
+	return m.editing
+}
+
+// movePass swaps the selected pass with its neighbour, changing run order
+func (m *PipelineModel) movePass(step int) {
+	from := m.selected
+	to := from + step
+	if from >= len(m.passes) || to < 0 || to >= len(m.passes) {
+		return
+	}
+
+	m.passes[from], m.passes[to] = m.passes[to], m.passes[from]
+	m.selected = to
+}
+
+// updateEditor handles field editing for the selected pass. Changes are
+// applied to the pass as they're made and written out by save.
+func (m *PipelineModel) updateEditor(msg tea.KeyMsg) (*PipelineModel, tea.Cmd) {
+	pass := &m.passes[m.selected]
+
+	switch msg.String() {
+	case "esc", "enter":
+		m.editing = false
+		m.fieldInput.Blur()
+		return m, nil
+
+	case "tab", "shift+tab":
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = passFieldCount - 1
+		}
+		return m, m.focusField((m.editField + step) % passFieldCount)
+	}
+
+	switch m.editField {
+	case passFieldName, passFieldModel:
+		var cmd tea.Cmd
+		m.fieldInput, cmd = m.fieldInput.Update(msg)
+		value := strings.TrimSpace(m.fieldInput.Value())
+		if m.editField == passFieldName {
+			pass.Name = value
+		} else {
+			pass.Model = value
+		}
+		return m, cmd
+
+	case passFieldTemperature, passFieldMaxTokens:
+		// Empty restores the default; unparseable input leaves the pass as is
+		var cmd tea.Cmd
+		m.fieldInput, cmd = m.fieldInput.Update(msg)
+		value := strings.TrimSpace(m.fieldInput.Value())
+		if m.editField == passFieldTemperature {
+			if value == "" {
+				pass.Temperature = nil
+			} else if temperature, err := strconv.ParseFloat(value, 64); err == nil && temperature >= 0 && temperature <= 2 {
+				pass.Temperature = &temperature
+			}
+		} else {
+			if value == "" {
+				pass.MaxTokens = 0
+			} else if maxTokens, err := strconv.Atoi(value); err == nil && maxTokens > 0 {
+				pass.MaxTokens = maxTokens
+			}
+		}
+		return m, cmd
+
+	case passFieldProvider:
+		switch msg.String() {
+		case "left", "h":
+			pass.Provider = m.cycleProvider(pass.Provider, -1)
+		case "right", "l":
+			pass.Provider = m.cycleProvider(pass.Provider, 1)
+		}
+
+	case passFieldEnabled:
+		switch msg.String() {
+		case "left", "right", "h", "l", " ":
+			pass.Enabled = !pass.Enabled
+		}
+	}
+
+	return m, nil
+}
+
+// focusField moves editing to a field, loading text fields into the input
+func (m *PipelineModel) focusField(field int) tea.Cmd {
+	pass := m.passes[m.selected]
+	m.editField = field
+
+	switch field {
+	case passFieldName:
+		m.fieldInput.SetValue(pass.Name)
+	case passFieldModel:
+		m.fieldInput.SetValue(pass.Model)
+	case passFieldTemperature:
+		m.fieldInput.SetValue("")
+		if pass.Temperature != nil {
+			m.fieldInput.SetValue(strconv.FormatFloat(*pass.Temperature, 'g', -1, 64))
+		}
+	case passFieldMaxTokens:
+		m.fieldInput.SetValue("")
+		if pass.MaxTokens > 0 {
+			m.fieldInput.SetValue(strconv.Itoa(pass.MaxTokens))
+		}
+	default:
+		m.fieldInput.Blur()
+		return nil
+	}
+
+	m.fieldInput.CursorEnd()
+	return tea.Batch(m.fieldInput.Focus(), textinput.Blink)
+}
+
+// cycleProvider returns the provider step places away from current,
+// wrapping. Passes can use the built-in and any compatible providers.
+func (m *PipelineModel) cycleProvider(current string, step int) string {
+	providers := m.config.Global.ProviderNames()
+	idx := 0
+	for i, provider := range providers {
+		if provider == current {
+			idx = i
+		}
+	}
+	n := len(providers)
+	return providers[((idx+step)%n+n)%n]
+}
+
+// save writes the passes to the project config and returns to the menu
+func (m *PipelineModel) save() (*PipelineModel, tea.Cmd) {
+	// Passes are looked up by name, so a nameless pass can't be saved
+	for i, pass := range m.passes {
+		if pass.Name == "" {
+			m.selected = i
+			m.saveErr = fmt.Errorf("pass %d has no name", i+1)
+			return m, nil
+		}
+	}
+
+	project := *m.config.Project
+	project.Pipeline = &config.PipelineConfig{
+		Passes: append([]config.PassConfig(nil), m.passes...),
+	}
+
+	if err := config.SaveProjectConfig(m.projectRoot, &project); err != nil {
+		m.saveErr = err
+		return m, nil
+	}
+
+	m.config.Project.Pipeline = project.Pipeline
+	m.saveErr = nil
+	return m, func() tea.Msg {
+		return BackToMenuMsg{}
+	}
+}
+
+// View renders the pipeline editor
+func (m *PipelineModel) View() string {
+	var b strings.Builder
+
+	// Render title
+	b.WriteString("\n\n")
+	title := theme.TitleStyle.Render("CONFIGURE PIPELINE")
+	b.WriteString(centerText(title, m.width))
+	b.WriteString("\n\n")
+
+	pipelineBox := m.renderBox(m.renderPasses())
+	b.WriteString(centerText(pipelineBox, m.width))
+	b.WriteString("\n\n")
+
+	if m.saveErr != nil {
+		errText := theme.ErrorStyle.Render("Failed to save pipeline: " + m.saveErr.Error())
+		b.WriteString(centerText(errText, m.width))
+		b.WriteString("\n\n")
+	}
+
+	// Render help text
+	helpText := "↑/↓: navigate | Space/Enter: toggle/save | e: edit | a: add | d: delete | shift+↑/↓: reorder | q: back to menu"
+	if m.editing {
+		helpText = "Tab: next field | ←/→: change provider/enabled | Enter/Esc: done"
+	}
+	b.WriteString(centerText(theme.MutedStyle.Render(helpText), m.width))
+
+	return b.String()
+}
+
+// renderPasses renders the pass list, expanding the selected pass
+func (m *PipelineModel) renderPasses() string {
+	var items []string
+
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorPrimaryRed)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Bold(true).
+		Padding(0, 2).
+		Width(60)
+
+	unselectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorMuted)).
+		Padding(0, 2).
+		Width(60)
+
+	detailStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Padding(0, 6).
+		Width(60)
+
+	for i, pass := range m.passes {
+		status := "[ ]"
+		if pass.Enabled {
+			status = "[✓]"
+		}
+
+		if i != m.selected {
+			items = append(items, unselectedStyle.Render(fmt.Sprintf("  %s %s", status, pass.Name)))
+			continue
+		}
+
+		items = append(items, selectedStyle.Render(fmt.Sprintf("▶ %s %s", status, pass.Name)))
+		items = append(items, detailStyle.Render(theme.MutedStyle.Render(pass.Description)))
+		if m.editing {
+			items = append(items, detailStyle.Render(m.renderEditor(pass)))
+		} else {
+			items = append(items, detailStyle.Render(fmt.Sprintf("Model: %s (%s)", pass.Model, pass.Provider)))
+		}
+	}
+
+	// Save option
+	items = append(items, "")
+	saveLabel := "Save Configuration"
+	if m.selected == len(m.passes) {
+		items = append(items, selectedStyle.Render("▶ "+saveLabel))
+	} else {
+		items = append(items, unselectedStyle.Render("  "+saveLabel))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// renderEditor renders the editable fields of the selected pass
+func (m *PipelineModel) renderEditor(pass config.PassConfig) string {
+	var lines []string
+
+	defaults := engine.DefaultRequestOptions()
+
+	labels := []string{"Name", "Model", "Provider", "Enabled", "Temperature", "Max tokens"}
+	for field, label := range labels {
+		var value string
+		switch field {
+		case passFieldName:
+			value = pass.Name
+		case passFieldModel:
+			value = pass.Model
+		case passFieldProvider:
+			value = "◀ " + pass.Provider + " ▶"
+		case passFieldEnabled:
+			value = fmt.Sprintf("%v", pass.Enabled)
+		case passFieldTemperature:
+			value = theme.MutedStyle.Render(fmt.Sprintf("default (%g)", defaults.Temperature))
+			if pass.Temperature != nil {
+				value = strconv.FormatFloat(*pass.Temperature, 'g', -1, 64)
+			}
+		case passFieldMaxTokens:
+			value = theme.MutedStyle.Render(fmt.Sprintf("default (%d)", defaults.MaxTokens))
+			if pass.MaxTokens > 0 {
+				value = strconv.Itoa(pass.MaxTokens)
+			}
+		}
+
+		prefix := "  "
+		if field == m.editField {
+			prefix = theme.HighlightStyle.Render("> ")
+			if field != passFieldProvider && field != passFieldEnabled {
+				value = m.fieldInput.View()
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%-12s %s", prefix, label+":", value))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderBox renders content in a box
+func (m *PipelineModel) renderBox(content string) string {
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		BorderBackground(lipgloss.Color(theme.ColorBackground)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Padding(1, 0)
+
+	title := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true).
+		Render(" Passes ")
+
+	return boxStyle.Render(title + "\n" + content)
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cloudboy-jh/churn-plus/internal/config"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
 	"github.com/cloudboy-jh/churn-plus/internal/theme"
 )
 
@@ -16,13 +18,90 @@ type SettingsModel struct {
 	projectRoot string
 	width       int
 	height      int
+
+	// Result of the last keyring migration or key change, shown under the
+	// settings box
+	status string
+	err    error
+
+	// API key form
+	editingAPIKey  bool
+	apiKeyProvider string
+	apiKeyInput    textinput.Model
+	apiKeyErr      error
+
+	// Concurrency form: the limits being edited, in concurrencyProviders
+	// order, and the selected row
+	editingConcurrency bool
+	concurrency        []int
+	concurrencyRow     int
+	concurrencyErr     error
+
+	// Cache form: the settings being edited and the selected cacheRow
+	editingCache bool
+	cache        config.CacheSettings
+	cacheRow     int
+	cacheErr     error
+
+	// Bytes used by the response cache on disk, read when the settings are
+	// opened and after the cache is cleared
+	cacheBytes int64
+
+	// Set once a digit is typed on the selected row of a form, see editNumber
+	typed bool
+}
+
+// Rows of the cache form
+const (
+	cacheRowEnabled = iota
+	cacheRowTTL
+	cacheRowMaxSize
+	cacheRowClear
+	cacheRowCount
+)
+
+// Upper bounds for typed cache settings
+const (
+	maxCacheTTL     = 24 * 365 // Hours
+	maxCacheMaxSize = 100000   // MB
+)
+
+// concurrencyProviders are the providers with a concurrency limit, in the
+// order the form lists them
+var concurrencyProviders = []struct {
+	name  string
+	label string
+}{
+	{"anthropic", "Anthropic"},
+	{"openai", "OpenAI"},
+	{"google", "Google"},
+	{"ollama", "Ollama"},
 }
 
+// maxConcurrency bounds a typed concurrency limit
+const maxConcurrency = 999
+
+// apiKeyProviders are the providers whose keys can be edited, in the order
+// tab cycles through them
+var apiKeyProviders = []string{"anthropic", "openai", "google", "azure"}
+
 // NewSettingsModel creates a new settings model
 func NewSettingsModel(cfg *config.Config, projectRoot string) *SettingsModel {
+	input := textinput.New()
+	input.Placeholder = "Enter API key..."
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '•'
+	input.CharLimit = 200
+	input.Width = 50
+
+	cacheBytes, _ := engine.CacheSize(projectRoot)
+
 	return &SettingsModel{
-		config:      cfg,
-		projectRoot: projectRoot,
+		config:         cfg,
+		projectRoot:    projectRoot,
+		apiKeyProvider: apiKeyProviders[0],
+		apiKeyInput:    input,
+		cacheBytes:     cacheBytes,
 	}
 }
 
@@ -41,18 +120,300 @@ func (m *SettingsModel) Init() tea.Cmd {
 func (m *SettingsModel) Update(msg tea.Msg) (*SettingsModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.editingAPIKey {
+			return m.updateAPIKeyForm(msg)
+		}
+		if m.editingConcurrency {
+			return m.updateConcurrencyForm(msg)
+		}
+		if m.editingCache {
+			return m.updateCacheForm(msg)
+		}
+
 		switch msg.String() {
+		case "a":
+			// Open the API key form
+			m.status, m.err = "", nil
+			m.editingAPIKey = true
+			m.apiKeyErr = nil
+			m.apiKeyInput.Reset()
+			return m, tea.Batch(m.apiKeyInput.Focus(), textinput.Blink)
+		case "c":
+			// Open the concurrency form with the global limits
+			limits := m.config.Global.Concurrency
+			m.status, m.err = "", nil
+			m.editingConcurrency = true
+			m.concurrency = []int{limits.Anthropic, limits.OpenAI, limits.Google, limits.Ollama}
+			m.concurrencyRow = 0
+			m.typed = false
+			m.concurrencyErr = nil
+		case "h":
+			// Open the cache form with the global settings
+			m.status, m.err = "", nil
+			m.editingCache = true
+			m.cache = m.config.Global.Cache
+			m.cacheRow = cacheRowEnabled
+			m.typed = false
+			m.cacheErr = nil
 		case "q", "esc", "enter":
 			// Return to main menu
+			m.status, m.err = "", nil
 			return m, func() tea.Msg {
 				return BackToMenuMsg{}
 			}
+		case "p":
+			// Cycle through the profiles, then back to none
+			m.status, m.err = "", nil
+			if err := m.config.SetActiveProfile(m.nextProfile()); err != nil {
+				m.err = err
+			}
+		case "k":
+			// Move plaintext keys from the global config into the OS keyring
+			moved, err := m.config.EnableKeyring()
+			if err != nil {
+				m.status, m.err = "", err
+			} else {
+				m.status, m.err = fmt.Sprintf("Moved %d API key(s) to the OS keyring", moved), nil
+			}
 		}
 	}
 
 	return m, nil
 }
 
+// Editing reports whether a form is open
+func (m *SettingsModel) Editing() bool {
+	return m.editingAPIKey || m.editingConcurrency || m.editingCache
+}
+
+// updateAPIKeyForm handles keys while the API key form is open
+func (m *SettingsModel) updateAPIKeyForm(msg tea.KeyMsg) (*SettingsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closeAPIKeyForm()
+		return m, nil
+
+	case "tab", "shift+tab":
+		// Cycle through providers without leaving the form
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(apiKeyProviders) - 1
+		}
+		next := 0
+		for i, provider := range apiKeyProviders {
+			if provider == m.apiKeyProvider {
+				next = (i + step) % len(apiKeyProviders)
+			}
+		}
+		m.apiKeyProvider = apiKeyProviders[next]
+		m.apiKeyErr = nil
+		m.apiKeyInput.Reset()
+		return m, nil
+
+	case "enter":
+		apiKey := strings.TrimSpace(m.apiKeyInput.Value())
+		if apiKey == "" {
+			return m, nil
+		}
+		return m.saveAPIKey(apiKey)
+
+	case "ctrl+d":
+		// Clear the stored key
+		return m.saveAPIKey("")
+	}
+
+	var cmd tea.Cmd
+	m.apiKeyInput, cmd = m.apiKeyInput.Update(msg)
+	return m, cmd
+}
+
+// saveAPIKey stores the key for the provider being edited and persists the
+// global config. An empty key clears the stored one.
+func (m *SettingsModel) saveAPIKey(apiKey string) (*SettingsModel, tea.Cmd) {
+	if err := m.config.StoreAPIKey(m.apiKeyProvider, apiKey); err != nil {
+		// Keep the form open so the key isn't lost
+		m.apiKeyErr = err
+		return m, nil
+	}
+
+	if apiKey == "" {
+		m.status = "Cleared the " + m.apiKeyProvider + " API key"
+	} else {
+		m.status = "Saved the " + m.apiKeyProvider + " API key"
+	}
+	m.closeAPIKeyForm()
+	return m, nil
+}
+
+// closeAPIKeyForm closes the API key form, discarding any typed key
+func (m *SettingsModel) closeAPIKeyForm() {
+	m.editingAPIKey = false
+	m.apiKeyErr = nil
+	m.apiKeyInput.Reset()
+	m.apiKeyInput.Blur()
+}
+
+// updateConcurrencyForm handles keys while the concurrency form is open
+func (m *SettingsModel) updateConcurrencyForm(msg tea.KeyMsg) (*SettingsModel, tea.Cmd) {
+	key := msg.String()
+	value := &m.concurrency[m.concurrencyRow]
+
+	switch key {
+	case "esc":
+		m.editingConcurrency = false
+		m.concurrencyErr = nil
+		return m, nil
+
+	case "enter":
+		limits := config.ConcurrencyLimits{
+			Anthropic: m.concurrency[0],
+			OpenAI:    m.concurrency[1],
+			Google:    m.concurrency[2],
+			Ollama:    m.concurrency[3],
+		}
+		if err := m.config.SetConcurrencyLimits(limits); err != nil {
+			// Keep the form open so the values aren't lost
+			m.concurrencyErr = err
+			return m, nil
+		}
+		m.editingConcurrency = false
+		m.concurrencyErr = nil
+		m.status = "Saved concurrency limits"
+		return m, nil
+
+	case "up", "k", "shift+tab":
+		if m.concurrencyRow > 0 {
+			m.concurrencyRow--
+			m.typed = false
+		}
+
+	case "down", "j", "tab":
+		if m.concurrencyRow < len(m.concurrency)-1 {
+			m.concurrencyRow++
+			m.typed = false
+		}
+
+	default:
+		editNumber(value, &m.typed, key, maxConcurrency)
+	}
+
+	return m, nil
+}
+
+// updateCacheForm handles keys while the cache form is open
+func (m *SettingsModel) updateCacheForm(msg tea.KeyMsg) (*SettingsModel, tea.Cmd) {
+	key := msg.String()
+
+	switch key {
+	case "esc":
+		m.editingCache = false
+		m.cacheErr = nil
+		return m, nil
+
+	case "up", "k", "shift+tab":
+		if m.cacheRow > 0 {
+			m.cacheRow--
+			m.typed = false
+		}
+		return m, nil
+
+	case "down", "j", "tab":
+		if m.cacheRow < cacheRowCount-1 {
+			m.cacheRow++
+			m.typed = false
+		}
+		return m, nil
+
+	case "enter", " ":
+		if m.cacheRow == cacheRowClear {
+			return m.clearCache()
+		}
+		if m.cacheRow == cacheRowEnabled && key == " " {
+			m.cache.Enabled = !m.cache.Enabled
+			return m, nil
+		}
+		if key == "enter" {
+			return m.saveCache()
+		}
+		return m, nil
+	}
+
+	switch m.cacheRow {
+	case cacheRowEnabled:
+		switch key {
+		case "left", "right", "h", "l":
+			m.cache.Enabled = !m.cache.Enabled
+		}
+	case cacheRowTTL:
+		editNumber(&m.cache.TTL, &m.typed, key, maxCacheTTL)
+	case cacheRowMaxSize:
+		editNumber(&m.cache.MaxSize, &m.typed, key, maxCacheMaxSize)
+	}
+
+	return m, nil
+}
+
+// saveCache persists the cache settings being edited
+func (m *SettingsModel) saveCache() (*SettingsModel, tea.Cmd) {
+	if err := m.config.SetCacheSettings(m.cache); err != nil {
+		// Keep the form open so the values aren't lost
+		m.cacheErr = err
+		return m, nil
+	}
+
+	m.editingCache = false
+	m.cacheErr = nil
+	m.status = "Saved cache settings"
+	return m, nil
+}
+
+// clearCache deletes the project's response cache, keeping the form open
+func (m *SettingsModel) clearCache() (*SettingsModel, tea.Cmd) {
+	if err := engine.ClearCache(m.projectRoot); err != nil {
+		m.cacheErr = err
+		return m, nil
+	}
+
+	m.cacheErr = nil
+	m.status = "The response cache is already empty"
+	if m.cacheBytes > 0 {
+		m.status = fmt.Sprintf("Cleared %s from the response cache", formatBytes(m.cacheBytes))
+	}
+	m.cacheBytes = 0
+	return m, nil
+}
+
+// editNumber applies a key to a number being edited: ←/→ and -/+ step it
+// within 1..maxValue, digits type it and backspace deletes a digit. typed
+// tracks whether a digit was entered since the field was selected, so the
+// first digit replaces the value rather than appending to it.
+func editNumber(value *int, typed *bool, key string, maxValue int) {
+	switch key {
+	case "left", "h", "-":
+		*value = max(*value-1, 1)
+		*typed = false
+
+	case "right", "l", "+", "=":
+		*value = min(*value+1, maxValue)
+		*typed = false
+
+	case "backspace":
+		*value /= 10
+		*typed = true
+
+	default:
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+			digit := int(key[0] - '0')
+			if *typed {
+				*value = min(*value*10+digit, maxValue)
+			} else {
+				*value = digit
+			}
+			*typed = true
+		}
+	}
+}
+
 // View renders the settings
 func (m *SettingsModel) View() string {
 	var b strings.Builder
@@ -63,19 +424,93 @@ func (m *SettingsModel) View() string {
 	b.WriteString(centerText(title, m.width))
 	b.WriteString("\n\n")
 
+	if m.editingAPIKey {
+		b.WriteString(centerText(m.renderAPIKeyForm(), m.width))
+		b.WriteString("\n\n")
+
+		if m.apiKeyErr != nil {
+			errText := theme.ErrorStyle.Render("Failed to save API key: " + m.apiKeyErr.Error())
+			b.WriteString(centerText(errText, m.width))
+			b.WriteString("\n\n")
+		}
+
+		helpText := theme.MutedStyle.Render("Tab: switch provider • Enter: save • Ctrl+D: clear key • Esc: cancel")
+		b.WriteString(centerText(helpText, m.width))
+		return b.String()
+	}
+
+	if m.editingConcurrency {
+		b.WriteString(centerText(m.renderConcurrencyForm(), m.width))
+		b.WriteString("\n\n")
+
+		if m.concurrencyErr != nil {
+			errText := theme.ErrorStyle.Render("Failed to save concurrency limits: " + m.concurrencyErr.Error())
+			b.WriteString(centerText(errText, m.width))
+			b.WriteString("\n\n")
+		}
+
+		helpText := theme.MutedStyle.Render("↑/↓: select provider • ←/→ or digits: change limit • Enter: save • Esc: cancel")
+		b.WriteString(centerText(helpText, m.width))
+		return b.String()
+	}
+
+	if m.editingCache {
+		b.WriteString(centerText(m.renderCacheForm(), m.width))
+		b.WriteString("\n\n")
+
+		if m.cacheErr != nil {
+			errText := theme.ErrorStyle.Render(m.cacheErr.Error())
+			b.WriteString(centerText(errText, m.width))
+			b.WriteString("\n\n")
+		} else if m.status != "" {
+			b.WriteString(centerText(theme.SuccessStyle.Render(m.status), m.width))
+			b.WriteString("\n\n")
+		}
+
+		helpText := theme.MutedStyle.Render("↑/↓: select • ←/→ or digits: change • Space: toggle/clear • Enter: save • Esc: cancel")
+		b.WriteString(centerText(helpText, m.width))
+		return b.String()
+	}
+
 	// Render settings content
 	content := m.renderSettings()
 	settingsBox := m.renderBox(content)
 	b.WriteString(centerText(settingsBox, m.width))
 	b.WriteString("\n\n")
 
+	if m.err != nil {
+		b.WriteString(centerText(theme.ErrorStyle.Render(m.err.Error()), m.width))
+		b.WriteString("\n\n")
+	} else if m.status != "" {
+		b.WriteString(centerText(theme.SuccessStyle.Render(m.status), m.width))
+		b.WriteString("\n\n")
+	}
+
 	// Render help text
-	helpText := theme.MutedStyle.Render("Press 'q' or Enter to go back to menu")
+	helpText := theme.MutedStyle.Render("Press 'a' to edit API keys • 'c' to edit concurrency • 'h' to edit cache • 'p' to switch profile • 'k' to move API keys to the OS keyring • 'q' or Enter to go back to menu")
 	b.WriteString(centerText(helpText, m.width))
 
 	return b.String()
 }
 
+// nextProfile returns the profile after the active one, "" after the last
+func (m *SettingsModel) nextProfile() string {
+	names := m.config.ProfileNames()
+	active, _, _ := m.config.ActiveProfile()
+	for i, name := range names {
+		if name == active {
+			if i+1 < len(names) {
+				return names[i+1]
+			}
+			return ""
+		}
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
 // renderSettings renders the settings content
 func (m *SettingsModel) renderSettings() string {
 	var items []string
@@ -96,7 +531,12 @@ func (m *SettingsModel) renderSettings() string {
 	sensitiveStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.ColorMuted))
 
-	// Provider and model
+	// Profile, provider and model
+	profile := theme.MutedStyle.Render("none")
+	if name, _, ok := m.config.ActiveProfile(); ok {
+		profile = valueStyle.Render(name)
+	}
+	items = append(items, labelStyle.Render("Profile: ")+profile)
 	items = append(items, labelStyle.Render("Provider: ")+valueStyle.Render(modelSelection.Provider))
 	items = append(items, labelStyle.Render("Model: ")+valueStyle.Render(modelSelection.Model))
 	items = append(items, "")
@@ -104,49 +544,51 @@ func (m *SettingsModel) renderSettings() string {
 	// API Keys
 	items = append(items, labelStyle.Render("API Keys:"))
 
-	anthropicKey := m.config.Global.APIKeys.Anthropic
-	if anthropicKey != "" {
-		maskedKey := maskAPIKey(anthropicKey)
-		items = append(items, "  Anthropic: "+sensitiveStyle.Render(maskedKey))
-	} else {
-		items = append(items, "  Anthropic: "+theme.MutedStyle.Render("not set"))
+	keyProviders := []struct {
+		name  string
+		label string
+	}{
+		{"anthropic", "Anthropic:"},
+		{"openai", "OpenAI:"},
+		{"google", "Google:"},
+		{"azure", "Azure:"},
 	}
-
-	openaiKey := m.config.Global.APIKeys.OpenAI
-	if openaiKey != "" {
-		maskedKey := maskAPIKey(openaiKey)
-		items = append(items, "  OpenAI:    "+sensitiveStyle.Render(maskedKey))
-	} else {
-		items = append(items, "  OpenAI:    "+theme.MutedStyle.Render("not set"))
+	for _, p := range keyProviders {
+		key := m.config.GetAPIKey(p.name)
+		if key == "" {
+			items = append(items, fmt.Sprintf("  %-11s", p.label)+theme.MutedStyle.Render("not set"))
+			continue
+		}
+		source := theme.MutedStyle.Render(" (" + m.config.APIKeySource(p.name) + ")")
+		items = append(items, fmt.Sprintf("  %-11s", p.label)+sensitiveStyle.Render(maskAPIKey(key))+source)
 	}
 
-	googleKey := m.config.Global.APIKeys.Google
-	if googleKey != "" {
-		maskedKey := maskAPIKey(googleKey)
-		items = append(items, "  Google:    "+sensitiveStyle.Render(maskedKey))
-	} else {
-		items = append(items, "  Google:    "+theme.MutedStyle.Render("not set"))
+	keyStorage := "config file"
+	if m.config.Global.UseKeyring {
+		keyStorage = "OS keyring"
 	}
-
+	items = append(items, "  Storage:   "+valueStyle.Render(keyStorage))
 	items = append(items, "")
 
 	// Concurrency settings
 	items = append(items, labelStyle.Render("Concurrency Limits:"))
-	items = append(items, fmt.Sprintf("  Anthropic: %s", valueStyle.Render(fmt.Sprintf("%d", m.config.Global.Concurrency.Anthropic))))
-	items = append(items, fmt.Sprintf("  OpenAI:    %s", valueStyle.Render(fmt.Sprintf("%d", m.config.Global.Concurrency.OpenAI))))
-	items = append(items, fmt.Sprintf("  Google:    %s", valueStyle.Render(fmt.Sprintf("%d", m.config.Global.Concurrency.Google))))
-	items = append(items, fmt.Sprintf("  Ollama:    %s", valueStyle.Render(fmt.Sprintf("%d", m.config.Global.Concurrency.Ollama))))
+	items = append(items, fmt.Sprintf("  Anthropic: %s", valueStyle.Render(fmt.Sprintf("%d", m.config.GetConcurrencyLimit("anthropic")))))
+	items = append(items, fmt.Sprintf("  OpenAI:    %s", valueStyle.Render(fmt.Sprintf("%d", m.config.GetConcurrencyLimit("openai")))))
+	items = append(items, fmt.Sprintf("  Google:    %s", valueStyle.Render(fmt.Sprintf("%d", m.config.GetConcurrencyLimit("google")))))
+	items = append(items, fmt.Sprintf("  Ollama:    %s", valueStyle.Render(fmt.Sprintf("%d", m.config.GetConcurrencyLimit("ollama")))))
 	items = append(items, "")
 
 	// Cache settings
 	items = append(items, labelStyle.Render("Cache:"))
+	cache := m.config.GetCacheSettings()
 	cacheEnabled := "disabled"
-	if m.config.Global.Cache.Enabled {
+	if cache.Enabled {
 		cacheEnabled = "enabled"
 	}
 	items = append(items, "  Status: "+valueStyle.Render(cacheEnabled))
-	items = append(items, fmt.Sprintf("  TTL:    %s", valueStyle.Render(fmt.Sprintf("%d hours", m.config.Global.Cache.TTL))))
-	items = append(items, fmt.Sprintf("  Size:   %s", valueStyle.Render(fmt.Sprintf("%d MB", m.config.Global.Cache.MaxSize))))
+	items = append(items, fmt.Sprintf("  TTL:    %s", valueStyle.Render(fmt.Sprintf("%d hours", cache.TTL))))
+	items = append(items, fmt.Sprintf("  Size:   %s", valueStyle.Render(fmt.Sprintf("%d MB", cache.MaxSize)))+
+		theme.MutedStyle.Render(fmt.Sprintf(" (%s used)", formatBytes(m.cacheBytes))))
 	items = append(items, "")
 
 	// Config file locations
@@ -167,6 +609,161 @@ func (m *SettingsModel) renderSettings() string {
 	return contentStyle.Render(strings.Join(items, "\n"))
 }
 
+// renderAPIKeyForm renders the API key input for the selected provider
+func (m *SettingsModel) renderAPIKeyForm() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true)
+
+	status := theme.MutedStyle.Render("not set")
+	if m.config.GetAPIKey(m.apiKeyProvider) != "" {
+		status = theme.MutedStyle.Render("set (" + m.config.APIKeySource(m.apiKeyProvider) + ")")
+	}
+
+	items := []string{
+		labelStyle.Render("Provider: ") + theme.HighlightStyle.Render(m.apiKeyProvider) + "  " + status,
+		"",
+		m.apiKeyInput.View(),
+	}
+
+	contentStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Padding(0, 2)
+
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		BorderBackground(lipgloss.Color(theme.ColorBackground)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Padding(1, 0).
+		Width(70)
+
+	title := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true).
+		Render(" API Key ")
+
+	return boxStyle.Render(title + "\n" + contentStyle.Render(strings.Join(items, "\n")))
+}
+
+// renderConcurrencyForm renders the per-provider concurrency limits being
+// edited
+func (m *SettingsModel) renderConcurrencyForm() string {
+	var items []string
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true)
+
+	items = append(items, theme.MutedStyle.Render("Maximum parallel requests per provider (at least 1)"))
+	items = append(items, "")
+
+	_, profile, hasProfile := m.config.ActiveProfile()
+	for i, provider := range concurrencyProviders {
+		line := fmt.Sprintf("  %-10s %d", provider.label+":", m.concurrency[i])
+		if i == m.concurrencyRow {
+			line = labelStyle.Render(fmt.Sprintf("▶ %-10s ◀ %d ▶", provider.label+":", m.concurrency[i]))
+		}
+
+		// The active profile's limit wins over the global one being edited
+		if hasProfile {
+			override := profile.Concurrency
+			limits := []int{override.Anthropic, override.OpenAI, override.Google, override.Ollama}
+			if limits[i] > 0 {
+				line += theme.MutedStyle.Render(fmt.Sprintf("  (profile uses %d)", limits[i]))
+			}
+		}
+		items = append(items, line)
+	}
+
+	contentStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Padding(0, 2)
+
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		BorderBackground(lipgloss.Color(theme.ColorBackground)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Padding(1, 0).
+		Width(70)
+
+	title := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true).
+		Render(" Concurrency Limits ")
+
+	return boxStyle.Render(title + "\n" + contentStyle.Render(strings.Join(items, "\n")))
+}
+
+// renderCacheForm renders the cache settings being edited
+func (m *SettingsModel) renderCacheForm() string {
+	var items []string
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true)
+
+	enabled := "disabled"
+	if m.cache.Enabled {
+		enabled = "enabled"
+	}
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Status:", enabled},
+		{"TTL:", fmt.Sprintf("%d hours", m.cache.TTL)},
+		{"Max size:", fmt.Sprintf("%d MB", m.cache.MaxSize)},
+		{"", "Clear cache now"},
+	}
+	for i, row := range rows {
+		line := fmt.Sprintf("  %-10s %s", row.label, row.value)
+		if i == m.cacheRow {
+			value := "◀ " + row.value + " ▶"
+			if i == cacheRowClear {
+				value = "[" + row.value + "]"
+			}
+			line = labelStyle.Render(fmt.Sprintf("▶ %-10s %s", row.label, value))
+		}
+		if i == cacheRowMaxSize {
+			line += theme.MutedStyle.Render(fmt.Sprintf("  (%s used)", formatBytes(m.cacheBytes)))
+		}
+		if i == cacheRowClear {
+			items = append(items, "")
+		}
+		items = append(items, line)
+	}
+
+	if _, profile, ok := m.config.ActiveProfile(); ok && profile.Cache != nil {
+		items = append(items, "")
+		items = append(items, theme.MutedStyle.Render("The active profile overrides these settings"))
+	}
+
+	contentStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Padding(0, 2)
+
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		BorderBackground(lipgloss.Color(theme.ColorBackground)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Padding(1, 0).
+		Width(70)
+
+	title := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true).
+		Render(" Response Cache ")
+
+	return boxStyle.Render(title + "\n" + contentStyle.Render(strings.Join(items, "\n")))
+}
+
 // renderBox renders content in a box
 func (m *SettingsModel) renderBox(content string) string {
 	boxStyle := lipgloss.NewStyle().
@@ -187,6 +784,14 @@ func (m *SettingsModel) renderBox(content string) string {
 	return boxStyle.Render(fullContent)
 }
 
+// formatBytes formats a size in bytes as KB or MB
+func formatBytes(n int64) string {
+	if n < 1024*1024 {
+		return fmt.Sprintf("%d KB", (n+1023)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+}
+
 // maskAPIKey masks an API key for display
 func maskAPIKey(key string) string {
 	if len(key) <= 10 {
@@ -7,6 +7,10 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
 )
 
 // CodeViewPane displays file content with syntax highlighting
@@ -17,16 +21,36 @@ type CodeViewPane struct {
 	lines      []string
 	scroll     int
 	highlights map[int]bool // Line numbers to highlight
+
+	language        string
+	syntaxHighlight bool
+	showLineNumbers bool
+	styled          []string // Syntax highlighted lines, nil when plain
 }
 
 // NewCodeViewPane creates a new code view pane
 func NewCodeViewPane() *CodeViewPane {
 	return &CodeViewPane{
-		lines:      make([]string, 0),
-		highlights: make(map[int]bool),
+		lines:           make([]string, 0),
+		highlights:      make(map[int]bool),
+		syntaxHighlight: true,
+		showLineNumbers: true,
+	}
+}
+
+// SetSyntaxHighlight turns syntax highlighting on or off
+func (p *CodeViewPane) SetSyntaxHighlight(enabled bool) {
+	if enabled != p.syntaxHighlight {
+		p.syntaxHighlight = enabled
+		p.restyle()
 	}
 }
 
+// SetShowLineNumbers turns the line number gutter on or off
+func (p *CodeViewPane) SetShowLineNumbers(show bool) {
+	p.showLineNumbers = show
+}
+
 // SetSize sets the pane dimensions
 func (p *CodeViewPane) SetSize(width, height int) {
 	p.width = width
@@ -50,9 +74,21 @@ func (p *CodeViewPane) SetFile(filePath string) error {
 		p.lines = append(p.lines, scanner.Text())
 	}
 
+	p.language = engine.DetectLanguage(filePath)
+	p.restyle()
+
 	return scanner.Err()
 }
 
+// restyle highlights the loaded lines, leaving them plain when highlighting
+// is off or the language isn't recognized
+func (p *CodeViewPane) restyle() {
+	p.styled = nil
+	if p.syntaxHighlight && p.language != "unknown" && len(p.lines) > 0 {
+		p.styled = highlightLines(p.lines, p.language)
+	}
+}
+
 // SetHighlights sets which lines to highlight
 func (p *CodeViewPane) SetHighlights(lines map[int]bool) {
 	p.highlights = lines
@@ -66,12 +102,12 @@ func (p *CodeViewPane) JumpToLine(line int) {
 
 	// Center the line in view
 	p.scroll = line - p.height/2
-	if p.scroll < 0 {
-		p.scroll = 0
-	}
 	if p.scroll > len(p.lines)-p.height {
 		p.scroll = len(p.lines) - p.height
 	}
+	if p.scroll < 0 {
+		p.scroll = 0
+	}
 }
 
 // Update handles messages
@@ -114,21 +150,37 @@ func (p *CodeViewPane) View() string {
 		end = len(p.lines)
 	}
 
+	markerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ColorPrimaryRed)).Bold(true)
+
 	for i := start; i < end; i++ {
 		lineNum := i + 1
-		marker := " "
-		if p.highlights[lineNum] {
-			marker = "►"
-		}
+		highlighted := p.highlights[lineNum]
 
-		line := fmt.Sprintf("%s%4d │ %s\n", marker, lineNum, p.lines[i])
+		gutter := " "
+		if highlighted {
+			gutter = "►"
+		}
+		if p.showLineNumbers {
+			gutter += fmt.Sprintf("%4d │ ", lineNum)
+		} else {
+			gutter += " "
+		}
+		if highlighted {
+			gutter = markerStyle.Render(gutter)
+		} else {
+			gutter = theme.MutedStyle.Render(gutter)
+		}
 
-		// Truncate if too long
-		if len(line) > p.width {
-			line = line[:p.width-3] + "...\n"
+		// Styled lines already have tabs expanded by lipgloss
+		text := strings.ReplaceAll(p.lines[i], "\t", "    ")
+		if p.styled != nil {
+			text = p.styled[i]
 		}
 
-		sb.WriteString(line)
+		// Truncate if too long, measuring printable width only
+		line := ansi.Truncate(gutter+text, p.width, "...")
+
+		sb.WriteString(line + "\n")
 	}
 
 	return sb.String()
@@ -0,0 +1,77 @@
+package panes
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// highlightStyle is the chroma style used for code, chosen to suit the dark
+// code background
+const highlightStyle = "github-dark"
+
+// highlightLines syntax highlights source line by line. It returns nil when
+// the language has no lexer, so callers fall back to plain text.
+func highlightLines(lines []string, language string) []string {
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		return nil
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	iterator, err := lexer.Tokenise(nil, strings.Join(lines, "\n"))
+	if err != nil {
+		return nil
+	}
+
+	style := styles.Get(highlightStyle)
+	tokenStyles := make(map[chroma.TokenType]lipgloss.Style)
+
+	highlighted := make([]string, 0, len(lines))
+	var current strings.Builder
+	for _, token := range iterator.Tokens() {
+		tokenStyle, ok := tokenStyles[token.Type]
+		if !ok {
+			tokenStyle = lipglossStyle(style.Get(token.Type))
+			tokenStyles[token.Type] = tokenStyle
+		}
+
+		// Tokens such as block comments span lines; style each piece so every
+		// line carries its own escape codes
+		for i, part := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				highlighted = append(highlighted, current.String())
+				current.Reset()
+			}
+			if part != "" {
+				current.WriteString(tokenStyle.Render(part))
+			}
+		}
+	}
+	highlighted = append(highlighted, current.String())
+
+	// Lexers may add a trailing newline; anything else means the lines no
+	// longer line up, so don't highlight at all
+	if len(highlighted) < len(lines) {
+		return nil
+	}
+	return highlighted[:len(lines)]
+}
+
+// lipglossStyle converts a chroma style entry to a lipgloss style
+func lipglossStyle(entry chroma.StyleEntry) lipgloss.Style {
+	s := lipgloss.NewStyle()
+	if entry.Colour.IsSet() {
+		s = s.Foreground(lipgloss.Color(entry.Colour.String()))
+	}
+	if entry.Bold == chroma.Yes {
+		s = s.Bold(true)
+	}
+	if entry.Italic == chroma.Yes {
+		s = s.Italic(true)
+	}
+	return s
+}
@@ -3,23 +3,39 @@ package panes
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cloudboy-jh/churn-plus/internal/engine"
 	"github.com/cloudboy-jh/churn-plus/internal/theme"
 )
 
-// PipelinePane displays pipeline execution status
+// PipelinePane displays pipeline execution status. It works on its own copy
+// of the passes, updated from pipeline events, so it never reads state the
+// running orchestrator writes.
 type PipelinePane struct {
-	width    int
-	height   int
-	pipeline *engine.Pipeline
-	scroll   int
+	width  int
+	height int
+	passes []engine.Pass
+	scroll int
+
+	// When the passes were set, and when the last of them finished
+	startTime time.Time
+	endTime   time.Time
+
+	// progress holds each pass's latest file counts, keyed by pass index
+	progress map[int]passProgress
+}
+
+// passProgress is how many of a pass's files have been analyzed
+type passProgress struct {
+	current int
+	total   int
 }
 
 // NewPipelinePane creates a new pipeline pane
 func NewPipelinePane() *PipelinePane {
-	return &PipelinePane{}
+	return &PipelinePane{progress: make(map[int]passProgress)}
 }
 
 // SetSize sets the pane dimensions
@@ -28,9 +44,53 @@ func (p *PipelinePane) SetSize(width, height int) {
 	p.height = height
 }
 
-// UpdatePipeline updates the pipeline data
-func (p *PipelinePane) UpdatePipeline(pipeline *engine.Pipeline) {
-	p.pipeline = pipeline
+// SetPasses sets the passes of a pipeline that is starting; pass a copy
+// taken before it runs
+func (p *PipelinePane) SetPasses(passes []engine.Pass) {
+	p.passes = passes
+	p.startTime = time.Now()
+	p.endTime = time.Time{}
+	p.progress = make(map[int]passProgress)
+}
+
+// HandleEvent records the pass status and file counts carried by a
+// pipeline event
+func (p *PipelinePane) HandleEvent(event engine.PipelineEvent) {
+	if event.PassIndex < 0 || event.PassIndex >= len(p.passes) {
+		return
+	}
+	pass := &p.passes[event.PassIndex]
+
+	switch event.Type {
+	case engine.EventPassStarted:
+		pass.Status = engine.PassRunning
+	case engine.EventPassCompleted:
+		pass.Status = engine.PassCompleted
+	case engine.EventPassFailed:
+		pass.Status = engine.PassFailed
+		if event.Error != nil {
+			pass.Error = event.Error.Error()
+		}
+	}
+
+	switch event.Type {
+	case engine.EventPassStarted, engine.EventPassProgress, engine.EventPassCompleted:
+		p.progress[event.PassIndex] = passProgress{current: event.Current, total: event.Total}
+	}
+
+	if p.endTime.IsZero() && p.finished() {
+		p.endTime = time.Now()
+	}
+}
+
+// finished reports whether every pass has completed or failed
+func (p *PipelinePane) finished() bool {
+	for _, pass := range p.passes {
+		if pass.Status != engine.PassCompleted && pass.Status != engine.PassFailed {
+			return false
+		}
+	}
+	return true
 }
 
 // Update handles messages
@@ -39,7 +99,7 @@ func (p *PipelinePane) Update(msg tea.Msg) tea.Cmd {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "j", "down":
-			if p.scroll < len(p.pipeline.Passes)-p.height {
+			if p.scroll < len(p.passes)-p.height {
 				p.scroll++
 			}
 		case "k", "up":
@@ -53,14 +113,14 @@ func (p *PipelinePane) Update(msg tea.Msg) tea.Cmd {
 
 // View renders the pane
 func (p *PipelinePane) View() string {
-	if p.pipeline == nil || len(p.pipeline.Passes) == 0 {
+	if len(p.passes) == 0 {
 		return "Pipeline not started"
 	}
 
 	var sb strings.Builder
 
 	// Display passes
-	for i, pass := range p.pipeline.Passes {
+	for i, pass := range p.passes {
 		if i < p.scroll {
 			continue
 		}
@@ -89,6 +149,14 @@ func (p *PipelinePane) View() string {
 
 		sb.WriteString(style.Render(line) + "\n")
 
+		// Show a progress bar once the pass has started, e.g. "142/500"
+		if progress, ok := p.progress[i]; ok && progress.total > 0 {
+			counts := fmt.Sprintf("%d/%d", progress.current, progress.total)
+			barWidth := max(p.width-len(counts)-4, 10)
+			bar := theme.CreateProgressBar(progress.current, progress.total, barWidth)
+			sb.WriteString("   " + bar + " " + theme.MutedStyle.Render(counts) + "\n")
+		}
+
 		// Show error if failed
 		if pass.Status == engine.PassFailed && pass.Error != "" {
 			errorLine := fmt.Sprintf("   Error: %s", pass.Error)
@@ -100,10 +168,11 @@ func (p *PipelinePane) View() string {
 	}
 
 	// Show summary
-	if p.pipeline.EndTime.IsZero() {
-		sb.WriteString("\n" + theme.InfoStyle.Render("Pipeline running...") + "\n")
+	if p.endTime.IsZero() {
+		elapsed := time.Since(p.startTime).Round(time.Second)
+		sb.WriteString("\n" + theme.InfoStyle.Render(fmt.Sprintf("Pipeline running... %s elapsed", elapsed)) + "\n")
 	} else {
-		duration := p.pipeline.EndTime.Sub(p.pipeline.StartTime)
+		duration := p.endTime.Sub(p.startTime)
 		summary := fmt.Sprintf("\nCompleted in %s", duration.Round(100))
 		sb.WriteString(theme.SuccessStyle.Render(summary) + "\n")
 	}
@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/atotto/clipboard"
+)
+
+// copyText puts text on the system clipboard. Without a usable clipboard,
+// such as over SSH or in a headless session, the text is written to a temp
+// file instead. It returns a short note saying where the text went.
+func copyText(text string) (string, error) {
+	if !clipboard.Unsupported {
+		if err := clipboard.WriteAll(text); err == nil {
+			return "Copied to clipboard", nil
+		}
+	}
+
+	f, err := os.CreateTemp("", "churn-copy-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(text); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	return fmt.Sprintf("No clipboard available, saved to %s", f.Name()), nil
+}
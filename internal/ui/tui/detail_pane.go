@@ -2,34 +2,180 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cloudboy-jh/churn-plus/internal/config"
 	"github.com/cloudboy-jh/churn-plus/internal/engine"
 	"github.com/cloudboy-jh/churn-plus/internal/theme"
+	"github.com/cloudboy-jh/churn-plus/internal/ui/panes"
 )
 
+// sourceContextLines is how many lines of the finding's file are shown
+const sourceContextLines = 11
+
 // DetailPane displays finding details
 type DetailPane struct {
 	finding *engine.Finding
 	width   int
 	height  int
+
+	// Scroll offset in lines and the content height from the last render
+	scroll       int
+	contentLines int
+
+	// Source file around the finding; sourceErr explains why it's missing
+	projectRoot  string
+	maxFileBytes int64
+	source       *panes.CodeViewPane
+	sourcePath   string
+	sourceErr    string
 }
 
 // NewDetailPane creates a new detail pane
 func NewDetailPane() *DetailPane {
-	return &DetailPane{}
+	return &DetailPane{
+		maxFileBytes: engine.DefaultMaxFileBytes,
+		source:       panes.NewCodeViewPane(),
+	}
+}
+
+// SetProjectRoot sets the directory relative finding paths are resolved against
+func (p *DetailPane) SetProjectRoot(root string) {
+	p.projectRoot = root
+}
+
+// SetUISettings applies the syntax highlighting and line number settings to
+// the source view
+func (p *DetailPane) SetUISettings(ui config.UISettings) {
+	p.source.SetSyntaxHighlight(ui.SyntaxHighlight)
+	p.source.SetShowLineNumbers(ui.ShowLineNumbers)
+}
+
+// SetMaxFileBytes sets the size above which source files aren't shown; 0
+// uses the default and a negative value disables the limit
+func (p *DetailPane) SetMaxFileBytes(n int64) {
+	if n == 0 {
+		n = engine.DefaultMaxFileBytes
+	}
+	p.maxFileBytes = n
 }
 
 // SetSize sets the pane dimensions
 func (p *DetailPane) SetSize(width, height int) {
 	p.width = width
 	p.height = height
+
+	if p.finding != nil {
+		p.loadSource()
+
+		// Content rewraps at the new width, so re-measure before clamping
+		p.contentLines = strings.Count(p.renderDetails(), "\n") + 1
+	}
+	p.clampScroll()
 }
 
 // SetFinding sets the finding to display
 func (p *DetailPane) SetFinding(finding *engine.Finding) {
+	if finding != p.finding {
+		p.scroll = 0
+	}
 	p.finding = finding
+
+	if finding != nil {
+		p.loadSource()
+	}
+}
+
+// loadSource shows the finding's file centred on its lines, reusing the
+// loaded file when consecutive findings share it
+func (p *DetailPane) loadSource() {
+	path := resolveFindingPath(p.projectRoot, p.finding.File)
+	if path != p.sourcePath {
+		p.sourcePath = path
+		p.sourceErr = readableSource(path, p.maxFileBytes)
+		if p.sourceErr == "" {
+			if err := p.source.SetFile(path); err != nil {
+				p.sourceErr = fmt.Sprintf("Cannot read file: %v", err)
+			}
+		}
+	}
+	if p.sourceErr != "" {
+		return
+	}
+
+	highlights := make(map[int]bool)
+	for line := p.finding.LineStart; line <= max(p.finding.LineEnd, p.finding.LineStart); line++ {
+		highlights[line] = true
+	}
+	p.source.SetHighlights(highlights)
+	p.source.SetSize(max(p.width-12, 10), sourceContextLines)
+	p.source.JumpToLine(p.finding.LineStart)
+}
+
+// readableSource returns why a file can't be shown, or "" if it can
+func readableSource(path string, maxBytes int64) string {
+	// The source view reads from disk, so archive entries can't be shown
+	if archivePath, _, ok := strings.Cut(path, engine.ArchiveSeparator); ok {
+		if _, err := os.Stat(archivePath); os.IsNotExist(err) {
+			return "Archive no longer exists"
+		}
+		return "Source of files inside archives can't be shown"
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "File no longer exists"
+		}
+		return fmt.Sprintf("Cannot read file: %v", err)
+	}
+	if maxBytes > 0 && info.Size() > maxBytes {
+		return fmt.Sprintf("File too large to display (%d KB)", info.Size()/1024)
+	}
+
+	// Same check as the scanner, so skipped and shown files agree
+	binary, err := engine.IsBinaryFile(path)
+	if err != nil {
+		return fmt.Sprintf("Cannot read file: %v", err)
+	}
+	if binary {
+		return "Binary file"
+	}
+
+	return ""
+}
+
+// resolveFindingPath resolves a finding path against the project root
+func resolveFindingPath(root, path string) string {
+	if filepath.IsAbs(path) || root == "" {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// visibleLines returns how many content lines fit below the title
+func (p *DetailPane) visibleLines() int {
+	return max(p.height-3, 1) // Account for title and borders
+}
+
+// ScrollBy moves the viewport by delta lines
+func (p *DetailPane) ScrollBy(delta int) {
+	p.scroll += delta
+	p.clampScroll()
+}
+
+// PageSize returns how far a page up/down scrolls
+func (p *DetailPane) PageSize() int {
+	return max(p.visibleLines()-1, 1)
+}
+
+// clampScroll keeps the viewport within the rendered content
+func (p *DetailPane) clampScroll() {
+	p.scroll = min(p.scroll, p.contentLines-p.visibleLines())
+	p.scroll = max(p.scroll, 0)
 }
 
 // View renders the detail pane
@@ -48,14 +194,22 @@ func (p *DetailPane) View(focused bool) string {
 		Width(p.width - 2).
 		Height(p.height - 2)
 
+	// Create content, keeping only the lines inside the viewport
+	lines := strings.Split(p.renderDetails(), "\n")
+	p.contentLines = len(lines)
+	p.clampScroll()
+	visible := p.visibleLines()
+	end := min(p.scroll+visible, len(lines))
+	content := strings.Join(lines[p.scroll:end], "\n")
+
 	// Create title
 	title := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(borderColor)).
 		Bold(true).
 		Render(" FINDING DETAILS ")
-
-	// Create content
-	content := p.renderDetails()
+	if len(lines) > visible {
+		title += theme.MutedStyle.Render(p.scrollIndicator(end))
+	}
 
 	// Combine title and content
 	fullContent := title + "\n" + content
@@ -63,6 +217,18 @@ func (p *DetailPane) View(focused bool) string {
 	return borderStyle.Render(fullContent)
 }
 
+// scrollIndicator shows which lines are visible and where more content is
+func (p *DetailPane) scrollIndicator(end int) string {
+	arrows := ""
+	if p.scroll > 0 {
+		arrows += "↑"
+	}
+	if end < p.contentLines {
+		arrows += "↓"
+	}
+	return fmt.Sprintf(" %d-%d/%d %s", p.scroll+1, end, p.contentLines, arrows)
+}
+
 // renderDetails renders the finding details
 func (p *DetailPane) renderDetails() string {
 	if p.finding == nil {
@@ -84,6 +250,10 @@ func (p *DetailPane) renderDetails() string {
 	sections = append(sections, p.renderMessage())
 	sections = append(sections, "")
 
+	// Source file around the finding
+	sections = append(sections, p.renderSource())
+	sections = append(sections, "")
+
 	// Code snippet if available
 	if p.finding.Code != "" {
 		sections = append(sections, p.renderCode())
@@ -129,11 +299,20 @@ func (p *DetailPane) renderFileInfo() string {
 	// Type/Kind
 	lines = append(lines, labelStyle.Render("Type: ")+valueStyle.Render(p.finding.Kind))
 
-	// Pass
-	if p.finding.Pass != "" {
+	// Pass, or every pass when near-duplicates were merged
+	if len(p.finding.Passes) > 1 {
+		lines = append(lines, labelStyle.Render("Passes: ")+valueStyle.Render(strings.Join(p.finding.Passes, ", ")))
+	} else if p.finding.Pass != "" {
 		lines = append(lines, labelStyle.Render("Pass: ")+valueStyle.Render(p.finding.Pass))
 	}
 
+	// Last change to the flagged lines
+	if blame := p.finding.Blame; blame != nil {
+		lines = append(lines, labelStyle.Render("Last changed: ")+valueStyle.Render(
+			fmt.Sprintf("%s on %s (%s)", blame.Author, blame.CommitDate.Local().Format("2006-01-02"), blame.Commit),
+		))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -155,6 +334,26 @@ func (p *DetailPane) renderMessage() string {
 	return title + "\n" + message
 }
 
+// renderSource renders the lines of the file around the finding
+func (p *DetailPane) renderSource() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true)
+
+	title := labelStyle.Render("Source:")
+	if p.sourceErr != "" {
+		return title + "\n" + theme.MutedStyle.Render(p.sourceErr)
+	}
+
+	sourceStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Background(lipgloss.Color(theme.ColorCodeBackground)).
+		Padding(0, 1).
+		Width(p.width - 12)
+
+	return title + "\n" + sourceStyle.Render(strings.TrimRight(p.source.View(), "\n"))
+}
+
 // renderCode renders the code snippet
 func (p *DetailPane) renderCode() string {
 	labelStyle := lipgloss.NewStyle().
@@ -163,7 +362,7 @@ func (p *DetailPane) renderCode() string {
 
 	codeStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(theme.ColorInfo)).
-		Background(lipgloss.Color("#0d1117")). // Slightly lighter dark for code
+		Background(lipgloss.Color(theme.ColorCodeBackground)).
 		Padding(1, 2).
 		Width(p.width - 12)
 
@@ -11,26 +11,197 @@ import (
 
 // ListPane displays the findings list
 type ListPane struct {
-	findings []*engine.Finding
+	all      []*engine.Finding
+	findings []*engine.Finding // all, narrowed by the active filter
 	selected int
 	scroll   int
 	width    int
 	height   int
+
+	// Active filter; empty values match everything
+	query    string
+	severity engine.Severity
+
+	// Review statuses shown against each finding, nil if not tracked;
+	// reviewed counts the shown findings resolved or dismissed
+	review   *engine.ReviewState
+	reviewed int
+
+	// Rendered items of the findings on screen, keyed by render state
+	cache map[itemKey]string
+}
+
+// itemKey identifies a rendered finding item. Findings are keyed by
+// pointer rather than by HashFinding, which would hash every row on screen
+// each frame just to find its cache entry; the cache is reset whenever the
+// findings are replaced, so pointers can't go stale. A plain map is enough
+// because only the Bubble Tea loop renders the pane.
+type itemKey struct {
+	finding  *engine.Finding
+	severity engine.Severity
+	status   engine.ReviewStatus
+	selected bool
 }
 
 // NewListPane creates a new list pane
 func NewListPane(findings []*engine.Finding) *ListPane {
 	return &ListPane{
+		all:      findings,
 		findings: findings,
 		selected: 0,
 		scroll:   0,
+		cache:    make(map[itemKey]string),
+	}
+}
+
+// SetReviewState sets where review statuses are read from
+func (p *ListPane) SetReviewState(review *engine.ReviewState) {
+	p.review = review
+	p.countReviewed()
+}
+
+// ReviewChanged recounts the reviewed findings after a status was set
+func (p *ListPane) ReviewChanged() {
+	p.countReviewed()
+}
+
+// reviewStatus returns the review status of a finding
+func (p *ListPane) reviewStatus(finding *engine.Finding) engine.ReviewStatus {
+	if p.review == nil {
+		return engine.ReviewPending
+	}
+	return p.review.Status(finding)
+}
+
+// countReviewed counts the shown findings that are resolved or dismissed.
+// Each status lookup hashes the finding, so this runs when the findings,
+// filter or statuses change rather than every frame.
+func (p *ListPane) countReviewed() {
+	p.reviewed = 0
+	if p.review == nil {
+		return
+	}
+	for _, finding := range p.findings {
+		if p.reviewStatus(finding) != engine.ReviewPending {
+			p.reviewed++
+		}
+	}
+}
+
+// SetFilter narrows the list to findings of the given severity whose file or
+// message contains query, case-insensitively. Empty values match everything.
+func (p *ListPane) SetFilter(query string, severity engine.Severity) {
+	p.query = query
+	p.severity = severity
+	p.applyFilter()
+
+	p.scroll = 0
+	p.SetSelected(0)
+}
+
+// SetFindings replaces the findings, keeping the active filter and the
+// selected finding when it is still listed
+func (p *ListPane) SetFindings(findings []*engine.Finding) {
+	var selectedHash string
+	if p.selected < len(p.findings) {
+		selectedHash = engine.HashFinding(p.findings[p.selected])
+	}
+
+	p.all = findings
+	p.applyFilter()
+	p.cache = make(map[itemKey]string)
+
+	if selectedHash != "" {
+		for i, finding := range p.findings {
+			if engine.HashFinding(finding) == selectedHash {
+				p.selected = i
+				break
+			}
+		}
+	}
+	p.clampSelection()
+}
+
+// AppendFinding adds a finding streamed in during a run. Only the new
+// finding is filtered, and the selection and rendered items are kept.
+func (p *ListPane) AppendFinding(finding *engine.Finding) {
+	p.all = append(p.all, finding)
+	if !p.Filtered() {
+		p.findings = p.all
+	} else if p.matchesFilter(finding) {
+		p.findings = append(p.findings, finding)
+	} else {
+		return
+	}
+
+	if p.review != nil && p.reviewStatus(finding) != engine.ReviewPending {
+		p.reviewed++
 	}
+	p.clampSelection()
+}
+
+// applyFilter narrows all to the findings matching the active filter
+func (p *ListPane) applyFilter() {
+	defer p.countReviewed()
+
+	if !p.Filtered() {
+		p.findings = p.all
+		return
+	}
+
+	p.findings = make([]*engine.Finding, 0, len(p.all))
+	for _, finding := range p.all {
+		if p.matchesFilter(finding) {
+			p.findings = append(p.findings, finding)
+		}
+	}
+}
+
+// matchesFilter reports whether a finding matches the active filter
+func (p *ListPane) matchesFilter(finding *engine.Finding) bool {
+	if p.severity != "" && finding.Severity != p.severity {
+		return false
+	}
+	needle := strings.ToLower(p.query)
+	return needle == "" ||
+		strings.Contains(strings.ToLower(finding.File), needle) ||
+		strings.Contains(strings.ToLower(finding.Message), needle)
+}
+
+// Findings returns the findings currently shown
+func (p *ListPane) Findings() []*engine.Finding {
+	return p.findings
+}
+
+// Filtered reports whether a filter is active
+func (p *ListPane) Filtered() bool {
+	return p.query != "" || p.severity != ""
 }
 
 // SetSize sets the pane dimensions
 func (p *ListPane) SetSize(width, height int) {
+	if width != p.width {
+		// Cached items are rendered at a fixed width
+		p.cache = make(map[itemKey]string)
+	}
 	p.width = width
 	p.height = height
+
+	// Keep the selection on screen and the viewport filled after a resize
+	p.clampSelection()
+}
+
+// clampSelection keeps the selection within the list and on screen, with
+// the viewport filled
+func (p *ListPane) clampSelection() {
+	p.scroll = min(p.scroll, len(p.findings)-p.visibleCount())
+	p.scroll = max(p.scroll, 0)
+	p.SetSelected(min(p.selected, max(len(p.findings)-1, 0)))
+}
+
+// visibleCount returns how many items fit in the pane
+func (p *ListPane) visibleCount() int {
+	return max(p.height-4, 1) // Account for title and borders
 }
 
 // SetSelected sets the selected index
@@ -38,7 +209,7 @@ func (p *ListPane) SetSelected(idx int) {
 	p.selected = idx
 
 	// Adjust scroll if needed
-	visibleCount := p.height - 4 // Account for title and borders
+	visibleCount := p.visibleCount()
 	if p.selected < p.scroll {
 		p.scroll = p.selected
 	} else if p.selected >= p.scroll+visibleCount {
@@ -63,10 +234,17 @@ func (p *ListPane) View(focused bool) string {
 		Height(p.height - 2)
 
 	// Create title
+	count := fmt.Sprintf("%d", len(p.findings))
+	if p.Filtered() {
+		count = fmt.Sprintf("%d/%d", len(p.findings), len(p.all))
+	}
+	if p.reviewed > 0 {
+		count += fmt.Sprintf(", %d reviewed", p.reviewed)
+	}
 	title := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(borderColor)).
 		Bold(true).
-		Render(fmt.Sprintf(" FINDINGS (%d) ", len(p.findings)))
+		Render(fmt.Sprintf(" FINDINGS (%s) ", count))
 
 	// Create content
 	content := p.renderFindings()
@@ -85,13 +263,16 @@ func (p *ListPane) renderFindings() string {
 			Background(lipgloss.Color(theme.ColorBackground)).
 			Padding(1, 2)
 
-		return emptyStyle.Render("No findings to display\n\nRun a scan first")
+		if p.Filtered() {
+			return emptyStyle.Render("No findings match the filter\n\nPress esc to clear it")
+		}
+		return emptyStyle.Render("No findings to display\n\nPress ctrl+r to re-analyze")
 	}
 
 	var items []string
 
-	// Calculate visible range
-	visibleCount := p.height - 4
+	// Calculate visible range; only these items are ever rendered
+	visibleCount := p.visibleCount()
 	start := p.scroll
 	end := start + visibleCount
 	if end > len(p.findings) {
@@ -101,16 +282,43 @@ func (p *ListPane) renderFindings() string {
 	// Render visible findings
 	for i := start; i < end; i++ {
 		finding := p.findings[i]
-		items = append(items, p.renderFindingItem(finding, i == p.selected))
+		items = append(items, p.cachedFindingItem(finding, i == p.selected))
 	}
 
 	return strings.Join(items, "\n")
 }
 
+// cachedFindingItem returns the rendered item, rendering it only when the
+// finding's severity, review status or selection state changed since the
+// last frame
+func (p *ListPane) cachedFindingItem(finding *engine.Finding, isSelected bool) string {
+	status := p.reviewStatus(finding)
+	key := itemKey{finding: finding, severity: finding.Severity, status: status, selected: isSelected}
+	if item, ok := p.cache[key]; ok {
+		return item
+	}
+
+	// Only items on screen are rendered, so a cache well past a screenful
+	// holds mostly rows scrolled away or states no longer shown
+	if len(p.cache) >= 4*p.visibleCount() {
+		p.cache = make(map[itemKey]string)
+	}
+
+	item := p.renderFindingItem(finding, status, isSelected)
+	p.cache[key] = item
+	return item
+}
+
 // renderFindingItem renders a single finding item
-func (p *ListPane) renderFindingItem(finding *engine.Finding, isSelected bool) string {
-	// Get severity icon
+func (p *ListPane) renderFindingItem(finding *engine.Finding, status engine.ReviewStatus, isSelected bool) string {
+	// Get severity icon, replaced by a mark once the finding is reviewed
 	icon := theme.SeverityIcon(string(finding.Severity))
+	switch status {
+	case engine.ReviewResolved:
+		icon = "✓"
+	case engine.ReviewDismissed:
+		icon = "✗"
+	}
 
 	// Create short label
 	fileName := finding.File
@@ -134,7 +342,8 @@ func (p *ListPane) renderFindingItem(finding *engine.Finding, isSelected bool) s
 			Foreground(lipgloss.Color(theme.ColorTextPrimary)).
 			Bold(true).
 			Padding(0, 1).
-			Width(p.width - 6)
+			Width(p.width - 6).
+			Strikethrough(status == engine.ReviewResolved)
 
 		return selectedStyle.Render("▶ " + label)
 	} else {
@@ -145,6 +354,14 @@ func (p *ListPane) renderFindingItem(finding *engine.Finding, isSelected bool) s
 			Padding(0, 1).
 			Width(p.width - 6)
 
+		// Reviewed findings recede so pending ones stand out
+		if status != engine.ReviewPending {
+			unselectedStyle = unselectedStyle.Foreground(lipgloss.Color(theme.ColorMuted))
+		}
+		if status == engine.ReviewResolved {
+			unselectedStyle = unselectedStyle.Strikethrough(true)
+		}
+
 		return unselectedStyle.Render("  " + label)
 	}
 }
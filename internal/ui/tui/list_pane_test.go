@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+)
+
+func BenchmarkListPaneView(b *testing.B) {
+	severities := []engine.Severity{
+		engine.SeverityCritical, engine.SeverityHigh, engine.SeverityMedium, engine.SeverityLow,
+	}
+	findings := make([]*engine.Finding, 10000)
+	for i := range findings {
+		findings[i] = &engine.Finding{
+			File:      fmt.Sprintf("internal/pkg%d/file%d.go", i%50, i),
+			LineStart: i%400 + 1,
+			LineEnd:   i%400 + 3,
+			Severity:  severities[i%len(severities)],
+			Message:   fmt.Sprintf("issue %d", i),
+		}
+	}
+
+	// Review every third finding so the statuses are looked up as in the TUI
+	review := &engine.ReviewState{}
+	for i := 0; i < len(findings); i += 3 {
+		review.SetStatus(findings[i], engine.ReviewResolved)
+	}
+
+	pane := NewListPane(findings)
+	pane.SetReviewState(review)
+	pane.SetSize(50, 40)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Walk the selection down the list so frames mix cached and new rows
+		pane.SetSelected(i % len(findings))
+		_ = pane.View(true)
+	}
+}
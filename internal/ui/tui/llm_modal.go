@@ -24,6 +24,11 @@ type LLMModal struct {
 	response  strings.Builder
 	err       error
 
+	// Stream channels and cancellation, set once the stream starts
+	tokens <-chan string
+	errs   <-chan error
+	cancel context.CancelFunc
+
 	// Dimensions
 	width  int
 	height int
@@ -47,26 +52,48 @@ func (m *LLMModal) Init() tea.Cmd {
 	return m.streamLLM()
 }
 
-// Update handles messages
-func (m *LLMModal) Update(msg tea.Msg) (LLMModal, tea.Cmd) {
+// Update handles messages. Stream messages from another modal, such as one
+// closed while its stream was in flight, are ignored.
+func (m *LLMModal) Update(msg tea.Msg) (*LLMModal, tea.Cmd) {
 	switch msg := msg.(type) {
+	case llmStreamStartedMsg:
+		if msg.modal != m {
+			msg.cancel()
+			return m, nil
+		}
+		m.tokens = msg.tokens
+		m.errs = msg.errs
+		m.cancel = msg.cancel
+		return m, m.waitForToken()
+
 	case llmTokenMsg:
-		// Append token to response
+		if msg.modal != m {
+			return m, nil
+		}
+		// Append token and wait for the next one
 		m.response.WriteString(msg.token)
-		return *m, nil
+		return m, m.waitForToken()
 
 	case llmCompleteMsg:
+		if msg.modal != m {
+			return m, nil
+		}
 		m.streaming = false
 		m.completed = true
-		return *m, nil
+		m.Close()
+		return m, nil
 
 	case llmErrorMsg:
+		if msg.modal != m {
+			return m, nil
+		}
 		m.streaming = false
 		m.err = msg.err
-		return *m, nil
+		m.Close()
+		return m, nil
 	}
 
-	return *m, nil
+	return m, nil
 }
 
 // View renders the modal
@@ -137,29 +164,52 @@ func (m *LLMModal) streamLLM() tea.Cmd {
 		case "anthropic":
 			apiKey := m.config.GetAPIKey("anthropic")
 			if apiKey == "" {
-				return llmErrorMsg{err: fmt.Errorf("Anthropic API key not set")}
+				return llmErrorMsg{modal: m, err: fmt.Errorf("Anthropic API key not set")}
 			}
-			provider = providers.NewAnthropicProvider(apiKey)
+			provider = providers.NewAnthropicProvider(apiKey, m.config.GetEndpoint("anthropic"))
 
 		case "openai":
 			apiKey := m.config.GetAPIKey("openai")
-			if apiKey == "" {
-				return llmErrorMsg{err: fmt.Errorf("OpenAI API key not set")}
+			baseURL := m.config.GetEndpoint("openai")
+			if apiKey == "" && baseURL == "" {
+				return llmErrorMsg{modal: m, err: fmt.Errorf("OpenAI API key not set")}
 			}
-			provider = providers.NewOpenAIProvider(apiKey)
+			provider = providers.NewOpenAIProvider(apiKey, baseURL)
 
 		case "google":
 			apiKey := m.config.GetAPIKey("google")
 			if apiKey == "" {
-				return llmErrorMsg{err: fmt.Errorf("Google API key not set")}
+				return llmErrorMsg{modal: m, err: fmt.Errorf("Google API key not set")}
 			}
-			provider = providers.NewGoogleProvider(apiKey)
+			provider = providers.NewGoogleProvider(apiKey, m.config.GetEndpoint("google"))
+
+		case "azure":
+			apiKey := m.config.GetAPIKey("azure")
+			if apiKey == "" {
+				return llmErrorMsg{modal: m, err: fmt.Errorf("Azure OpenAI API key not set")}
+			}
+			azure := m.config.Global.AzureOpenAI
+			if azure.Endpoint == "" {
+				return llmErrorMsg{modal: m, err: fmt.Errorf("Azure OpenAI endpoint not set")}
+			}
+			deployment := azure.Deployment
+			if deployment == "" {
+				deployment = modelSelection.Model
+			}
+			provider = providers.NewAzureOpenAIProvider(apiKey, azure.Endpoint, deployment, azure.APIVersion)
 
 		case "ollama":
-			provider = providers.NewOllamaProvider("http://localhost:11434")
+			provider = providers.NewOllamaProvider(m.config.GetEndpoint("ollama"))
 
 		default:
-			return llmErrorMsg{err: fmt.Errorf("unknown provider: %s", modelSelection.Provider)}
+			compatible, ok := m.config.GetCompatibleProvider(modelSelection.Provider)
+			if !ok {
+				return llmErrorMsg{modal: m, err: fmt.Errorf("unknown provider: %s", modelSelection.Provider)}
+			}
+			provider = engine.NewCompatibleProvider(modelSelection.Provider, compatible)
+		}
+		if err := engine.ConfigureHTTP(m.config, modelSelection.Provider, provider); err != nil {
+			return llmErrorMsg{modal: m, err: err}
 		}
 
 		// Build prompt
@@ -168,36 +218,57 @@ func (m *LLMModal) streamLLM() tea.Cmd {
 		// Create request options
 		opts := providers.DefaultRequestOptions()
 		opts.Model = modelSelection.Model
+		opts.Timeout = m.config.GetRequestTimeout()
 		opts.SystemPrompt = "You are a code fixing assistant. Provide concise, actionable fixes with patches in unified diff format."
 
-		// Stream response
-		ctx := context.Background()
+		// Stream response; the modal reads tokens as they arrive
+		ctx, cancel := context.WithCancel(context.Background())
 		tokenChan, errChan := provider.Stream(ctx, prompt, opts)
 
-		// Read stream (this is synchronous for simplicity)
-		// In a real implementation, we'd use a goroutine and send messages back
-		var fullResponse strings.Builder
+		return llmStreamStartedMsg{modal: m, tokens: tokenChan, errs: errChan, cancel: cancel}
+	}
+}
+
+// waitForToken returns a command that yields the next token, or completion
+// once the token channel closes
+func (m *LLMModal) waitForToken() tea.Cmd {
+	tokens, errs := m.tokens, m.errs
 
+	return func() tea.Msg {
 		for {
 			select {
-			case token, ok := <-tokenChan:
+			case token, ok := <-tokens:
 				if !ok {
-					// Channel closed, streaming complete
-					return llmCompleteMsg{}
+					// Providers send any error before closing the stream
+					if errs != nil {
+						if err, ok := <-errs; ok && err != nil {
+							return llmErrorMsg{modal: m, err: err}
+						}
+					}
+					return llmCompleteMsg{modal: m}
 				}
-				fullResponse.WriteString(token)
-				// Send token message
-				return llmTokenMsg{token: token}
+				return llmTokenMsg{modal: m, token: token}
 
-			case err, ok := <-errChan:
-				if ok && err != nil {
-					return llmErrorMsg{err: err}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if err != nil {
+					return llmErrorMsg{modal: m, err: err}
 				}
 			}
 		}
 	}
 }
 
+// Close stops an in-flight stream
+func (m *LLMModal) Close() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
 // buildPrompt builds the prompt for the LLM
 func (m *LLMModal) buildPrompt() string {
 	var prompt strings.Builder
@@ -223,15 +294,27 @@ func (m *LLMModal) buildPrompt() string {
 	return prompt.String()
 }
 
-// llmTokenMsg is sent when a token is received
+// llmStreamStartedMsg carries the channels of a stream that modal started
+type llmStreamStartedMsg struct {
+	modal  *LLMModal
+	tokens <-chan string
+	errs   <-chan error
+	cancel context.CancelFunc
+}
+
+// llmTokenMsg is sent when modal's stream receives a token
 type llmTokenMsg struct {
+	modal *LLMModal
 	token string
 }
 
-// llmCompleteMsg is sent when streaming completes
-type llmCompleteMsg struct{}
+// llmCompleteMsg is sent when modal's stream completes
+type llmCompleteMsg struct {
+	modal *LLMModal
+}
 
-// llmErrorMsg is sent when an error occurs
+// llmErrorMsg is sent when modal's stream fails
 type llmErrorMsg struct {
-	err error
+	modal *LLMModal
+	err   error
 }
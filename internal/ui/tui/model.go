@@ -1,11 +1,21 @@
 package tui
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/cloudboy-jh/churn-plus/internal/config"
 	"github.com/cloudboy-jh/churn-plus/internal/engine"
 	"github.com/cloudboy-jh/churn-plus/internal/theme"
+	"github.com/cloudboy-jh/churn-plus/internal/ui/help"
+	"github.com/cloudboy-jh/churn-plus/internal/ui/panes"
 )
 
 // PaneFocus represents which pane has focus
@@ -19,6 +29,21 @@ const (
 // BackToMenuMsg is sent when user wants to return to menu
 type BackToMenuMsg struct{}
 
+// ReanalyzeMsg is sent when user asks for a fresh analysis run
+type ReanalyzeMsg struct{}
+
+// statusDuration is how long a transient status message stays up
+const statusDuration = 3 * time.Second
+
+// runningTickMsg refreshes the running view's elapsed time
+type runningTickMsg struct{}
+
+// clearStatusMsg clears the status message it was scheduled for, unless a
+// newer one has replaced it
+type clearStatusMsg struct {
+	seq int
+}
+
 // Model is the main two-pane TUI model
 type Model struct {
 	projectRoot string
@@ -34,12 +59,38 @@ type Model struct {
 	selectedIdx int
 	width       int
 	height      int
+	banner      string
+
+	// running is set while the analysis is still adding findings. The
+	// running view shows its progress until tab switches to the findings.
+	running      bool
+	runningView  bool
+	pipelinePane *panes.PipelinePane
+
+	// Transient status shown in place of the key hints
+	status    string
+	statusSeq int
+
+	// fileHashes maps file paths to their content hash at analysis time
+	fileHashes map[string]string
+
+	// review holds resolved/dismissed marks, persisted across sessions
+	review *engine.ReviewState
+
+	// Findings list filter
+	searching      bool
+	searchInput    textinput.Model
+	severityFilter engine.Severity
 
 	// Modal state
+	showHelp          bool
 	showLLMModal      bool
 	llmModal          *LLMModal
 	showPatchPreview  bool
 	patchPreviewModal *PatchPreviewModal
+	showPatchEditor   bool
+	patchEditor       *InteractivePatchEditor
+	patchEditorHash   string // Hash the edited patch is checked against
 }
 
 // NewModel creates a new TUI model
@@ -55,6 +106,23 @@ func NewModel(projectRoot string, findings []*engine.Finding, cfg *config.Config
 	// Create panes
 	m.listPane = NewListPane(findings)
 	m.detailPane = NewDetailPane()
+	m.pipelinePane = panes.NewPipelinePane()
+	m.detailPane.SetProjectRoot(projectRoot)
+	m.detailPane.SetMaxFileBytes(cfg.Project.MaxFileBytes)
+	m.detailPane.SetUISettings(cfg.Global.UI)
+
+	// Restore review progress from earlier sessions
+	review, err := engine.LoadReviewState(projectRoot)
+	if err != nil {
+		review = &engine.ReviewState{}
+		m.status = fmt.Sprintf("Review state not restored: %v", err)
+	}
+	m.review = review
+	m.listPane.SetReviewState(review)
+
+	m.searchInput = textinput.New()
+	m.searchInput.Prompt = "/"
+	m.searchInput.Placeholder = "search file or message"
 
 	// Set initial selection
 	if len(findings) > 0 {
@@ -64,6 +132,71 @@ func NewModel(projectRoot string, findings []*engine.Finding, cfg *config.Config
 	return m
 }
 
+// SetFileHashes records the content hash of each file at analysis time so
+// patches are not applied to files that changed since
+func (m *Model) SetFileHashes(hashes map[string]string) {
+	m.fileHashes = hashes
+}
+
+// SetBanner sets a one-line notice shown above the panes
+func (m *Model) SetBanner(banner string) {
+	m.banner = banner
+	m.SetSize(m.width, m.height)
+}
+
+// SetRunning marks whether the analysis is still running, with findings
+// arriving through HandleEvent. A running analysis opens on the running
+// view; once it finishes the findings are shown.
+func (m *Model) SetRunning(running bool) {
+	m.running = running
+	m.runningView = running
+	m.SetSize(m.width, m.height)
+}
+
+// SetPasses sets the passes the running view shows, copied before the
+// pipeline started; their progress then arrives through HandleEvent
+func (m *Model) SetPasses(passes []engine.Pass) {
+	m.pipelinePane.SetPasses(passes)
+}
+
+// HandleEvent applies a pipeline event of the running analysis, updating
+// pass progress and adding reported findings as they arrive
+func (m *Model) HandleEvent(event engine.PipelineEvent) {
+	m.pipelinePane.HandleEvent(event)
+	if event.Type == engine.EventFindingAdded && event.Finding != nil {
+		m.appendFinding(event.Finding)
+	}
+}
+
+// appendFinding adds a finding reported during the run. The detail pane is
+// only reloaded when the selection moves, e.g. onto the first finding.
+func (m *Model) appendFinding(finding *engine.Finding) {
+	selected := m.selectedFinding()
+	m.findings = append(m.findings, finding)
+	m.listPane.AppendFinding(finding)
+	m.selectedIdx = m.listPane.selected
+	if current := m.selectedFinding(); current != selected {
+		m.detailPane.SetFinding(current)
+	}
+}
+
+// SetFindings replaces the findings, such as with the final report once the
+// analysis finishes, keeping the filter and selection
+func (m *Model) SetFindings(findings []*engine.Finding) {
+	m.findings = findings
+	m.listPane.SetFindings(findings)
+	m.selectedIdx = m.listPane.selected
+	m.detailPane.SetFinding(m.selectedFinding())
+}
+
+// bannerText returns the notice shown above the panes, if any
+func (m *Model) bannerText() string {
+	if m.running {
+		return fmt.Sprintf("Analyzing... %d findings so far. Press ctrl+x to cancel.", len(m.findings))
+	}
+	return m.banner
+}
+
 // SetSize sets the model dimensions
 func (m *Model) SetSize(width, height int) {
 	m.width = width
@@ -73,6 +206,9 @@ func (m *Model) SetSize(width, height int) {
 	leftWidth := width / 3
 	rightWidth := width - leftWidth
 	paneHeight := height - 2 // Reserve space for status bar
+	if m.bannerText() != "" {
+		paneHeight-- // Reserve space for banner
+	}
 
 	if m.listPane != nil {
 		m.listPane.SetSize(leftWidth, paneHeight)
@@ -80,15 +216,66 @@ func (m *Model) SetSize(width, height int) {
 	if m.detailPane != nil {
 		m.detailPane.SetSize(rightWidth, paneHeight)
 	}
+	if m.patchEditor != nil {
+		m.patchEditor.SetSize(width*9/10, height*8/10)
+	}
+	if m.pipelinePane != nil {
+		// Passes are few, so the pane shows them all and recent findings
+		// take the remaining rows
+		m.pipelinePane.SetSize(width-6, height)
+	}
 }
 
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
+	if m.running {
+		return tickRunning()
+	}
 	return nil
 }
 
+// tickRunning schedules the next refresh of the running view
+func tickRunning() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return runningTickMsg{}
+	})
+}
+
 // Update handles messages
 func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
+	if msg, ok := msg.(clearStatusMsg); ok {
+		if msg.seq == m.statusSeq {
+			m.status = ""
+		}
+		return m, nil
+	}
+	if _, ok := msg.(runningTickMsg); ok {
+		if m.running {
+			return m, tickRunning()
+		}
+		return m, nil
+	}
+
+	// The help overlay sits above everything, including other modals
+	if msg, ok := msg.(tea.KeyMsg); ok && !m.searching {
+		if m.showHelp {
+			if help.IsCloseKey(msg.String()) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if msg.String() == help.ToggleKey {
+			m.showHelp = true
+			return m, nil
+		}
+	}
+
+	// A stream that started after its modal was closed is stopped right away
+	if msg, ok := msg.(llmStreamStartedMsg); ok && msg.modal != m.llmModal {
+		msg.cancel()
+		return m, nil
+	}
+
 	// Handle modal updates first
 	if m.showLLMModal {
 		return m.updateLLMModal(msg)
@@ -96,18 +283,106 @@ func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
 	if m.showPatchPreview {
 		return m.updatePatchPreview(msg)
 	}
+	if m.showPatchEditor {
+		return m.updatePatchEditor(msg)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.running && m.runningView {
+			return m.handleRunningKey(msg)
+		}
+		if m.searching {
+			return m.updateSearch(msg)
+		}
 		return m.handleKeyPress(msg)
 	}
 
 	return m, nil
 }
 
+// severityKeys maps list pane keys to the severity they filter by
+var severityKeys = map[string]engine.Severity{
+	"1": engine.SeverityCritical,
+	"2": engine.SeverityHigh,
+	"3": engine.SeverityMedium,
+	"4": engine.SeverityLow,
+}
+
+// updateSearch handles input while the search prompt is open
+func (m *Model) updateSearch(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		// Keep the filter and return to the list
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		m.searchInput.Reset()
+		m.applyFilter()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.applyFilter()
+	return m, cmd
+}
+
+// applyFilter narrows the findings list to the current search and severity
+// filter and selects the first match
+func (m *Model) applyFilter() {
+	m.listPane.SetFilter(m.searchInput.Value(), m.severityFilter)
+	m.selectedIdx = 0
+	m.detailPane.SetFinding(m.selectedFinding())
+}
+
+// selectedFinding returns the selected finding of the filtered list, or nil
+// when nothing is shown
+func (m *Model) selectedFinding() *engine.Finding {
+	shown := m.listPane.Findings()
+	if m.selectedIdx >= len(shown) {
+		return nil
+	}
+	return shown[m.selectedIdx]
+}
+
+// handleRunningKey handles keyboard input on the running view
+func (m *Model) handleRunningKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab":
+		// Browse the findings reported so far
+		m.runningView = false
+
+	case "q":
+		return m, tea.Quit
+
+	case "m":
+		return m, func() tea.Msg {
+			return BackToMenuMsg{}
+		}
+
+	case "ctrl+r":
+		return m, func() tea.Msg {
+			return ReanalyzeMsg{}
+		}
+	}
+
+	return m, nil
+}
+
 // handleKeyPress handles keyboard input
 func (m *Model) handleKeyPress(msg tea.KeyMsg) (*Model, tea.Cmd) {
 	switch msg.String() {
+	case "tab":
+		if m.running {
+			// Back to the pipeline progress
+			m.runningView = true
+		}
+
 	case "q":
 		if m.focus == FocusDetailPane {
 			// Return to list pane
@@ -123,47 +398,221 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (*Model, tea.Cmd) {
 			return BackToMenuMsg{}
 		}
 
+	case "ctrl+r":
+		// Re-run analysis
+		return m, func() tea.Msg {
+			return ReanalyzeMsg{}
+		}
+
+	case "/":
+		if m.focus == FocusListPane {
+			m.searching = true
+			return m, m.searchInput.Focus()
+		}
+
+	case "1", "2", "3", "4":
+		if m.focus == FocusListPane {
+			// Pressing the active severity again clears it
+			severity := severityKeys[msg.String()]
+			if m.severityFilter == severity {
+				severity = ""
+			}
+			m.severityFilter = severity
+			m.applyFilter()
+		}
+
+	case "esc":
+		if m.focus == FocusListPane && m.listPane.Filtered() {
+			m.severityFilter = ""
+			m.searchInput.Reset()
+			m.applyFilter()
+		}
+
+	case "b":
+		if m.focus == FocusListPane && len(m.findings) > 0 {
+			// Accept the current findings into the baseline
+			return m.saveBaseline()
+		}
+
 	case "up":
 		if m.focus == FocusListPane {
 			m.navigateList(-1)
+		} else {
+			m.detailPane.ScrollBy(-1)
 		}
 
 	case "down":
 		if m.focus == FocusListPane {
 			m.navigateList(1)
+		} else {
+			m.detailPane.ScrollBy(1)
+		}
+
+	case "k":
+		if m.focus == FocusDetailPane {
+			m.detailPane.ScrollBy(-1)
+		}
+
+	case "j":
+		if m.focus == FocusDetailPane {
+			m.detailPane.ScrollBy(1)
+		}
+
+	case "pgup":
+		if m.focus == FocusDetailPane {
+			m.detailPane.ScrollBy(-m.detailPane.PageSize())
+		}
+
+	case "pgdown":
+		if m.focus == FocusDetailPane {
+			m.detailPane.ScrollBy(m.detailPane.PageSize())
 		}
 
 	case "enter":
-		if m.focus == FocusListPane {
+		if m.focus == FocusListPane && m.selectedFinding() != nil {
 			// Switch to detail pane
 			m.focus = FocusDetailPane
 		}
 
 	case "l":
-		if m.focus == FocusDetailPane && len(m.findings) > 0 {
+		if m.focus == FocusDetailPane && m.selectedFinding() != nil {
 			// Send to LLM
 			return m.openLLMModal()
 		}
 
 	case "p":
-		if m.focus == FocusDetailPane && len(m.findings) > 0 {
+		if m.focus == FocusDetailPane && m.selectedFinding() != nil {
 			// Preview patch
 			return m.openPatchPreview()
 		}
 
 	case "a":
-		if m.focus == FocusDetailPane && len(m.findings) > 0 {
+		if m.focus == FocusDetailPane && m.selectedFinding() != nil {
 			// Apply patch
 			return m.applyPatch()
 		}
+
+	case "i":
+		if m.focus == FocusDetailPane && m.selectedFinding() != nil {
+			// Pick lines of the patch interactively
+			return m.openPatchEditor()
+		}
+
+	case "r":
+		return m, m.toggleReview(engine.ReviewResolved)
+
+	case "x":
+		return m, m.toggleReview(engine.ReviewDismissed)
+
+	case "y":
+		if finding := m.selectedFinding(); m.focus == FocusDetailPane && finding != nil {
+			return m, m.copyToClipboard(findingText(finding))
+		}
+	}
+
+	return m, nil
+}
+
+// toggleReview marks the selected finding with status, or back to pending if
+// it already has it, and saves the review state
+func (m *Model) toggleReview(status engine.ReviewStatus) tea.Cmd {
+	finding := m.selectedFinding()
+	if finding == nil {
+		return nil
+	}
+
+	if m.review.Status(finding) == status {
+		status = engine.ReviewPending
+	}
+	m.review.SetStatus(finding, status)
+	m.listPane.ReviewChanged()
+
+	if err := m.review.Save(m.projectRoot); err != nil {
+		return m.setStatus(fmt.Sprintf("Failed to save review state: %v", err))
+	}
+	return m.setStatus(fmt.Sprintf("Marked %s", status))
+}
+
+// setStatus shows a message in the status bar for statusDuration
+func (m *Model) setStatus(status string) tea.Cmd {
+	m.status = status
+	m.statusSeq++
+	seq := m.statusSeq
+	return tea.Tick(statusDuration, func(time.Time) tea.Msg {
+		return clearStatusMsg{seq: seq}
+	})
+}
+
+// copyToClipboard copies text and reports where it went
+func (m *Model) copyToClipboard(text string) tea.Cmd {
+	note, err := copyText(text)
+	if err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	return m.setStatus(note)
+}
+
+// copyPatch copies the unified diff for the selected finding
+func (m *Model) copyPatch() (*Model, tea.Cmd) {
+	finding := m.selectedFinding()
+	if finding == nil {
+		return m, nil
+	}
+
+	content, err := os.ReadFile(m.resolvePath(finding.File))
+	if err != nil {
+		return m, m.setStatus(fmt.Sprintf("Cannot open %s: %v", finding.File, err))
+	}
+
+	diff, err := engine.ApplyFindingSuggestion(finding, string(content))
+	if err != nil {
+		return m, m.setStatus(fmt.Sprintf("No patch available: %v", err))
 	}
 
+	return m, m.copyToClipboard(diff.FormatUnified())
+}
+
+// findingText formats a finding for pasting into another tool
+func findingText(finding *engine.Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d", finding.File, finding.LineStart)
+	if finding.LineEnd > finding.LineStart {
+		fmt.Fprintf(&b, "-%d", finding.LineEnd)
+	}
+	fmt.Fprintf(&b, " [%s] %s\n%s\n", finding.Severity, finding.Kind, finding.Message)
+	if finding.Code != "" {
+		fmt.Fprintf(&b, "\n%s\n", finding.Code)
+	}
+	return b.String()
+}
+
+// saveBaseline adds the shown findings to .churn/baseline.json so future
+// reports leave them out when baseline filtering is enabled
+func (m *Model) saveBaseline() (*Model, tea.Cmd) {
+	baseline, err := engine.LoadBaseline(m.projectRoot)
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("Failed to save baseline: %v", err))
+		return m, nil
+	}
+
+	baseline.Add(m.findings)
+	if err := baseline.Save(m.projectRoot); err != nil {
+		m.SetBanner(fmt.Sprintf("Failed to save baseline: %v", err))
+		return m, nil
+	}
+
+	banner := fmt.Sprintf("Baseline now holds %d findings", baseline.Len())
+	if !m.config.Project.UseBaseline {
+		banner += " (set use_baseline in the project config to filter them)"
+	}
+	m.SetBanner(banner)
 	return m, nil
 }
 
 // navigateList navigates the findings list
 func (m *Model) navigateList(delta int) {
-	if len(m.findings) == 0 {
+	shown := m.listPane.Findings()
+	if len(shown) == 0 {
 		return
 	}
 
@@ -171,14 +620,14 @@ func (m *Model) navigateList(delta int) {
 	if newIdx < 0 {
 		newIdx = 0
 	}
-	if newIdx >= len(m.findings) {
-		newIdx = len(m.findings) - 1
+	if newIdx >= len(shown) {
+		newIdx = len(shown) - 1
 	}
 
 	if newIdx != m.selectedIdx {
 		m.selectedIdx = newIdx
 		m.listPane.SetSelected(newIdx)
-		m.detailPane.SetFinding(m.findings[newIdx])
+		m.detailPane.SetFinding(shown[newIdx])
 	}
 }
 
@@ -188,16 +637,25 @@ func (m *Model) View() string {
 		return "Loading..."
 	}
 
-	// Render main two-pane layout
+	// Render main two-pane layout, or progress while the analysis runs
 	mainView := m.renderMainLayout()
+	if m.running && m.runningView {
+		mainView = m.renderRunningView()
+	}
 
 	// Overlay modal if active
+	if m.showHelp {
+		return m.renderModalOverlay(mainView, help.Render(m.helpSections()))
+	}
 	if m.showLLMModal && m.llmModal != nil {
 		return m.renderModalOverlay(mainView, m.llmModal.View())
 	}
 	if m.showPatchPreview && m.patchPreviewModal != nil {
 		return m.renderModalOverlay(mainView, m.patchPreviewModal.View())
 	}
+	if m.showPatchEditor && m.patchEditor != nil {
+		return m.renderModalOverlay(mainView, m.patchEditor.View())
+	}
 
 	return mainView
 }
@@ -219,17 +677,93 @@ func (m *Model) renderMainLayout() string {
 	statusBar := m.renderStatusBar()
 
 	// Join vertically
+	if m.bannerText() != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, m.renderBanner(), panes, statusBar)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, panes, statusBar)
 }
 
+// renderRunningView renders the pipeline's pass progress and the most
+// recent findings of the running analysis
+func (m *Model) renderRunningView() string {
+	boxStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		BorderBackground(lipgloss.Color(theme.ColorBackground)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Width(m.width-2).
+		Padding(0, 1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Bold(true)
+
+	pipelineBox := boxStyle.Render(titleStyle.Render(" PIPELINE ") + "\n" +
+		strings.TrimRight(m.pipelinePane.View(), "\n"))
+
+	// Recent findings fill the rows left below the pipeline
+	rows := m.height - lipgloss.Height(pipelineBox) - 5 // Banner, status bar, borders and title
+	rows = max(rows, 1)
+	recent := m.findings[max(len(m.findings)-rows, 0):]
+
+	lines := make([]string, 0, rows)
+	for i := len(recent) - 1; i >= 0; i-- {
+		finding := recent[i]
+		line := fmt.Sprintf("%s %s:%d  %s", theme.SeverityIcon(string(finding.Severity)),
+			finding.File, finding.LineStart, finding.Message)
+		lines = append(lines, ansi.Truncate(line, m.width-6, "..."))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, theme.MutedStyle.Render("No findings yet"))
+	}
+
+	findingsBox := boxStyle.
+		Height(rows + 1).
+		Render(titleStyle.Render(fmt.Sprintf(" RECENT FINDINGS (%d) ", len(m.findings))) + "\n" +
+			strings.Join(lines, "\n"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.renderBanner(), pipelineBox, findingsBox, m.renderStatusBar())
+}
+
+// renderBanner renders the notice line above the panes
+func (m *Model) renderBanner() string {
+	bannerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorWarning)).
+		Width(m.width).
+		Padding(0, 1)
+
+	return bannerStyle.Render(m.bannerText())
+}
+
 // renderStatusBar renders the bottom status bar
 func (m *Model) renderStatusBar() string {
 	var helpText string
 
-	if m.focus == FocusListPane {
-		helpText = "↑/↓: navigate | Enter: select | m: menu | q: quit"
+	if m.status != "" {
+		return lipgloss.NewStyle().
+			Background(lipgloss.Color(theme.ColorBackground)).
+			Foreground(lipgloss.Color(theme.ColorSuccess)).
+			Width(m.width).
+			Padding(0, 1).
+			Render(m.status)
+	}
+
+	if m.searching {
+		return m.renderSearchBar()
+	}
+
+	if m.running && m.runningView {
+		helpText = "?: help | tab: browse findings | ctrl+x: cancel | ctrl+r: restart | m: menu | q: quit"
+	} else if m.focus == FocusListPane {
+		helpText = "?: help | ↑/↓: navigate | Enter: select | /: search | 1-4: severity | r/x: resolve/dismiss | b: baseline all | ctrl+r: re-analyze | m: menu | q: quit"
+		if m.listPane.Filtered() {
+			helpText = "esc: clear filter | " + helpText
+		}
+		if m.running {
+			helpText = "tab: progress | " + helpText
+		}
 	} else {
-		helpText = "l: LLM hand-off | p: preview patch | a: apply | m: menu | q: back"
+		helpText = "?: help | j/k/pgup/pgdn: scroll | l: LLM hand-off | y: copy | p: preview patch | a: apply | i: interactive apply | m: menu | q: back"
 	}
 
 	statusStyle := lipgloss.NewStyle().
@@ -241,6 +775,99 @@ func (m *Model) renderStatusBar() string {
 	return statusStyle.Render(helpText)
 }
 
+// helpSections lists the keybindings for whatever currently has focus
+func (m *Model) helpSections() []help.Section {
+	quit := help.Section{Title: "General", Bindings: []help.Binding{
+		{Key: "?", Description: "Toggle this help"},
+		{Key: "m", Description: "Back to menu"},
+		{Key: "ctrl+r", Description: "Re-run analysis"},
+		{Key: "ctrl+c", Description: "Quit"},
+	}}
+	if m.running {
+		quit.Bindings = append(quit.Bindings, help.Binding{Key: "ctrl+x", Description: "Cancel the running analysis"})
+	}
+
+	switch {
+	case m.running && m.runningView:
+		return []help.Section{
+			{Title: "Running Analysis", Bindings: []help.Binding{
+				{Key: "tab", Description: "Browse the findings so far"},
+				{Key: "q", Description: "Quit"},
+			}},
+			quit,
+		}
+
+	case m.showLLMModal:
+		return []help.Section{{Title: "LLM Hand-off", Bindings: []help.Binding{
+			{Key: "a", Description: "Apply the patch from the response"},
+			{Key: "q/esc", Description: "Close"},
+		}}}
+
+	case m.showPatchPreview:
+		return []help.Section{{Title: "Patch Preview", Bindings: []help.Binding{
+			{Key: "a", Description: "Apply patch"},
+			{Key: "y", Description: "Copy the unified diff"},
+			{Key: "q/esc", Description: "Close"},
+		}}}
+
+	case m.showPatchEditor:
+		return []help.Section{{Title: "Interactive Patch", Bindings: []help.Binding{
+			{Key: "↑/↓ j/k", Description: "Move between changes"},
+			{Key: "y/n", Description: "Keep or drop the change"},
+			{Key: "enter", Description: "Apply the kept changes"},
+			{Key: "q/esc", Description: "Cancel"},
+		}}}
+
+	case m.focus == FocusDetailPane:
+		return []help.Section{
+			{Title: "Details", Bindings: []help.Binding{
+				{Key: "↑/↓ j/k", Description: "Scroll"},
+				{Key: "pgup/pgdn", Description: "Scroll a page"},
+				{Key: "q", Description: "Back to the list"},
+			}},
+			{Title: "Fixes", Bindings: []help.Binding{
+				{Key: "l", Description: "Hand off to an LLM"},
+				{Key: "p", Description: "Preview patch"},
+				{Key: "a", Description: "Apply patch"},
+				{Key: "i", Description: "Pick patch lines interactively"},
+				{Key: "y", Description: "Copy the finding"},
+			}},
+			{Title: "Review", Bindings: []help.Binding{
+				{Key: "r", Description: "Mark resolved (again to undo)"},
+				{Key: "x", Description: "Mark dismissed (again to undo)"},
+			}},
+			quit,
+		}
+
+	default:
+		return []help.Section{
+			{Title: "Findings", Bindings: []help.Binding{
+				{Key: "↑/↓", Description: "Navigate"},
+				{Key: "enter", Description: "Open details"},
+				{Key: "/", Description: "Search file or message"},
+				{Key: "1-4", Description: "Filter by critical/high/medium/low"},
+				{Key: "esc", Description: "Clear filters"},
+				{Key: "r", Description: "Mark resolved (again to undo)"},
+				{Key: "x", Description: "Mark dismissed (again to undo)"},
+				{Key: "b", Description: "Add all findings to the baseline"},
+				{Key: "q", Description: "Quit"},
+			}},
+			quit,
+		}
+	}
+}
+
+// renderSearchBar renders the search prompt in place of the status bar
+func (m *Model) renderSearchBar() string {
+	searchStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Width(m.width).
+		Padding(0, 1)
+
+	return searchStyle.Render(m.searchInput.View() + theme.MutedStyle.Render("  enter: keep | esc: clear"))
+}
+
 // renderModalOverlay renders a modal on top of the main view
 func (m *Model) renderModalOverlay(mainView, modalView string) string {
 	// Calculate modal position (centered)
@@ -266,9 +893,15 @@ func (m *Model) renderModalOverlay(mainView, modalView string) string {
 	// Simple overlay: just render modal centered
 	// For a true overlay effect, we'd need to draw the modal over the background
 	// For now, we'll just center it on a dark background
+	// Keep a row for the transient status so it stays visible over modals
+	height := m.height
+	if m.status != "" {
+		height--
+	}
+
 	centeredModal := lipgloss.Place(
 		m.width,
-		m.height,
+		height,
 		lipgloss.Center,
 		lipgloss.Center,
 		modalView,
@@ -276,16 +909,19 @@ func (m *Model) renderModalOverlay(mainView, modalView string) string {
 		lipgloss.WithWhitespaceForeground(lipgloss.Color(theme.ColorBackground)),
 	)
 
+	if m.status != "" {
+		centeredModal = lipgloss.JoinVertical(lipgloss.Left, centeredModal, m.renderStatusBar())
+	}
+
 	return overlayStyle.Render(centeredModal)
 }
 
 // openLLMModal opens the LLM modal
 func (m *Model) openLLMModal() (*Model, tea.Cmd) {
-	if len(m.findings) == 0 {
+	finding := m.selectedFinding()
+	if finding == nil {
 		return m, nil
 	}
-
-	finding := m.findings[m.selectedIdx]
 	m.llmModal = NewLLMModal(finding, m.config)
 	m.showLLMModal = true
 
@@ -296,25 +932,46 @@ func (m *Model) openLLMModal() (*Model, tea.Cmd) {
 func (m *Model) updateLLMModal(msg tea.Msg) (*Model, tea.Cmd) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		if msg.String() == "q" || msg.String() == "esc" {
+			m.llmModal.Close()
 			m.showLLMModal = false
 			m.llmModal = nil
 			return m, nil
 		}
+		if msg.String() == "a" && m.llmModal.completed {
+			return m.applyLLMPatch()
+		}
 	}
 
 	var cmd tea.Cmd
-	*m.llmModal, cmd = m.llmModal.Update(msg)
+	m.llmModal, cmd = m.llmModal.Update(msg)
 
 	return m, cmd
 }
 
+// applyLLMPatch applies the unified diff from the LLM modal's response
+func (m *Model) applyLLMPatch() (*Model, tea.Cmd) {
+	diff, err := engine.ParseUnifiedDiff(m.llmModal.response.String())
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("No patch available: %v", err))
+		return m, nil
+	}
+	// The model's header path is relative and unreliable; the finding is not
+	diff.FilePath = m.llmModal.finding.File
+
+	m.showLLMModal = false
+	m.llmModal = nil
+
+	// Hunks are located by their context and removed lines, and refused
+	// when those no longer match the file
+	return m.applyDiff(diff, "")
+}
+
 // openPatchPreview opens the patch preview modal
 func (m *Model) openPatchPreview() (*Model, tea.Cmd) {
-	if len(m.findings) == 0 {
+	finding := m.selectedFinding()
+	if finding == nil {
 		return m, nil
 	}
-
-	finding := m.findings[m.selectedIdx]
 	m.patchPreviewModal = NewPatchPreviewModal(finding)
 	m.showPatchPreview = true
 
@@ -334,15 +991,140 @@ func (m *Model) updatePatchPreview(msg tea.Msg) (*Model, tea.Cmd) {
 			m.showPatchPreview = false
 			m.patchPreviewModal = nil
 			return m.applyPatch()
+		case "y":
+			return m.copyPatch()
 		}
 	}
 
 	return m, nil
 }
 
+// openPatchEditor opens the interactive patch editor for the current finding
+func (m *Model) openPatchEditor() (*Model, tea.Cmd) {
+	finding := m.selectedFinding()
+	if finding == nil {
+		return m, nil
+	}
+
+	content, err := os.ReadFile(m.resolvePath(finding.File))
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("Cannot open %s: %v", finding.File, err))
+		return m, nil
+	}
+	if engine.IsBinaryContent(content) {
+		m.SetBanner(fmt.Sprintf("Cannot edit %s: binary file", finding.File))
+		return m, nil
+	}
+
+	expectedHash, err := m.patchGuard(finding)
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("Cannot patch: %v", err))
+		return m, nil
+	}
+
+	diff, err := engine.ApplyFindingSuggestion(finding, string(content))
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("No patch available: %v", err))
+		return m, nil
+	}
+
+	m.patchEditor = NewInteractivePatchEditor(diff)
+	m.patchEditorHash = expectedHash
+	m.patchEditor.SetSize(m.width*9/10, m.height*8/10)
+	m.showPatchEditor = true
+
+	return m, nil
+}
+
+// updatePatchEditor updates the interactive patch editor
+func (m *Model) updatePatchEditor(msg tea.Msg) (*Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "q", "esc":
+			m.showPatchEditor = false
+			m.patchEditor = nil
+			return m, nil
+		case "enter":
+			diff := m.patchEditor.Patch()
+			m.showPatchEditor = false
+			m.patchEditor = nil
+			return m.applyDiff(diff, m.patchEditorHash)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.patchEditor, cmd = m.patchEditor.Update(msg)
+
+	return m, cmd
+}
+
+// patchGuard returns the file hash a patch for finding is checked against.
+// Findings that recorded their source lines are checked line by line in
+// ApplyFindingSuggestion instead; older ones need the file unchanged since
+// the analysis, and are refused once it has been patched.
+func (m *Model) patchGuard(finding *engine.Finding) (string, error) {
+	if finding.Source != "" {
+		return "", nil
+	}
+	hash := m.fileHashes[finding.File]
+	if hash == "" {
+		return "", fmt.Errorf("%s changed or was not recorded by the analysis; re-run it", finding.File)
+	}
+	return hash, nil
+}
+
+// applyDiff writes a diff to the file it was generated from, backing up the
+// original and refusing if the file's hash no longer matches expectedHash
+func (m *Model) applyDiff(diff *engine.Diff, expectedHash string) (*Model, tea.Cmd) {
+	result, err := engine.ApplyDiffToFile(m.projectRoot, diff, expectedHash)
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("Failed to apply patch: %v", err))
+		return m, nil
+	}
+
+	// Line numbers of the file's other findings may have shifted. Findings
+	// with recorded source lines are found again where they moved; the rest
+	// are invalidated by dropping the hash they were checked against.
+	delete(m.fileHashes, diff.FilePath)
+
+	backup, err := filepath.Rel(m.projectRoot, result.BackupPath)
+	if err != nil {
+		backup = result.BackupPath
+	}
+	m.SetBanner(fmt.Sprintf("Applied patch to %s (+%d -%d), backup at %s",
+		diff.FilePath, result.Additions, result.Deletions, backup))
+	return m, nil
+}
+
+// resolvePath resolves a finding path against the project root
+func (m *Model) resolvePath(path string) string {
+	return resolveFindingPath(m.projectRoot, path)
+}
+
 // applyPatch applies the patch for the current finding
 func (m *Model) applyPatch() (*Model, tea.Cmd) {
-	// TODO: Implement patch application
-	// For now, just a placeholder
-	return m, nil
+	finding := m.selectedFinding()
+	if finding == nil {
+		return m, nil
+	}
+
+	content, err := os.ReadFile(m.resolvePath(finding.File))
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("Cannot open %s: %v", finding.File, err))
+		return m, nil
+	}
+
+	expectedHash, err := m.patchGuard(finding)
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("Cannot patch: %v", err))
+		return m, nil
+	}
+
+	diff, err := engine.ApplyFindingSuggestion(finding, string(content))
+	if err != nil {
+		m.SetBanner(fmt.Sprintf("No patch available: %v", err))
+		return m, nil
+	}
+
+	return m.applyDiff(diff, expectedHash)
 }
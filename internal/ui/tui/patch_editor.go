@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cloudboy-jh/churn-plus/internal/engine"
+	"github.com/cloudboy-jh/churn-plus/internal/theme"
+)
+
+// InteractivePatchEditor lets the user accept or reject each changed line of
+// a patch before it is applied, similar to `git add -p`
+type InteractivePatchEditor struct {
+	diff *engine.Diff
+
+	// lines is the flattened diff; changes indexes the added/removed lines
+	lines    []*engine.DiffLine
+	changes  []int
+	rejected map[int]bool
+
+	// State
+	cursor int // Index into changes
+	offset int // First visible row
+
+	// Dimensions
+	width  int
+	height int
+}
+
+// NewInteractivePatchEditor creates an editor over a diff
+func NewInteractivePatchEditor(diff *engine.Diff) *InteractivePatchEditor {
+	e := &InteractivePatchEditor{
+		diff:     diff,
+		rejected: make(map[int]bool),
+		width:    100,
+		height:   30,
+	}
+
+	for _, hunk := range diff.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type != engine.DiffLineContext {
+				e.changes = append(e.changes, len(e.lines))
+			}
+			e.lines = append(e.lines, line)
+		}
+	}
+
+	return e
+}
+
+// SetSize sets the editor dimensions
+func (e *InteractivePatchEditor) SetSize(width, height int) {
+	e.width = width
+	e.height = height
+}
+
+// Update handles key presses for accepting and rejecting lines
+func (e *InteractivePatchEditor) Update(msg tea.Msg) (*InteractivePatchEditor, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || len(e.changes) == 0 {
+		return e, nil
+	}
+
+	switch keyMsg.String() {
+	case "y":
+		delete(e.rejected, e.changes[e.cursor])
+		e.moveCursor(1)
+	case "n":
+		e.rejected[e.changes[e.cursor]] = true
+		e.moveCursor(1)
+	case "up", "k":
+		e.moveCursor(-1)
+	case "down", "j":
+		e.moveCursor(1)
+	}
+
+	return e, nil
+}
+
+// moveCursor moves between changed lines and keeps the cursor visible
+func (e *InteractivePatchEditor) moveCursor(delta int) {
+	e.cursor += delta
+	if e.cursor < 0 {
+		e.cursor = 0
+	}
+	if e.cursor >= len(e.changes) {
+		e.cursor = len(e.changes) - 1
+	}
+
+	row := e.changes[e.cursor]
+	visible := e.visibleRows()
+	if row < e.offset {
+		e.offset = row
+	} else if row >= e.offset+visible {
+		e.offset = row - visible + 1
+	}
+}
+
+// visibleRows returns how many diff rows fit in the editor
+func (e *InteractivePatchEditor) visibleRows() int {
+	// Reserve space for border, padding, title and footer
+	rows := e.height - 8
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// Patch returns the diff with rejected lines removed. A rejected addition is
+// dropped and a rejected removal is kept as context, so the original line stays.
+func (e *InteractivePatchEditor) Patch() *engine.Diff {
+	result := &engine.Diff{FilePath: e.diff.FilePath}
+
+	idx := 0
+	for _, hunk := range e.diff.Hunks {
+		filtered := &engine.DiffHunk{
+			OriginalStart: hunk.OriginalStart,
+			ModifiedStart: hunk.ModifiedStart,
+			Lines:         make([]*engine.DiffLine, 0, len(hunk.Lines)),
+		}
+
+		for _, line := range hunk.Lines {
+			lineType := line.Type
+			if e.rejected[idx] {
+				if line.Type == engine.DiffLineAdded {
+					idx++
+					continue
+				}
+				lineType = engine.DiffLineContext
+			}
+			idx++
+
+			filtered.Lines = append(filtered.Lines, &engine.DiffLine{
+				Type:    lineType,
+				Content: line.Content,
+				LineNum: line.LineNum,
+			})
+
+			if lineType != engine.DiffLineAdded {
+				filtered.OriginalLines++
+			}
+			if lineType != engine.DiffLineRemoved {
+				filtered.ModifiedLines++
+			}
+		}
+
+		result.Hunks = append(result.Hunks, filtered)
+	}
+
+	return result
+}
+
+// View renders the split view: original on the left, proposed on the right
+func (e *InteractivePatchEditor) View() string {
+	modalStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ColorPrimaryRed)).
+		Background(lipgloss.Color(theme.ColorBackground)).
+		Foreground(lipgloss.Color(theme.ColorTextPrimary)).
+		Padding(1, 2).
+		Width(e.width).
+		Height(e.height)
+
+	var content strings.Builder
+
+	accepted := len(e.changes) - len(e.rejected)
+	title := fmt.Sprintf("✂ Interactive Patch: %s (%d/%d lines accepted)",
+		e.diff.FilePath, accepted, len(e.changes))
+	content.WriteString(theme.HighlightStyle.Render(title))
+	content.WriteString("\n\n")
+
+	// Marker column plus two halves separated by a divider
+	colWidth := (e.width - 4 - 4 - 3) / 2
+	if colWidth < 10 {
+		colWidth = 10
+	}
+
+	header := fmt.Sprintf("     %s │ %s",
+		padRight("Original", colWidth), padRight("Proposed", colWidth))
+	content.WriteString(theme.MutedStyle.Render(header))
+	content.WriteString("\n")
+
+	end := e.offset + e.visibleRows()
+	if end > len(e.lines) {
+		end = len(e.lines)
+	}
+
+	current := -1
+	if len(e.changes) > 0 {
+		current = e.changes[e.cursor]
+	}
+
+	for i := e.offset; i < end; i++ {
+		content.WriteString(e.renderRow(i, i == current, colWidth))
+		content.WriteString("\n")
+	}
+
+	if len(e.changes) == 0 {
+		content.WriteString(theme.MutedStyle.Render("  (No changes in this patch)"))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	footer := theme.MutedStyle.Render("y: accept line | n: reject line | ↑/↓: move | enter: apply | esc: cancel")
+	content.WriteString(footer)
+
+	return modalStyle.Render(content.String())
+}
+
+// renderRow renders a single diff line in the split view
+func (e *InteractivePatchEditor) renderRow(idx int, selected bool, colWidth int) string {
+	line := e.lines[idx]
+	text := truncateLine(line.Content, colWidth)
+
+	marker := "   "
+	if line.Type != engine.DiffLineContext {
+		if e.rejected[idx] {
+			marker = "[✗]"
+		} else {
+			marker = "[✓]"
+		}
+	}
+
+	var left, right string
+	switch line.Type {
+	case engine.DiffLineRemoved:
+		left = padRight(text, colWidth)
+		right = padRight("", colWidth)
+		if e.rejected[idx] {
+			// Rejected removal keeps the original line on both sides
+			right = padRight(text, colWidth)
+		} else {
+			left = theme.ErrorStyle.Render(left)
+		}
+	case engine.DiffLineAdded:
+		left = padRight("", colWidth)
+		right = padRight(text, colWidth)
+		if e.rejected[idx] {
+			right = theme.MutedStyle.Render(right)
+		} else {
+			right = theme.SuccessStyle.Render(right)
+		}
+	default:
+		left = padRight(text, colWidth)
+		right = padRight(text, colWidth)
+	}
+
+	row := fmt.Sprintf("%s %s │ %s", marker, left, right)
+	if selected {
+		return theme.HighlightStyle.Render("▶") + row
+	}
+	return " " + row
+}
+
+// truncateLine shortens a line to fit in width, expanding tabs
+func truncateLine(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s
+}
+
+// padRight pads s with spaces to width
+func padRight(s string, width int) string {
+	n := len([]rune(s))
+	if n >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-n)
+}
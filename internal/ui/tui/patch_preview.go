@@ -49,7 +49,7 @@ func (m *PatchPreviewModal) View() string {
 
 	// Style for diff
 	diffStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("#0d1117")). // Darker background for code
+		Background(lipgloss.Color(theme.ColorCodeBackground)).
 		Foreground(lipgloss.Color(theme.ColorInfo)).
 		Padding(1, 2).
 		Width(m.width - 8)
@@ -58,7 +58,7 @@ func (m *PatchPreviewModal) View() string {
 
 	// Footer
 	content.WriteString("\n\n")
-	footer := theme.MutedStyle.Render("Press 'a' to apply | Press 'q' to close")
+	footer := theme.MutedStyle.Render("Press 'a' to apply | 'y' to copy | 'q' to close")
 	content.WriteString(footer)
 
 	return modalStyle.Render(content.String())